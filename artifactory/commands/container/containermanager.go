@@ -6,6 +6,7 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils/container"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
 )
 
 type ContainerManagerCommand struct {
@@ -14,6 +15,8 @@ type ContainerManagerCommand struct {
 	buildConfiguration *utils.BuildConfiguration
 	serverDetails      *config.ServerDetails
 	skipLogin          bool
+	multiPlatform      bool
+	ociLayoutDir       string
 }
 
 func (cmc *ContainerManagerCommand) ImageTag() string {
@@ -58,6 +61,43 @@ func (cmc *ContainerManagerCommand) SetSkipLogin(skipLogin bool) *ContainerManag
 	return cmc
 }
 
+func (cmc *ContainerManagerCommand) IsMultiPlatform() bool {
+	return cmc.multiPlatform
+}
+
+// When set, the build-info records the image's fat manifest and all of its platforms, instead of the local image only.
+func (cmc *ContainerManagerCommand) SetMultiPlatform(multiPlatform bool) *ContainerManagerCommand {
+	cmc.multiPlatform = multiPlatform
+	return cmc
+}
+
+// Create a build-info builder for the image, according to the multi-platform setting.
+func (cmc *ContainerManagerCommand) newBuildInfoBuilder(image *container.Image, serviceManager artifactory.ArtifactoryServicesManager, commandType container.CommandType, cm container.ContainerManager) (container.Builder, error) {
+	buildName, buildNumber, project := cmc.buildConfiguration.BuildName, cmc.buildConfiguration.BuildNumber, cmc.buildConfiguration.Project
+	if cmc.multiPlatform {
+		return container.NewMultiPlatformBuildInfoBuilder(image, cmc.repo, buildName, buildNumber, project, serviceManager, commandType, cm)
+	}
+	return container.NewBuildInfoBuilder(image, cmc.repo, buildName, buildNumber, project, serviceManager, commandType, cm)
+}
+
+// The OCI image layout directory, used by the registry client container manager to read pushed images from and write pulled images to.
+func (cmc *ContainerManagerCommand) SetOciLayoutDir(ociLayoutDir string) *ContainerManagerCommand {
+	cmc.ociLayoutDir = ociLayoutDir
+	return cmc
+}
+
+// Create the container manager. The registry client works directly against the Artifactory repository and therefore requires the server details.
+func (cmc *ContainerManagerCommand) newContainerManager(serverDetails *config.ServerDetails, containerManagerType container.ContainerManagerType) (container.ContainerManager, error) {
+	if containerManagerType != container.RegistryClient {
+		return container.NewManager(containerManagerType), nil
+	}
+	serviceManager, err := utils.CreateServiceManager(serverDetails, -1, false)
+	if err != nil {
+		return nil, err
+	}
+	return container.NewRegistryClientManager(serviceManager, cmc.repo, cmc.ociLayoutDir), nil
+}
+
 func (cmc *ContainerManagerCommand) ServerDetails() *config.ServerDetails {
 	return cmc.serverDetails
 }
@@ -68,7 +108,8 @@ func (cmc *ContainerManagerCommand) SetServerDetails(serverDetails *config.Serve
 }
 
 func (cmc *ContainerManagerCommand) PerformLogin(serverDetails *config.ServerDetails, containerManagerType container.ContainerManagerType) error {
-	if !cmc.skipLogin {
+	// Only container managers operated through a CLI hold a login session.
+	if !cmc.skipLogin && containerManagerType.IsCli() {
 		loginConfig := &container.ContainerManagerLoginConfig{ServerDetails: serverDetails}
 		return container.ContainerManagerLogin(cmc.imageTag, loginConfig, containerManagerType)
 	}
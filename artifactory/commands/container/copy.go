@@ -0,0 +1,164 @@
+package container
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils/container"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Copy an image tag, with all of its platforms, between Docker repositories.
+// The target repository may reside on a different server. The copied image can be added to a build.
+type ContainerCopyCommand struct {
+	sourceServerDetails *config.ServerDetails
+	targetServerDetails *config.ServerDetails
+	sourceRepo          string
+	targetRepo          string
+	sourceImage         string
+	sourceTag           string
+	targetImage         string
+	targetTag           string
+	buildConfiguration  *utils.BuildConfiguration
+}
+
+func NewContainerCopyCommand() *ContainerCopyCommand {
+	return &ContainerCopyCommand{}
+}
+
+func (ccc *ContainerCopyCommand) SetSourceServerDetails(serverDetails *config.ServerDetails) *ContainerCopyCommand {
+	ccc.sourceServerDetails = serverDetails
+	return ccc
+}
+
+// If not set, the source server is used.
+func (ccc *ContainerCopyCommand) SetTargetServerDetails(serverDetails *config.ServerDetails) *ContainerCopyCommand {
+	ccc.targetServerDetails = serverDetails
+	return ccc
+}
+
+func (ccc *ContainerCopyCommand) SetSourceRepo(sourceRepo string) *ContainerCopyCommand {
+	ccc.sourceRepo = sourceRepo
+	return ccc
+}
+
+func (ccc *ContainerCopyCommand) SetTargetRepo(targetRepo string) *ContainerCopyCommand {
+	ccc.targetRepo = targetRepo
+	return ccc
+}
+
+// The name of the source image, e.g. "hello-world".
+func (ccc *ContainerCopyCommand) SetSourceImage(sourceImage string) *ContainerCopyCommand {
+	ccc.sourceImage = sourceImage
+	return ccc
+}
+
+// If not set, the "latest" tag is copied.
+func (ccc *ContainerCopyCommand) SetSourceTag(sourceTag string) *ContainerCopyCommand {
+	ccc.sourceTag = sourceTag
+	return ccc
+}
+
+// If not set, the source image name is used.
+func (ccc *ContainerCopyCommand) SetTargetImage(targetImage string) *ContainerCopyCommand {
+	ccc.targetImage = targetImage
+	return ccc
+}
+
+// If not set, the source tag is used.
+func (ccc *ContainerCopyCommand) SetTargetTag(targetTag string) *ContainerCopyCommand {
+	ccc.targetTag = targetTag
+	return ccc
+}
+
+func (ccc *ContainerCopyCommand) SetBuildConfiguration(buildConfiguration *utils.BuildConfiguration) *ContainerCopyCommand {
+	ccc.buildConfiguration = buildConfiguration
+	return ccc
+}
+
+func (ccc *ContainerCopyCommand) Run() error {
+	if err := ccc.setDefaults(); err != nil {
+		return err
+	}
+	sourceServiceManager, err := utils.CreateServiceManager(ccc.sourceServerDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	targetServiceManager, err := utils.CreateServiceManager(ccc.targetServerDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	sourceImage, err := toImage(ccc.sourceServerDetails, ccc.sourceImage, ccc.sourceTag)
+	if err != nil {
+		return err
+	}
+	targetImage, err := toImage(ccc.targetServerDetails, ccc.targetImage, ccc.targetTag)
+	if err != nil {
+		return err
+	}
+	digest, err := container.NewImageCopier(sourceServiceManager, ccc.sourceRepo, targetServiceManager, ccc.targetRepo).Copy(sourceImage, targetImage)
+	if err != nil {
+		return err
+	}
+	log.Info("Image copied successfully. Digest: " + digest)
+	if ccc.buildConfiguration == nil || ccc.buildConfiguration.BuildName == "" || ccc.buildConfiguration.BuildNumber == "" {
+		return nil
+	}
+	buildName, buildNumber, project := ccc.buildConfiguration.BuildName, ccc.buildConfiguration.BuildNumber, ccc.buildConfiguration.Project
+	if err = utils.SaveBuildGeneralDetails(buildName, buildNumber, project); err != nil {
+		return err
+	}
+	cm := container.NewRegistryClientManager(targetServiceManager, ccc.targetRepo, "")
+	builder, err := container.NewMultiPlatformBuildInfoBuilder(targetImage, ccc.targetRepo, buildName, buildNumber, project, targetServiceManager, container.Push, cm)
+	if err != nil {
+		return err
+	}
+	buildInfo, err := builder.Build(ccc.buildConfiguration.Module)
+	if err != nil {
+		return err
+	}
+	return utils.SaveBuildInfo(buildName, buildNumber, project, buildInfo)
+}
+
+func (ccc *ContainerCopyCommand) setDefaults() error {
+	if ccc.sourceServerDetails == nil || ccc.sourceRepo == "" || ccc.targetRepo == "" || ccc.sourceImage == "" {
+		return errorutils.CheckError(errors.New("the source server, source repository, target repository and source image are mandatory"))
+	}
+	if ccc.targetServerDetails == nil {
+		ccc.targetServerDetails = ccc.sourceServerDetails
+	}
+	if ccc.sourceTag == "" {
+		ccc.sourceTag = "latest"
+	}
+	if ccc.targetImage == "" {
+		ccc.targetImage = ccc.sourceImage
+	}
+	if ccc.targetTag == "" {
+		ccc.targetTag = ccc.sourceTag
+	}
+	if ccc.sourceServerDetails.ArtifactoryUrl == ccc.targetServerDetails.ArtifactoryUrl && ccc.sourceRepo == ccc.targetRepo &&
+		ccc.sourceImage == ccc.targetImage && ccc.sourceTag == ccc.targetTag {
+		return errorutils.CheckError(errors.New("the source and target images are identical"))
+	}
+	return nil
+}
+
+// Create an image, using the server's host as the registry domain, e.g. 'acme.jfrog.io/hello-world:1.0'.
+func toImage(serverDetails *config.ServerDetails, imageName, tag string) (*container.Image, error) {
+	artifactoryUrl, err := url.Parse(serverDetails.ArtifactoryUrl)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return container.NewImage(artifactoryUrl.Host + "/" + imageName + ":" + tag), nil
+}
+
+func (ccc *ContainerCopyCommand) CommandName() string {
+	return "rt_container_copy"
+}
+
+func (ccc *ContainerCopyCommand) ServerDetails() (*config.ServerDetails, error) {
+	return ccc.targetServerDetails, nil
+}
@@ -0,0 +1,117 @@
+package container
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils/container"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// A minimal in-memory Docker registry, serving the Artifactory registry API of all the repositories.
+type registryStandIn struct {
+	mutex sync.Mutex
+	// Maps '<repo>/blobs/<digest>' and '<repo>/<image>/manifests/<reference>' to their content.
+	content map[string][]byte
+}
+
+func (registry *registryStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	body, _ := ioutil.ReadAll(r.Body)
+	// For example: /artifactory/api/docker/docker-local/v2/hello-world/manifests/1.0
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/artifactory/api/docker/"), "/v2/", 2)
+	if len(parts) != 2 {
+		registry.serveUpload(w, r, body)
+		return
+	}
+	repo, requestPath := parts[0], parts[1]
+	key := repo + "/" + requestPath
+	switch {
+	case strings.HasSuffix(requestPath, "/blobs/uploads/") && r.Method == http.MethodPost:
+		w.Header().Set("Location", "/upload/"+repo)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	case strings.Contains(requestPath, "/blobs/"):
+		key = repo + "/blobs/" + requestPath[strings.LastIndex(requestPath, "/")+1:]
+	case r.Method == http.MethodPut:
+		registry.content[key] = body
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	content, exists := registry.content[key]
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if strings.Contains(requestPath, "/manifests/") {
+		w.Header().Set("Content-Type", container.DockerManifestMediaType)
+	}
+	_, _ = w.Write(content)
+}
+
+// Complete a blob upload, started by a POST request to the repository's uploads URL.
+func (registry *registryStandIn) serveUpload(w http.ResponseWriter, r *http.Request, body []byte) {
+	digest := r.URL.Query().Get("digest")
+	if !strings.HasPrefix(r.URL.Path, "/upload/") || r.Method != http.MethodPut || calcDigest(body) != digest {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	registry.content[strings.TrimPrefix(r.URL.Path, "/upload/")+"/blobs/"+digest] = body
+	w.WriteHeader(http.StatusCreated)
+}
+
+func calcDigest(content []byte) string {
+	hash := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+func TestContainerCopyCommand(t *testing.T) {
+	registry := &registryStandIn{content: map[string][]byte{}}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	serverDetails := &config.ServerDetails{ArtifactoryUrl: server.URL + "/artifactory/", User: "admin", Password: "password"}
+
+	configContent := []byte(`{"os":"linux","architecture":"amd64"}`)
+	layerContent := []byte("layer")
+	manifestContent, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     container.DockerManifestMediaType,
+		"config":        map[string]interface{}{"digest": calcDigest(configContent), "size": len(configContent)},
+		"layers":        []interface{}{map[string]interface{}{"digest": calcDigest(layerContent), "size": len(layerContent)}},
+	})
+	assert.NoError(t, err)
+	registry.content["docker-local/blobs/"+calcDigest(configContent)] = configContent
+	registry.content["docker-local/blobs/"+calcDigest(layerContent)] = layerContent
+	registry.content["docker-local/hello-world/manifests/1.0"] = manifestContent
+
+	copyCommand := NewContainerCopyCommand().SetSourceServerDetails(serverDetails).SetSourceRepo("docker-local").
+		SetTargetRepo("docker-prod").SetSourceImage("hello-world").SetSourceTag("1.0").SetTargetTag("2.0")
+	assert.NoError(t, copyCommand.Run())
+	assert.Equal(t, manifestContent, registry.content["docker-prod/hello-world/manifests/2.0"])
+	assert.Equal(t, configContent, registry.content["docker-prod/blobs/"+calcDigest(configContent)])
+	assert.Equal(t, layerContent, registry.content["docker-prod/blobs/"+calcDigest(layerContent)])
+
+	// A missing source image fails the copy.
+	copyCommand = NewContainerCopyCommand().SetSourceServerDetails(serverDetails).SetSourceRepo("docker-local").
+		SetTargetRepo("docker-prod").SetSourceImage("missing")
+	assert.Error(t, copyCommand.Run())
+}
+
+func TestContainerCopyCommandIdenticalImages(t *testing.T) {
+	serverDetails := &config.ServerDetails{ArtifactoryUrl: "http://localhost:8081/artifactory/"}
+	copyCommand := NewContainerCopyCommand().SetSourceServerDetails(serverDetails).SetSourceRepo("docker-local").
+		SetTargetRepo("docker-local").SetSourceImage("hello-world")
+	assert.EqualError(t, copyCommand.Run(), "the source and target images are identical")
+
+	// The source server, repositories and image are mandatory.
+	assert.Error(t, NewContainerCopyCommand().SetSourceServerDetails(serverDetails).SetSourceRepo("docker-local").Run())
+}
@@ -33,7 +33,10 @@ func (pc *PullCommand) Run() error {
 		return err
 	}
 	// Perform pull.
-	cm := container.NewManager(pc.containerManagerType)
+	cm, err := pc.newContainerManager(serverDetails, pc.containerManagerType)
+	if err != nil {
+		return err
+	}
 	image := container.NewImage(pc.imageTag)
 	err = cm.Pull(image)
 	if err != nil {
@@ -53,7 +56,7 @@ func (pc *PullCommand) Run() error {
 	if err != nil {
 		return err
 	}
-	builder, err := container.NewBuildInfoBuilder(image, pc.Repo(), buildName, buildNumber, project, serviceManager, container.Pull, cm)
+	builder, err := pc.newBuildInfoBuilder(image, serviceManager, container.Pull, cm)
 	if err != nil {
 		return err
 	}
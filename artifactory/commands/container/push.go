@@ -67,7 +67,10 @@ func (pc *PushCommand) Run() error {
 		return err
 	}
 	// Perform push.
-	cm := container.NewManager(pc.containerManagerType)
+	cm, err := pc.newContainerManager(serverDetails, pc.containerManagerType)
+	if err != nil {
+		return err
+	}
 	image := container.NewImage(pc.imageTag)
 	err = cm.Push(image)
 	if err != nil {
@@ -85,7 +88,7 @@ func (pc *PushCommand) Run() error {
 	if err != nil {
 		return err
 	}
-	builder, err := container.NewBuildInfoBuilder(image, pc.Repo(), pc.BuildConfiguration().BuildName, pc.BuildConfiguration().BuildNumber, pc.BuildConfiguration().Project, serviceManager, container.Push, cm)
+	builder, err := pc.newBuildInfoBuilder(image, serviceManager, container.Push, cm)
 	if err != nil {
 		return err
 	}
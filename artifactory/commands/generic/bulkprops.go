@@ -0,0 +1,520 @@
+package generic
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/jfrog/gofrog/parallel"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	defaultBulkPropsThreads = 3
+
+	PropsMappingSucceeded = "succeeded"
+	PropsMappingFailed    = "failed"
+	PropsMappingSkipped   = "skipped"
+)
+
+// The properties to set on and remove from an artifact.
+// In a CSV mapping file, the columns are 'path', 'set' and 'delete'. The properties to set are in the format of
+// the --props option ('key1=value1;key2=value2,value3') and the properties to delete are separated by commas.
+type PropsMapping struct {
+	Path   string              `json:"path"`
+	Set    map[string][]string `json:"set,omitempty"`
+	Delete []string            `json:"delete,omitempty"`
+}
+
+// The outcome of applying a mapping to an artifact.
+type PropsMappingResult struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// The changes the mapping would make, on dry-run.
+	Changes []PropChange `json:"changes,omitempty"`
+}
+
+// A change of a property. Old is empty if the property does not exist, and New is empty if the property is deleted.
+type PropChange struct {
+	Key string   `json:"key"`
+	Old []string `json:"old,omitempty"`
+	New []string `json:"new,omitempty"`
+}
+
+// Set and delete different properties on each artifact, according to a CSV or JSON mapping file.
+// Before an artifact is modified, its previous values of the modified properties are recorded in a rollback file.
+// The rollback file is itself a JSON mapping file, which restores the properties when provided to this command.
+// It is created before any artifact is modified and a line is appended to it as each mapping is applied,
+// so that it is complete even if the command is interrupted.
+type BulkPropsCommand struct {
+	GenericCommand
+	mappingFilePath  string
+	rollbackFilePath string
+	threads          int
+	results          []PropsMappingResult
+}
+
+func NewBulkPropsCommand() *BulkPropsCommand {
+	return &BulkPropsCommand{GenericCommand: *NewGenericCommand(), threads: defaultBulkPropsThreads}
+}
+
+func (bpc *BulkPropsCommand) SetMappingFilePath(mappingFilePath string) *BulkPropsCommand {
+	bpc.mappingFilePath = mappingFilePath
+	return bpc
+}
+
+// The file to write the previous values of the properties to. Required, unless running in dry-run mode.
+func (bpc *BulkPropsCommand) SetRollbackFilePath(rollbackFilePath string) *BulkPropsCommand {
+	bpc.rollbackFilePath = rollbackFilePath
+	return bpc
+}
+
+func (bpc *BulkPropsCommand) SetThreads(threads int) *BulkPropsCommand {
+	bpc.threads = threads
+	return bpc
+}
+
+// The results of the last run, in the order of the mapping file.
+func (bpc *BulkPropsCommand) Results() []PropsMappingResult {
+	return bpc.results
+}
+
+func (bpc *BulkPropsCommand) CommandName() string {
+	return "rt_bulk_properties"
+}
+
+func (bpc *BulkPropsCommand) Run() error {
+	if !bpc.dryRun && bpc.rollbackFilePath == "" {
+		return errorutils.CheckError(errors.New("a rollback file is required"))
+	}
+	mappings, err := ReadPropsMappings(bpc.mappingFilePath)
+	if err != nil {
+		return err
+	}
+	servicesManager, err := utils.CreateServiceManager(bpc.serverDetails, bpc.retries, false)
+	if err != nil {
+		return err
+	}
+	threads := bpc.threads
+	if threads <= 0 {
+		threads = defaultBulkPropsThreads
+	}
+	bpc.results = make([]PropsMappingResult, len(mappings))
+	var rollbackFile *propsRollbackFile
+	if !bpc.dryRun {
+		if rollbackFile, err = newPropsRollbackFile(bpc.rollbackFilePath); err != nil {
+			return err
+		}
+	}
+	producerConsumer := parallel.NewBounedRunner(threads, false)
+	go func() {
+		defer producerConsumer.Done()
+		for i := range mappings {
+			index := i
+			producerConsumer.AddTask(func(int) error {
+				bpc.results[index] = bpc.apply(servicesManager, &mappings[index], rollbackFile.record)
+				return nil
+			})
+		}
+	}()
+	producerConsumer.Run()
+	if rollbackFile != nil {
+		if err = rollbackFile.close(); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, result := range bpc.results {
+		if result.Status == PropsMappingFailed {
+			failed++
+		}
+	}
+	bpc.result.SetSuccessCount(len(mappings) - failed)
+	bpc.result.SetFailCount(failed)
+	log.Output(formatPropsMappingResults(bpc.results))
+	if !bpc.dryRun {
+		log.Info("The previous properties were saved to", bpc.rollbackFilePath)
+	}
+	if failed > 0 {
+		return errorutils.CheckError(fmt.Errorf("failed to update the properties of %d out of %d artifacts", failed, len(mappings)))
+	}
+	return nil
+}
+
+// Apply the mapping to its artifact, and return the result.
+// The mapping which reverts it is recorded before the artifact is modified, so that partial changes can be reverted too.
+// On dry-run, the artifact is not modified, and the result lists the changes the mapping would make.
+func (bpc *BulkPropsCommand) apply(servicesManager artifactory.ArtifactoryServicesManager, mapping *PropsMapping, recordRollback func(*PropsMapping) error) PropsMappingResult {
+	result := PropsMappingResult{Path: mapping.Path, Status: PropsMappingSucceeded}
+	fail := func(err error) PropsMappingResult {
+		log.Error(fmt.Sprintf("Failed to update the properties of %s: %s", mapping.Path, err.Error()))
+		result.Status, result.Error = PropsMappingFailed, err.Error()
+		return result
+	}
+	previous, err := getItemProperties(servicesManager, mapping.Path)
+	if err != nil {
+		return fail(err)
+	}
+	if bpc.dryRun {
+		result.Status = PropsMappingSkipped
+		result.Changes = createPropChanges(mapping, previous)
+		return result
+	}
+	if err = recordRollback(createPropsRollback(mapping, previous)); err != nil {
+		return fail(err)
+	}
+	// Called if the first request fails, in which case the artifact was not modified and there is nothing to revert.
+	discardRollback := func() {
+		if err := recordRollback(&PropsMapping{Path: mapping.Path}); err != nil {
+			log.Error(err.Error())
+		}
+	}
+	if len(mapping.Set) > 0 {
+		if err = updateItemProperties(servicesManager.SetProps, mapping.Path, formatProps(mapping.Set)); err != nil {
+			discardRollback()
+			return fail(err)
+		}
+	}
+	if len(mapping.Delete) > 0 {
+		if err = updateItemProperties(servicesManager.DeleteProps, mapping.Path, strings.Join(mapping.Delete, ",")); err != nil {
+			if len(mapping.Set) == 0 {
+				discardRollback()
+			}
+			return fail(err)
+		}
+	}
+	log.Debug("Updated the properties of", mapping.Path)
+	return result
+}
+
+// Return the changes of the properties of an artifact, given its properties before the mapping is applied, sorted by key.
+// Deleting a property which does not exist is not a change.
+func createPropChanges(mapping *PropsMapping, previous map[string][]string) []PropChange {
+	var changes []PropChange
+	for key, values := range mapping.Set {
+		changes = append(changes, PropChange{Key: key, Old: previous[key], New: values})
+	}
+	for _, key := range mapping.Delete {
+		if values, ok := previous[key]; ok {
+			changes = append(changes, PropChange{Key: key, Old: values})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// Return the mapping which restores the properties of an artifact, given its properties before the mapping is applied.
+// Properties which are set and did not exist before are deleted, and the previous values of the other properties are set.
+func createPropsRollback(mapping *PropsMapping, previous map[string][]string) *PropsMapping {
+	rollback := &PropsMapping{Path: mapping.Path, Set: make(map[string][]string)}
+	restore := func(key string) {
+		if values, ok := previous[key]; ok {
+			rollback.Set[key] = values
+		}
+	}
+	for key := range mapping.Set {
+		if _, ok := previous[key]; !ok {
+			rollback.Delete = append(rollback.Delete, key)
+		}
+		restore(key)
+	}
+	for _, key := range mapping.Delete {
+		restore(key)
+	}
+	sort.Strings(rollback.Delete)
+	if len(rollback.Set) == 0 {
+		rollback.Set = nil
+	}
+	return rollback
+}
+
+// Return the properties of an artifact. Artifactory responds with 404 for artifacts without properties.
+func getItemProperties(servicesManager artifactory.ArtifactoryServicesManager, itemPath string) (map[string][]string, error) {
+	artDetails := servicesManager.GetConfig().GetServiceDetails()
+	requestUrl, err := clientutils.BuildArtifactoryUrl(artDetails.GetUrl(), path.Join("api", "storage", itemPath), make(map[string]string))
+	if err != nil {
+		return nil, err
+	}
+	httpClientDetails := artDetails.CreateHttpClientDetails()
+	resp, body, _, err := servicesManager.Client().SendGet(requestUrl+"?properties", true, &httpClientDetails)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return map[string][]string{}, nil
+	}
+	if err = errorutils.CheckResponseStatus(resp, http.StatusOK); err != nil {
+		return nil, errorutils.CheckError(errors.New(err.Error() + " " + string(body)))
+	}
+	response := struct {
+		Properties map[string][]string `json:"properties"`
+	}{}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if response.Properties == nil {
+		response.Properties = map[string][]string{}
+	}
+	return response.Properties, nil
+}
+
+// Set or delete the properties of a single artifact, using the given function of the services manager.
+func updateItemProperties(update func(services.PropsParams) (int, error), itemPath, props string) error {
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	if err != nil {
+		return err
+	}
+	repo, itemRelativePath := splitRepoPath(itemPath)
+	writer.Write(clientutils.ResultItem{Repo: repo, Path: path.Dir(itemRelativePath), Name: path.Base(itemRelativePath), Type: "file"})
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	reader := content.NewContentReader(writer.GetFilePath(), content.DefaultKey)
+	defer reader.Close()
+	propsParams := services.NewPropsParams()
+	propsParams.Reader = reader
+	propsParams.Props = props
+	_, err = update(propsParams)
+	return err
+}
+
+// Format the properties in the format of the --props option, sorted by key. Commas in values are escaped.
+func formatProps(props map[string][]string) string {
+	var keys []string
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var formatted []string
+	for _, key := range keys {
+		var values []string
+		for _, value := range props[key] {
+			values = append(values, strings.ReplaceAll(value, ",", "\\,"))
+		}
+		formatted = append(formatted, key+"="+strings.Join(values, ","))
+	}
+	return strings.Join(formatted, ";")
+}
+
+// Read a mapping file. Files with the .csv extension are read as CSV, and other files as a JSON array of mappings.
+func ReadPropsMappings(mappingFilePath string) ([]PropsMapping, error) {
+	if mappingFilePath == "" {
+		return nil, errorutils.CheckError(errors.New("a mapping file is required"))
+	}
+	file, err := os.Open(mappingFilePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	defer file.Close()
+	var mappings []PropsMapping
+	if strings.EqualFold(filepath.Ext(mappingFilePath), ".csv") {
+		mappings, err = parseCsvPropsMappings(file)
+	} else {
+		mappings, err = parseJsonPropsMappings(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mappings, validatePropsMappings(mappings)
+}
+
+// A JSON mapping file is either an array of mappings, or a sequence of mappings such as a rollback file.
+// In a sequence, a later mapping of a path replaces the earlier one, and a mapping without properties discards it.
+func parseJsonPropsMappings(reader io.Reader) ([]PropsMapping, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var mappings []PropsMapping
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] == '[' {
+		if err = json.Unmarshal(data, &mappings); err != nil {
+			return nil, errorutils.CheckError(fmt.Errorf("invalid mapping file: %s", err.Error()))
+		}
+		return mappings, nil
+	}
+	indexes := make(map[string]int)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var mapping PropsMapping
+		if err = decoder.Decode(&mapping); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errorutils.CheckError(fmt.Errorf("invalid mapping file: %s", err.Error()))
+		}
+		if index, ok := indexes[mapping.Path]; ok {
+			mappings[index] = mapping
+			continue
+		}
+		indexes[mapping.Path] = len(mappings)
+		mappings = append(mappings, mapping)
+	}
+	var effective []PropsMapping
+	for _, mapping := range mappings {
+		if len(mapping.Set) > 0 || len(mapping.Delete) > 0 {
+			effective = append(effective, mapping)
+		}
+	}
+	return effective, nil
+}
+
+func parseCsvPropsMappings(reader io.Reader) ([]PropsMapping, error) {
+	csvReader := csv.NewReader(reader)
+	// Trailing empty columns may be omitted.
+	csvReader.FieldsPerRecord = -1
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("invalid mapping file: %s", err.Error()))
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := columns["path"]; !ok {
+		return nil, errorutils.CheckError(errors.New("invalid mapping file: the CSV header must include a 'path' column"))
+	}
+	value := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	var mappings []PropsMapping
+	for line, record := range records[1:] {
+		mapping := PropsMapping{Path: value(record, "path")}
+		if set := value(record, "set"); set != "" {
+			props, err := clientutils.ParseProperties(set)
+			if err != nil {
+				return nil, errorutils.CheckError(fmt.Errorf("invalid mapping file, line %d: %s", line+2, err.Error()))
+			}
+			mapping.Set = props.ToMap()
+		}
+		for _, key := range strings.Split(value(record, "delete"), ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				mapping.Delete = append(mapping.Delete, key)
+			}
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+func validatePropsMappings(mappings []PropsMapping) error {
+	if len(mappings) == 0 {
+		return errorutils.CheckError(errors.New("the mapping file does not include any mappings"))
+	}
+	paths := make(map[string]bool)
+	for i, mapping := range mappings {
+		if mapping.Path == "" {
+			return errorutils.CheckError(fmt.Errorf("mapping %d: a path is required", i+1))
+		}
+		if paths[mapping.Path] {
+			return errorutils.CheckError(fmt.Errorf("mapping %d: the path '%s' appears more than once", i+1, mapping.Path))
+		}
+		paths[mapping.Path] = true
+		if len(mapping.Set) == 0 && len(mapping.Delete) == 0 {
+			return errorutils.CheckError(fmt.Errorf("mapping %d: no properties to set or delete on '%s'", i+1, mapping.Path))
+		}
+		// The properties are passed in the format of the --props option, which cannot express these characters.
+		for key, values := range mapping.Set {
+			if strings.ContainsAny(key, ",;=") {
+				return errorutils.CheckError(fmt.Errorf("mapping %d: the property key '%s' on '%s' includes ',', ';' or '='", i+1, key, mapping.Path))
+			}
+			for _, value := range values {
+				if strings.ContainsAny(value, ";=") {
+					return errorutils.CheckError(fmt.Errorf("mapping %d: the value of the property '%s' on '%s' includes ';' or '='", i+1, key, mapping.Path))
+				}
+			}
+		}
+		for _, key := range mapping.Delete {
+			if strings.ContainsAny(key, ",;=") {
+				return errorutils.CheckError(fmt.Errorf("mapping %d: the property key '%s' on '%s' includes ',', ';' or '='", i+1, key, mapping.Path))
+			}
+			if _, ok := mapping.Set[key]; ok {
+				return errorutils.CheckError(fmt.Errorf("mapping %d: the property '%s' is both set and deleted on '%s'", i+1, key, mapping.Path))
+			}
+		}
+	}
+	return nil
+}
+
+// The rollback file, to which each rollback mapping is appended as a single line.
+type propsRollbackFile struct {
+	file  *os.File
+	mutex sync.Mutex
+}
+
+// Create the rollback file with no mappings, before any artifact is modified.
+func newPropsRollbackFile(path string) (*propsRollbackFile, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return &propsRollbackFile{file: file}, nil
+}
+
+// Record the rollback mapping of an artifact. A mapping without properties discards the artifact's previous mapping.
+func (rf *propsRollbackFile) record(rollback *PropsMapping) error {
+	data, err := json.Marshal(rollback)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+	_, err = rf.file.Write(append(data, '\n'))
+	return errorutils.CheckError(err)
+}
+
+func (rf *propsRollbackFile) close() error {
+	return errorutils.CheckError(rf.file.Close())
+}
+
+func formatPropsMappingResults(results []PropsMappingResult) string {
+	buffer := &bytes.Buffer{}
+	writer := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "PATH\tSTATUS\tERROR\tCHANGES")
+	for _, result := range results {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", result.Path, result.Status, result.Error, formatPropChanges(result.Changes))
+	}
+	writer.Flush()
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+// Format the changes as 'key: old -> new', separated by semicolons.
+func formatPropChanges(changes []PropChange) string {
+	formatValues := func(values []string, missing string) string {
+		if len(values) == 0 {
+			return missing
+		}
+		return strings.Join(values, ",")
+	}
+	var formatted []string
+	for _, change := range changes {
+		formatted = append(formatted, fmt.Sprintf("%s: %s -> %s", change.Key, formatValues(change.Old, "(none)"), formatValues(change.New, "(deleted)")))
+	}
+	return strings.Join(formatted, "; ")
+}
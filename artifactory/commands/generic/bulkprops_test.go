@@ -0,0 +1,167 @@
+package generic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Serve the item properties API from memory.
+type propsServer struct {
+	mutex sync.Mutex
+	items map[string]map[string][]string
+}
+
+func (ps *propsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	itemPath := strings.TrimPrefix(r.URL.Path, "/api/storage/")
+	props, ok := ps.items[itemPath]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if len(props) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data, _ := json.Marshal(map[string]interface{}{"properties": props})
+		w.Write(data)
+	case http.MethodPut:
+		query, _ := url.QueryUnescape(r.URL.RawQuery)
+		for _, prop := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(query, "properties="), "&recursive=0"), ";") {
+			parts := strings.SplitN(prop, "=", 2)
+			props[parts[0]] = strings.Split(parts[1], ",")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		for _, key := range strings.Split(r.URL.Query().Get("properties"), ",") {
+			delete(props, key)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestBulkPropsWithRollback(t *testing.T) {
+	log.SetDefaultLogger()
+	server := &propsServer{items: map[string]map[string][]string{
+		"libs/a.jar": {"status": {"snapshot"}, "owner": {"team-a"}},
+		"libs/b.jar": {},
+	}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	tempDir, err := ioutil.TempDir("", "bulkprops")
+	assert.NoError(t, err)
+	mappingFile := filepath.Join(tempDir, "mapping.csv")
+	rollbackFile := filepath.Join(tempDir, "rollback.json")
+	assert.NoError(t, ioutil.WriteFile(mappingFile, []byte("path,set,delete\nlibs/a.jar,status=release;tier=gold,owner\nlibs/b.jar,status=release,\nlibs/missing.jar,status=release,\n"), 0600))
+
+	serverDetails := &config.ServerDetails{ArtifactoryUrl: ts.URL + "/"}
+	command := NewBulkPropsCommand().SetMappingFilePath(mappingFile).SetRollbackFilePath(rollbackFile)
+	command.SetServerDetails(serverDetails)
+	assert.Error(t, command.Run())
+	assert.Equal(t, []PropsMappingResult{
+		{Path: "libs/a.jar", Status: PropsMappingSucceeded},
+		{Path: "libs/b.jar", Status: PropsMappingSucceeded},
+		{Path: "libs/missing.jar", Status: PropsMappingFailed, Error: command.Results()[2].Error},
+	}, command.Results())
+	assert.Equal(t, 2, command.Result().SuccessCount())
+	assert.Equal(t, 1, command.Result().FailCount())
+	assert.Equal(t, map[string][]string{"status": {"release"}, "tier": {"gold"}}, server.items["libs/a.jar"])
+	assert.Equal(t, map[string][]string{"status": {"release"}}, server.items["libs/b.jar"])
+
+	rollbacks, err := ReadPropsMappings(rollbackFile)
+	assert.NoError(t, err)
+	// The rollback mappings are recorded in the order they are applied.
+	assert.ElementsMatch(t, []PropsMapping{
+		{Path: "libs/a.jar", Set: map[string][]string{"status": {"snapshot"}, "owner": {"team-a"}}, Delete: []string{"tier"}},
+		{Path: "libs/b.jar", Delete: []string{"status"}},
+	}, rollbacks)
+
+	// Applying the rollback file restores the previous properties.
+	command = NewBulkPropsCommand().SetMappingFilePath(rollbackFile).SetRollbackFilePath(filepath.Join(tempDir, "rollback2.json"))
+	command.SetServerDetails(serverDetails)
+	assert.NoError(t, command.Run())
+	assert.Equal(t, map[string][]string{"status": {"snapshot"}, "owner": {"team-a"}}, server.items["libs/a.jar"])
+	assert.Empty(t, server.items["libs/b.jar"])
+}
+
+func TestBulkPropsDryRun(t *testing.T) {
+	log.SetDefaultLogger()
+	server := &propsServer{items: map[string]map[string][]string{
+		"libs/a.jar": {"status": {"snapshot"}, "owner": {"team-a"}},
+	}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	tempDir, err := ioutil.TempDir("", "bulkprops")
+	assert.NoError(t, err)
+	mappingFile := filepath.Join(tempDir, "mapping.csv")
+	assert.NoError(t, ioutil.WriteFile(mappingFile, []byte("path,set,delete\nlibs/a.jar,status=release;tier=gold,\"owner,missing\"\n"), 0600))
+
+	command := NewBulkPropsCommand().SetMappingFilePath(mappingFile)
+	command.SetServerDetails(&config.ServerDetails{ArtifactoryUrl: ts.URL + "/"}).SetDryRun(true)
+	assert.NoError(t, command.Run())
+	changes := []PropChange{
+		{Key: "owner", Old: []string{"team-a"}},
+		{Key: "status", Old: []string{"snapshot"}, New: []string{"release"}},
+		{Key: "tier", New: []string{"gold"}},
+	}
+	assert.Equal(t, []PropsMappingResult{{Path: "libs/a.jar", Status: PropsMappingSkipped, Changes: changes}}, command.Results())
+	assert.Equal(t, "owner: team-a -> (deleted); status: snapshot -> release; tier: (none) -> gold", formatPropChanges(changes))
+	// Nothing is changed.
+	assert.Equal(t, map[string][]string{"status": {"snapshot"}, "owner": {"team-a"}}, server.items["libs/a.jar"])
+}
+
+func TestParsePropsMappings(t *testing.T) {
+	mappings, err := parseCsvPropsMappings(strings.NewReader("Path,Delete,Set\nlibs/a.jar,\"x,y\",\"a=1;b=2\\,3\"\nlibs/b.jar,z\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []PropsMapping{{Path: "libs/a.jar", Set: map[string][]string{"a": {"1"}, "b": {"2,3"}}, Delete: []string{"x", "y"}}, {Path: "libs/b.jar", Delete: []string{"z"}}}, mappings)
+
+	mappings, err = parseJsonPropsMappings(strings.NewReader(`[{"path": "libs/a.jar", "set": {"a": ["1", "2"]}}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []PropsMapping{{Path: "libs/a.jar", Set: map[string][]string{"a": {"1", "2"}}}}, mappings)
+
+	// In a sequence of mappings, a later mapping of a path replaces the earlier one, and an empty mapping discards it.
+	mappings, err = parseJsonPropsMappings(strings.NewReader("{\"path\":\"libs/a.jar\",\"set\":{\"a\":[\"1\"]}}\n{\"path\":\"libs/b.jar\",\"delete\":[\"b\"]}\n{\"path\":\"libs/a.jar\"}\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []PropsMapping{{Path: "libs/b.jar", Delete: []string{"b"}}}, mappings)
+
+	_, err = parseCsvPropsMappings(strings.NewReader("file,set\nlibs/a.jar,a=1\n"))
+	assert.Error(t, err)
+
+	invalid := [][]PropsMapping{
+		nil,
+		{{Set: map[string][]string{"a": {"1"}}}},
+		{{Path: "libs/a.jar"}},
+		{{Path: "libs/a.jar", Delete: []string{"a"}}, {Path: "libs/a.jar", Delete: []string{"b"}}},
+		{{Path: "libs/a.jar", Set: map[string][]string{"a": {"1"}}, Delete: []string{"a"}}},
+		{{Path: "libs/a.jar", Set: map[string][]string{"a": {"1;b=2"}}}},
+		{{Path: "libs/a.jar", Delete: []string{"a,b"}}},
+	}
+	for _, mappings := range invalid {
+		assert.Error(t, validatePropsMappings(mappings))
+	}
+}
+
+func TestFormatProps(t *testing.T) {
+	formatted := formatProps(map[string][]string{"b": {"1,2", "3"}, "a": {"x"}})
+	assert.Equal(t, "a=x;b=1\\,2,3", formatted)
+	props, err := clientutils.ParseProperties(formatted)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"a": {"x"}, "b": {"1,2", "3"}}, props.ToMap())
+}
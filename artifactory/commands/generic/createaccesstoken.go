@@ -24,6 +24,7 @@ type AccessTokenCreateCommand struct {
 	audience      string
 	groups        string
 	grantAdmin    bool
+	scope         string
 	response      *services.CreateTokenResponseData
 }
 
@@ -66,6 +67,12 @@ func (atcc *AccessTokenCreateCommand) SetGroups(groups string) *AccessTokenCreat
 	return atcc
 }
 
+// The scope of the token, such as the scope of an existing token. If set, the groups and admin privileges are ignored.
+func (atcc *AccessTokenCreateCommand) SetScope(scope string) *AccessTokenCreateCommand {
+	atcc.scope = scope
+	return atcc
+}
+
 func (atcc *AccessTokenCreateCommand) Response() ([]byte, error) {
 	content, err := json.Marshal(*atcc.response)
 	return content, errorutils.CheckError(err)
@@ -101,6 +108,10 @@ func (atcc *AccessTokenCreateCommand) getTokenParams() (tokenParams services.Cre
 	// Artifactory expects the username to be lower-cased. In case it is not,
 	// Artifactory will still accept a non lower-cased user, except for token related actions.
 	tokenParams.Username = strings.ToLower(atcc.userName)
+	if atcc.scope != "" {
+		tokenParams.Scope = atcc.scope
+		return
+	}
 	// By default we will create "user-scoped token", unless specific groups or admin-privilege-instance were specified
 	if len(atcc.groups) == 0 && !atcc.grantAdmin {
 		atcc.groups = UserScopedNotation
@@ -0,0 +1,102 @@
+package generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	rtUtils "github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// List the access tokens issued by Artifactory. Requires admin privileges.
+type AccessTokenListCommand struct {
+	serverDetails *config.ServerDetails
+	userName      string
+	tokens        []services.Token
+}
+
+func NewAccessTokenListCommand() *AccessTokenListCommand {
+	return &AccessTokenListCommand{}
+}
+
+func (atlc *AccessTokenListCommand) SetServerDetails(serverDetails *config.ServerDetails) *AccessTokenListCommand {
+	atlc.serverDetails = serverDetails
+	return atlc
+}
+
+// List only the tokens issued for this user.
+func (atlc *AccessTokenListCommand) SetUserName(userName string) *AccessTokenListCommand {
+	atlc.userName = userName
+	return atlc
+}
+
+func (atlc *AccessTokenListCommand) Tokens() []services.Token {
+	return atlc.tokens
+}
+
+func (atlc *AccessTokenListCommand) Response() ([]byte, error) {
+	content, err := json.Marshal(atlc.tokens)
+	return content, errorutils.CheckError(err)
+}
+
+// Return the tokens as a table, with their issue and expiry times in UTC.
+func (atlc *AccessTokenListCommand) Table() string {
+	buffer := &bytes.Buffer{}
+	writer := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TOKEN ID\tSUBJECT\tISSUED AT\tEXPIRY\tREFRESHABLE")
+	for _, token := range atlc.tokens {
+		expiry := "never"
+		if token.Expiry > 0 {
+			expiry = formatUnixTime(token.Expiry)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%t\n", token.TokenId, token.Subject, formatUnixTime(token.IssuedAt), expiry, token.Refreshable)
+	}
+	writer.Flush()
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+func formatUnixTime(seconds int) string {
+	return time.Unix(int64(seconds), 0).UTC().Format(time.RFC3339)
+}
+
+func (atlc *AccessTokenListCommand) ServerDetails() (*config.ServerDetails, error) {
+	return atlc.serverDetails, nil
+}
+
+func (atlc *AccessTokenListCommand) CommandName() string {
+	return "rt_list_access_tokens"
+}
+
+func (atlc *AccessTokenListCommand) Run() error {
+	servicesManager, err := rtUtils.CreateServiceManager(atlc.serverDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	response, err := servicesManager.GetTokens()
+	if err != nil {
+		return err
+	}
+	atlc.tokens = filterTokensByUser(response.Tokens, atlc.userName)
+	return nil
+}
+
+// The subject of a user token ends with '/users/<username>'. Artifactory lower-cases usernames in tokens.
+func filterTokensByUser(tokens []services.Token, userName string) []services.Token {
+	if userName == "" {
+		return tokens
+	}
+	suffix := "/users/" + strings.ToLower(userName)
+	filtered := []services.Token{}
+	for _, token := range tokens {
+		if strings.HasSuffix(token.Subject, suffix) {
+			filtered = append(filtered, token)
+		}
+	}
+	return filtered
+}
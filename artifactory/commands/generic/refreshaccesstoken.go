@@ -0,0 +1,79 @@
+package generic
+
+import (
+	"encoding/json"
+
+	rtUtils "github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// Exchange a refreshable access token and its refresh token for a new pair.
+type AccessTokenRefreshCommand struct {
+	serverDetails *config.ServerDetails
+	accessToken   string
+	refreshToken  string
+	expiry        int
+	response      *services.CreateTokenResponseData
+}
+
+func NewAccessTokenRefreshCommand() *AccessTokenRefreshCommand {
+	return &AccessTokenRefreshCommand{expiry: -1, response: new(services.CreateTokenResponseData)}
+}
+
+func (atrc *AccessTokenRefreshCommand) SetServerDetails(serverDetails *config.ServerDetails) *AccessTokenRefreshCommand {
+	atrc.serverDetails = serverDetails
+	return atrc
+}
+
+func (atrc *AccessTokenRefreshCommand) SetAccessToken(accessToken string) *AccessTokenRefreshCommand {
+	atrc.accessToken = accessToken
+	return atrc
+}
+
+func (atrc *AccessTokenRefreshCommand) SetRefreshToken(refreshToken string) *AccessTokenRefreshCommand {
+	atrc.refreshToken = refreshToken
+	return atrc
+}
+
+// The expiry of the new token in seconds. By default, the expiry of the refreshed token is kept.
+func (atrc *AccessTokenRefreshCommand) SetExpiry(expiry int) *AccessTokenRefreshCommand {
+	atrc.expiry = expiry
+	return atrc
+}
+
+func (atrc *AccessTokenRefreshCommand) Response() ([]byte, error) {
+	content, err := json.Marshal(*atrc.response)
+	return content, errorutils.CheckError(err)
+}
+
+func (atrc *AccessTokenRefreshCommand) ServerDetails() (*config.ServerDetails, error) {
+	return atrc.serverDetails, nil
+}
+
+func (atrc *AccessTokenRefreshCommand) CommandName() string {
+	return "rt_refresh_access_token"
+}
+
+func (atrc *AccessTokenRefreshCommand) Run() error {
+	// The refresh token authenticates the request, so other credentials are not sent.
+	noCredsDetails := &config.ServerDetails{
+		ArtifactoryUrl:    atrc.serverDetails.ArtifactoryUrl,
+		ClientCertPath:    atrc.serverDetails.ClientCertPath,
+		ClientCertKeyPath: atrc.serverDetails.ClientCertKeyPath,
+		InsecureTls:       atrc.serverDetails.InsecureTls,
+		ServerId:          atrc.serverDetails.ServerId,
+		IsDefault:         atrc.serverDetails.IsDefault,
+	}
+	servicesManager, err := rtUtils.CreateServiceManager(noCredsDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	refreshTokenParams := services.NewRefreshTokenParams()
+	refreshTokenParams.AccessToken = atrc.accessToken
+	refreshTokenParams.RefreshToken = atrc.refreshToken
+	refreshTokenParams.Token.ExpiresIn = atrc.expiry
+	*atrc.response, err = servicesManager.RefreshToken(refreshTokenParams)
+	return err
+}
@@ -0,0 +1,227 @@
+package generic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Evaluate retention policies against the files in their repositories, and delete the files the policies do not retain.
+// A file covered by several policies is deleted only if all of them decide to delete it.
+// In dry-run mode, only the report is printed.
+type RetentionCommand struct {
+	GenericCommand
+	policyFilePath string
+	threads        int
+	decisions      []RetentionDecision
+}
+
+func NewRetentionCommand() *RetentionCommand {
+	return &RetentionCommand{GenericCommand: *NewGenericCommand()}
+}
+
+func (rc *RetentionCommand) SetPolicyFilePath(policyFilePath string) *RetentionCommand {
+	rc.policyFilePath = policyFilePath
+	return rc
+}
+
+func (rc *RetentionCommand) SetThreads(threads int) *RetentionCommand {
+	rc.threads = threads
+	return rc
+}
+
+// The decisions made by the last run, sorted by path.
+func (rc *RetentionCommand) Decisions() []RetentionDecision {
+	return rc.decisions
+}
+
+func (rc *RetentionCommand) CommandName() string {
+	return "rt_retention"
+}
+
+func (rc *RetentionCommand) Run() error {
+	if rc.policyFilePath == "" {
+		return errorutils.CheckError(errors.New("a retention policy file is required"))
+	}
+	policies, err := ReadRetentionPolicies(rc.policyFilePath)
+	if err != nil {
+		return err
+	}
+	servicesManager, err := utils.CreateServiceManager(rc.serverDetails, rc.retries, false)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var decisions []RetentionDecision
+	for i := range policies.Policies {
+		policy := &policies.Policies[i]
+		for _, repo := range policy.Repos {
+			log.Info(fmt.Sprintf("Evaluating retention policy '%s' on repository '%s'...", policy.Name, repo))
+			items, buildArtifacts, err := collectRetentionItems(servicesManager, policy, repo)
+			if err != nil {
+				return err
+			}
+			decisions = append(decisions, evaluateRetentionPolicy(policy, items, buildArtifacts, now)...)
+		}
+	}
+	rc.decisions = mergeRetentionDecisions(decisions)
+	log.Output(formatRetentionReport(rc.decisions))
+	if rc.dryRun {
+		return nil
+	}
+	return rc.delete()
+}
+
+func (rc *RetentionCommand) delete() error {
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	if err != nil {
+		return err
+	}
+	total := 0
+	for _, decision := range rc.decisions {
+		if decision.Delete {
+			total++
+			repo, itemPath := splitRepoPath(decision.Path)
+			writer.Write(clientutils.ResultItem{Repo: repo, Path: path.Dir(itemPath), Name: path.Base(itemPath), Type: "file"})
+		}
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	if total == 0 {
+		log.Info("No files to delete.")
+		return nil
+	}
+	reader := content.NewContentReader(writer.GetFilePath(), content.DefaultKey)
+	defer reader.Close()
+	if !rc.quiet {
+		allowDelete, err := utils.ConfirmDelete(reader)
+		if err != nil || !allowDelete {
+			return err
+		}
+	}
+	servicesManager, err := utils.CreateDeleteServiceManager(rc.serverDetails, rc.threads, rc.retries, false)
+	if err != nil {
+		return err
+	}
+	deleted, err := servicesManager.DeleteFiles(reader)
+	rc.result.SetSuccessCount(deleted)
+	rc.result.SetFailCount(total - deleted)
+	return err
+}
+
+// Query the files of the repository covered by the policy, including their properties and download statistics,
+// and the paths of the files referenced by builds.
+func collectRetentionItems(servicesManager artifactory.ArtifactoryServicesManager, policy *RetentionPolicy, repo string) (items []*aqlItem, buildArtifacts map[string]bool, err error) {
+	body := createRetentionAqlBody(repo, policy.Path, "")
+	err = runAqlQuery(servicesManager, fmt.Sprintf(`items.find(%s).include("repo","path","name","size","created","stat.downloaded","property.key","property.value")`, body), func(item *aqlItem) {
+		items = append(items, item)
+	})
+	if err != nil || policy.DeleteBuildArtifacts {
+		return
+	}
+	buildArtifacts = make(map[string]bool)
+	// Files are referenced by builds either as artifacts or as dependencies.
+	for _, buildField := range []string{"artifact.module.build.name", "dependency.module.build.name"} {
+		body = createRetentionAqlBody(repo, policy.Path, fmt.Sprintf(`%q:{"$match":"*"}`, buildField))
+		err = runAqlQuery(servicesManager, fmt.Sprintf(`items.find(%s).include("repo","path","name")`, body), func(item *aqlItem) {
+			buildArtifacts[item.fullPath()] = true
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Match the files in the repository, under the policy's path if provided.
+func createRetentionAqlBody(repo, pathPattern, criteria string) string {
+	conditions := []string{fmt.Sprintf(`"repo":%q`, repo), `"type":"file"`}
+	if pathPattern = strings.Trim(pathPattern, "/"); pathPattern != "" {
+		// Files directly in the path, and files in the folders below it.
+		conditions = append(conditions, fmt.Sprintf(`"$or":[{"path":{"$match":%q}},{"path":{"$match":%q}}]`, pathPattern, pathPattern+"/*"))
+	}
+	if criteria != "" {
+		conditions = append(conditions, criteria)
+	}
+	return "{" + strings.Join(conditions, ",") + "}"
+}
+
+func runAqlQuery(servicesManager artifactory.ArtifactoryServicesManager, query string, handle func(*aqlItem)) error {
+	log.Debug("Searching Artifactory using AQL query:", query)
+	stream, err := servicesManager.Aql(query)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	return readAqlItems(stream, handle)
+}
+
+// Merge the decisions of several policies on the same file. A file is kept if any of the policies keeps it.
+func mergeRetentionDecisions(decisions []RetentionDecision) []RetentionDecision {
+	merged := make(map[string]RetentionDecision)
+	for _, decision := range decisions {
+		if existing, ok := merged[decision.Path]; ok && !existing.Delete {
+			continue
+		}
+		merged[decision.Path] = decision
+	}
+	results := make([]RetentionDecision, 0, len(merged))
+	for _, decision := range merged {
+		results = append(results, decision)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+	return results
+}
+
+// List the files to delete with their reasons, followed by the number of files and the size reclaimed per repository.
+func formatRetentionReport(decisions []RetentionDecision) string {
+	type repoSummary struct {
+		deleted, kept int
+		size          int64
+	}
+	summaries := make(map[string]*repoSummary)
+	var repos []string
+	buffer := &bytes.Buffer{}
+	writer := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "PATH\tSIZE\tPOLICY\tREASON")
+	for _, decision := range decisions {
+		repo, _ := splitRepoPath(decision.Path)
+		summary, ok := summaries[repo]
+		if !ok {
+			summary = new(repoSummary)
+			summaries[repo] = summary
+			repos = append(repos, repo)
+		}
+		if !decision.Delete {
+			summary.kept++
+			continue
+		}
+		summary.deleted++
+		summary.size += decision.Size
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", decision.Path, formatSize(decision.Size), decision.Policy, decision.Reason)
+	}
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "REPOSITORY\tDELETE\tKEEP\tRECLAIMED")
+	sort.Strings(repos)
+	for _, repo := range repos {
+		summary := summaries[repo]
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%s\n", repo, summary.deleted, summary.kept, formatSize(summary.size))
+	}
+	writer.Flush()
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
@@ -0,0 +1,215 @@
+package generic
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"gopkg.in/yaml.v2"
+)
+
+// A retention policy file, for example:
+//
+//	policies:
+//	  - name: snapshots
+//	    repos: [libs-snapshot-local]
+//	    keepLastVersions: 5
+//	    olderThanDays: 30
+//	    unlessDownloadedWithinDays: 7
+//	    keepProperties: [release, "qa.status=approved"]
+type RetentionPolicies struct {
+	Policies []RetentionPolicy `yaml:"policies"`
+}
+
+// Determines which files of a package are deleted.
+// A file is deleted only if it meets all the configured conditions, and none of the keep rules apply to it.
+// The version of a file is its parent folder, and the package is the folder above it.
+type RetentionPolicy struct {
+	Name  string   `yaml:"name"`
+	Repos []string `yaml:"repos"`
+	// Limit the policy to a path within the repositories. Wildcards are supported.
+	Path string `yaml:"path"`
+	// Keep the files of the N most recently created versions of each package.
+	KeepLastVersions int `yaml:"keepLastVersions"`
+	// Delete only files created more than X days ago.
+	OlderThanDays int `yaml:"olderThanDays"`
+	// Keep files downloaded within the last Y days.
+	UnlessDownloadedWithinDays int `yaml:"unlessDownloadedWithinDays"`
+	// Keep files with any of these properties, in the form of 'key' or 'key=value'.
+	KeepProperties []string `yaml:"keepProperties"`
+	// Files referenced by a published build are kept, unless this is set.
+	DeleteBuildArtifacts bool `yaml:"deleteBuildArtifacts"`
+}
+
+func ReadRetentionPolicies(policyFilePath string) (*RetentionPolicies, error) {
+	data, err := ioutil.ReadFile(policyFilePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return parseRetentionPolicies(data)
+}
+
+func parseRetentionPolicies(data []byte) (*RetentionPolicies, error) {
+	policies := new(RetentionPolicies)
+	if err := yaml.UnmarshalStrict(data, policies); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("invalid retention policy file: %s", err.Error()))
+	}
+	if len(policies.Policies) == 0 {
+		return nil, errorutils.CheckError(fmt.Errorf("the retention policy file does not include any policies"))
+	}
+	for i := range policies.Policies {
+		if err := policies.Policies[i].validate(i); err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+func (policy *RetentionPolicy) validate(index int) error {
+	if policy.Name == "" {
+		policy.Name = fmt.Sprintf("policy-%d", index+1)
+	}
+	if len(policy.Repos) == 0 {
+		return errorutils.CheckError(fmt.Errorf("retention policy '%s': at least one repository is required", policy.Name))
+	}
+	if policy.KeepLastVersions < 0 || policy.OlderThanDays < 0 || policy.UnlessDownloadedWithinDays < 0 {
+		return errorutils.CheckError(fmt.Errorf("retention policy '%s': the number of versions and days must not be negative", policy.Name))
+	}
+	// Without any of these conditions, the policy would delete every file.
+	if policy.KeepLastVersions == 0 && policy.OlderThanDays == 0 {
+		return errorutils.CheckError(fmt.Errorf("retention policy '%s': keepLastVersions or olderThanDays is required", policy.Name))
+	}
+	for _, property := range policy.KeepProperties {
+		if strings.TrimSpace(strings.SplitN(property, "=", 2)[0]) == "" {
+			return errorutils.CheckError(fmt.Errorf("retention policy '%s': invalid property '%s'", policy.Name, property))
+		}
+	}
+	return nil
+}
+
+// The decision of a retention policy on a file.
+type RetentionDecision struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Delete bool   `json:"delete"`
+	Reason string `json:"reason"`
+	Policy string `json:"policy"`
+}
+
+// Decide which of the files in a repository are deleted by the policy.
+// buildArtifacts holds the full paths of the files referenced by builds. The decisions are sorted by path.
+func evaluateRetentionPolicy(policy *RetentionPolicy, items []*aqlItem, buildArtifacts map[string]bool, now time.Time) []RetentionDecision {
+	latestVersions := findLatestVersions(items, policy.KeepLastVersions)
+	var decisions []RetentionDecision
+	for _, item := range items {
+		decision := RetentionDecision{Path: item.fullPath(), Size: item.Size, Policy: policy.Name}
+		decision.Delete, decision.Reason = policy.decide(item, latestVersions, buildArtifacts, now)
+		decisions = append(decisions, decision)
+	}
+	sort.Slice(decisions, func(i, j int) bool {
+		return decisions[i].Path < decisions[j].Path
+	})
+	return decisions
+}
+
+func (policy *RetentionPolicy) decide(item *aqlItem, latestVersions, buildArtifacts map[string]bool, now time.Time) (bool, string) {
+	if property, ok := policy.matchKeepProperty(item); ok {
+		return false, fmt.Sprintf("has the '%s' property", property)
+	}
+	if !policy.DeleteBuildArtifacts && buildArtifacts[item.fullPath()] {
+		return false, "referenced by a build"
+	}
+	// The versions are ranked and the age is computed by the creation time, so an item whose creation time is unknown is kept.
+	created, err := time.Parse(artifactoryTimeFormat, item.Created)
+	if err != nil && (policy.KeepLastVersions > 0 || policy.OlderThanDays > 0) {
+		return false, fmt.Sprintf("invalid creation time '%s'", item.Created)
+	}
+	var reasons []string
+	if policy.KeepLastVersions > 0 {
+		if latestVersions[versionOf(item)] {
+			return false, fmt.Sprintf("one of the last %d versions", policy.KeepLastVersions)
+		}
+		reasons = append(reasons, fmt.Sprintf("not one of the last %d versions", policy.KeepLastVersions))
+	}
+	if policy.OlderThanDays > 0 {
+		age := int(now.Sub(created) / day)
+		if age < policy.OlderThanDays {
+			return false, fmt.Sprintf("created %d days ago", age)
+		}
+		reasons = append(reasons, fmt.Sprintf("created %d days ago", age))
+	}
+	if policy.UnlessDownloadedWithinDays > 0 {
+		downloaded := item.lastDownloaded()
+		if downloaded.IsZero() {
+			reasons = append(reasons, "never downloaded")
+		} else {
+			days := int(now.Sub(downloaded) / day)
+			if days < policy.UnlessDownloadedWithinDays {
+				return false, fmt.Sprintf("downloaded %d days ago", days)
+			}
+			reasons = append(reasons, fmt.Sprintf("downloaded %d days ago", days))
+		}
+	}
+	return true, strings.Join(reasons, ", ")
+}
+
+// Return the first of the policy's keep properties which the item has.
+func (policy *RetentionPolicy) matchKeepProperty(item *aqlItem) (string, bool) {
+	for _, property := range policy.KeepProperties {
+		parts := strings.SplitN(property, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		for _, itemProperty := range item.Properties {
+			if itemProperty.Key == key && (len(parts) == 1 || itemProperty.Value == strings.TrimSpace(parts[1])) {
+				return property, true
+			}
+		}
+	}
+	return "", false
+}
+
+// The version of a file is its parent folder.
+func versionOf(item *aqlItem) string {
+	return path.Join(item.Repo, item.Path)
+}
+
+// Return the last N versions of each package, by the creation time of their newest file.
+func findLatestVersions(items []*aqlItem, keepLastVersions int) map[string]bool {
+	latest := make(map[string]bool)
+	if keepLastVersions <= 0 {
+		return latest
+	}
+	versionCreated := make(map[string]time.Time)
+	packageVersions := make(map[string][]string)
+	for _, item := range items {
+		created, err := time.Parse(artifactoryTimeFormat, item.Created)
+		if err != nil {
+			// Items with an invalid creation time are kept regardless of their version, so they don't take part in the ranking.
+			continue
+		}
+		version := versionOf(item)
+		newest, ok := versionCreated[version]
+		if !ok {
+			packageName := path.Dir(version)
+			packageVersions[packageName] = append(packageVersions[packageName], version)
+		}
+		if !ok || created.After(newest) {
+			versionCreated[version] = created
+		}
+	}
+	for _, versions := range packageVersions {
+		sort.Slice(versions, func(i, j int) bool {
+			if !versionCreated[versions[i]].Equal(versionCreated[versions[j]]) {
+				return versionCreated[versions[i]].After(versionCreated[versions[j]])
+			}
+			return versions[i] > versions[j]
+		})
+		for i := 0; i < len(versions) && i < keepLastVersions; i++ {
+			latest[versions[i]] = true
+		}
+	}
+	return latest
+}
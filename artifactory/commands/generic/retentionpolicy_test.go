@@ -0,0 +1,127 @@
+package generic
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const retentionAqlResponse = `{
+  "results": [
+    {"repo": "libs", "path": "org/app/1.0", "name": "app-1.0.jar", "size": 100, "created": "2021-01-01T10:00:00.000Z", "stats": [{"downloaded": "2021-06-28T10:00:00.000Z"}]},
+    {"repo": "libs", "path": "org/app/2.0", "name": "app-2.0.jar", "size": 200, "created": "2021-02-01T10:00:00.000Z", "stats": [{}]},
+    {"repo": "libs", "path": "org/app/3.0", "name": "app-3.0.jar", "size": 300, "created": "2021-03-01T10:00:00.000Z", "properties": [{"key": "release", "value": "true"}]},
+    {"repo": "libs", "path": "org/app/4.0", "name": "app-4.0.jar", "size": 400, "created": "2021-04-01T10:00:00.000Z", "stats": [{"downloaded": "2021-04-02T10:00:00.000Z"}]},
+    {"repo": "libs", "path": "org/app/5.0", "name": "app-5.0.jar", "size": 500, "created": "2021-06-01T10:00:00.000Z"},
+    {"repo": "libs", "path": "org/app/6.0", "name": "app-6.0.jar", "size": 600, "created": "2021-06-20T10:00:00.000Z"},
+    {"repo": "libs", "path": "org/app/6.0", "name": "app-6.0.pom", "size": 10, "created": "2021-06-20T10:00:00.000Z"}
+  ]
+}`
+
+func readRetentionItems(t *testing.T) []*aqlItem {
+	var items []*aqlItem
+	assert.NoError(t, readAqlItems(strings.NewReader(retentionAqlResponse), func(item *aqlItem) {
+		items = append(items, item)
+	}))
+	return items
+}
+
+func TestEvaluateRetentionPolicy(t *testing.T) {
+	now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	policy := &RetentionPolicy{
+		Name:                       "apps",
+		Repos:                      []string{"libs"},
+		KeepLastVersions:           2,
+		OlderThanDays:              60,
+		UnlessDownloadedWithinDays: 7,
+		KeepProperties:             []string{"release=true"},
+	}
+	buildArtifacts := map[string]bool{"libs/org/app/2.0/app-2.0.jar": true}
+	decisions := evaluateRetentionPolicy(policy, readRetentionItems(t), buildArtifacts, now)
+
+	assert.Equal(t, []RetentionDecision{
+		{Path: "libs/org/app/1.0/app-1.0.jar", Size: 100, Delete: false, Reason: "downloaded 2 days ago", Policy: "apps"},
+		{Path: "libs/org/app/2.0/app-2.0.jar", Size: 200, Delete: false, Reason: "referenced by a build", Policy: "apps"},
+		{Path: "libs/org/app/3.0/app-3.0.jar", Size: 300, Delete: false, Reason: "has the 'release=true' property", Policy: "apps"},
+		{Path: "libs/org/app/4.0/app-4.0.jar", Size: 400, Delete: true, Reason: "not one of the last 2 versions, created 90 days ago, downloaded 89 days ago", Policy: "apps"},
+		{Path: "libs/org/app/5.0/app-5.0.jar", Size: 500, Delete: false, Reason: "one of the last 2 versions", Policy: "apps"},
+		{Path: "libs/org/app/6.0/app-6.0.jar", Size: 600, Delete: false, Reason: "one of the last 2 versions", Policy: "apps"},
+		{Path: "libs/org/app/6.0/app-6.0.pom", Size: 10, Delete: false, Reason: "one of the last 2 versions", Policy: "apps"},
+	}, decisions)
+
+	// Without the build protection, only the versions and age conditions apply.
+	policy = &RetentionPolicy{Name: "old", OlderThanDays: 100, DeleteBuildArtifacts: true}
+	decisions = evaluateRetentionPolicy(policy, readRetentionItems(t), buildArtifacts, now)
+	var deleted []string
+	for _, decision := range decisions {
+		if decision.Delete {
+			deleted = append(deleted, decision.Path)
+		}
+	}
+	assert.Equal(t, []string{"libs/org/app/1.0/app-1.0.jar", "libs/org/app/2.0/app-2.0.jar", "libs/org/app/3.0/app-3.0.jar"}, deleted)
+}
+
+func TestEvaluateRetentionPolicyInvalidCreationTime(t *testing.T) {
+	now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	items := []*aqlItem{
+		{Repo: "libs", Path: "org/app/1.0", Name: "app-1.0.jar", Created: "2021-01-01T10:00:00.000Z"},
+		{Repo: "libs", Path: "org/app/2.0", Name: "app-2.0.jar", Created: "2021-02-01T10:00:00.000Z"},
+		{Repo: "libs", Path: "org/app/3.0", Name: "app-3.0.jar", Created: "invalid"},
+	}
+	policy := &RetentionPolicy{Name: "apps", KeepLastVersions: 1, OlderThanDays: 30}
+	decisions := evaluateRetentionPolicy(policy, items, nil, now)
+	assert.Equal(t, []RetentionDecision{
+		{Path: "libs/org/app/1.0/app-1.0.jar", Delete: true, Reason: "not one of the last 1 versions, created 180 days ago", Policy: "apps"},
+		{Path: "libs/org/app/2.0/app-2.0.jar", Delete: false, Reason: "one of the last 1 versions", Policy: "apps"},
+		{Path: "libs/org/app/3.0/app-3.0.jar", Delete: false, Reason: "invalid creation time 'invalid'", Policy: "apps"},
+	}, decisions)
+}
+
+func TestMergeRetentionDecisions(t *testing.T) {
+	merged := mergeRetentionDecisions([]RetentionDecision{
+		{Path: "libs/b", Delete: true, Policy: "first"},
+		{Path: "libs/a", Delete: true, Policy: "first"},
+		{Path: "libs/b", Delete: false, Policy: "second"},
+		{Path: "libs/a", Delete: true, Policy: "second"},
+	})
+	assert.Equal(t, []RetentionDecision{{Path: "libs/a", Delete: true, Policy: "second"}, {Path: "libs/b", Delete: false, Policy: "second"}}, merged)
+
+	report := formatRetentionReport([]RetentionDecision{{Path: "libs/a/b.jar", Size: 2048, Delete: true, Policy: "p", Reason: "created 90 days ago"}, {Path: "libs/a/c.jar"}})
+	assert.Contains(t, report, "libs/a/b.jar  2.0 KiB")
+	assert.Regexp(t, `libs\s+1\s+1\s+2.0 KiB`, report)
+}
+
+func TestParseRetentionPolicies(t *testing.T) {
+	policies, err := parseRetentionPolicies([]byte(`
+policies:
+  - repos: [libs-snapshot]
+    path: org/acme
+    keepLastVersions: 3
+    keepProperties: [release]
+`))
+	assert.NoError(t, err)
+	if assert.Len(t, policies.Policies, 1) {
+		assert.Equal(t, "policy-1", policies.Policies[0].Name)
+		assert.Equal(t, 3, policies.Policies[0].KeepLastVersions)
+	}
+
+	invalid := []string{
+		"policies: []",
+		"policies:\n  - repos: [libs]\n",
+		"policies:\n  - keepLastVersions: 1\n",
+		"policies:\n  - repos: [libs]\n    olderThenDays: 10\n",
+		"policies:\n  - repos: [libs]\n    olderThanDays: 10\n    keepProperties: [=x]\n",
+	}
+	for _, data := range invalid {
+		_, err = parseRetentionPolicies([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestCreateRetentionAqlBody(t *testing.T) {
+	assert.Equal(t, `{"repo":"libs","type":"file"}`, createRetentionAqlBody("libs", "", ""))
+	assert.Equal(t, `{"repo":"libs","type":"file","$or":[{"path":{"$match":"org/*"}},{"path":{"$match":"org/*/*"}}],"artifact.module.build.name":{"$match":"*"}}`,
+		createRetentionAqlBody("libs", "/org/*/", `"artifact.module.build.name":{"$match":"*"}`))
+}
@@ -0,0 +1,64 @@
+package generic
+
+import (
+	"errors"
+
+	rtUtils "github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// Revoke an access token, by the token itself or by its ID.
+type AccessTokenRevokeCommand struct {
+	serverDetails *config.ServerDetails
+	token         string
+	tokenId       string
+	response      string
+}
+
+func NewAccessTokenRevokeCommand() *AccessTokenRevokeCommand {
+	return &AccessTokenRevokeCommand{}
+}
+
+func (atrc *AccessTokenRevokeCommand) SetServerDetails(serverDetails *config.ServerDetails) *AccessTokenRevokeCommand {
+	atrc.serverDetails = serverDetails
+	return atrc
+}
+
+func (atrc *AccessTokenRevokeCommand) SetToken(token string) *AccessTokenRevokeCommand {
+	atrc.token = token
+	return atrc
+}
+
+func (atrc *AccessTokenRevokeCommand) SetTokenId(tokenId string) *AccessTokenRevokeCommand {
+	atrc.tokenId = tokenId
+	return atrc
+}
+
+func (atrc *AccessTokenRevokeCommand) Response() string {
+	return atrc.response
+}
+
+func (atrc *AccessTokenRevokeCommand) ServerDetails() (*config.ServerDetails, error) {
+	return atrc.serverDetails, nil
+}
+
+func (atrc *AccessTokenRevokeCommand) CommandName() string {
+	return "rt_revoke_access_token"
+}
+
+func (atrc *AccessTokenRevokeCommand) Run() error {
+	if (atrc.token == "") == (atrc.tokenId == "") {
+		return errorutils.CheckError(errors.New("either a token or a token ID must be provided"))
+	}
+	servicesManager, err := rtUtils.CreateServiceManager(atrc.serverDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	revokeTokenParams := services.NewRevokeTokenParams()
+	revokeTokenParams.Token = atrc.token
+	revokeTokenParams.TokenId = atrc.tokenId
+	atrc.response, err = servicesManager.RevokeToken(revokeTokenParams)
+	return err
+}
@@ -0,0 +1,186 @@
+package generic
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/auth"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Replace the access token of a configured server with a new one.
+// The new token is validated with a ping before it is saved to the configuration, and the previous token is revoked
+// only after the new one is saved, so that a failure at any step leaves the server with a working token.
+type AccessTokenRotateCommand struct {
+	serverId   string
+	expiry     int
+	groups     string
+	grantAdmin bool
+	response   *services.CreateTokenResponseData
+}
+
+func NewAccessTokenRotateCommand() *AccessTokenRotateCommand {
+	return &AccessTokenRotateCommand{expiry: -1, response: new(services.CreateTokenResponseData)}
+}
+
+// The ID of the configured server. If empty, the default server is used.
+func (atrc *AccessTokenRotateCommand) SetServerId(serverId string) *AccessTokenRotateCommand {
+	atrc.serverId = serverId
+	return atrc
+}
+
+// The expiry of the new token in seconds. By default, the new token has the same lifetime as the previous one,
+// and never expires if the previous one does not.
+func (atrc *AccessTokenRotateCommand) SetExpiry(expiry int) *AccessTokenRotateCommand {
+	atrc.expiry = expiry
+	return atrc
+}
+
+// The groups and admin privileges of the new token. If neither is set, the new token has the scope of the previous one.
+func (atrc *AccessTokenRotateCommand) SetGroups(groups string) *AccessTokenRotateCommand {
+	atrc.groups = groups
+	return atrc
+}
+
+func (atrc *AccessTokenRotateCommand) SetGrantAdmin(grantAdmin bool) *AccessTokenRotateCommand {
+	atrc.grantAdmin = grantAdmin
+	return atrc
+}
+
+func (atrc *AccessTokenRotateCommand) ServerDetails() (*config.ServerDetails, error) {
+	return config.GetSpecificConfig(atrc.serverId, true, false)
+}
+
+func (atrc *AccessTokenRotateCommand) CommandName() string {
+	return "rt_rotate_access_token"
+}
+
+func (atrc *AccessTokenRotateCommand) Run() error {
+	serverDetails, err := atrc.ServerDetails()
+	if err != nil {
+		return err
+	}
+	previousToken := serverDetails.AccessToken
+	if previousToken == "" {
+		return errorutils.CheckError(fmt.Errorf("the server '%s' is not configured with an access token", serverDetails.ServerId))
+	}
+	createCmd, err := atrc.createTokenCommand(serverDetails)
+	if err != nil {
+		return err
+	}
+	log.Info("Creating a new access token...")
+	if err = createCmd.Run(); err != nil {
+		return err
+	}
+	*atrc.response = *createCmd.response
+	newDetails := tokenOnlyServerDetails(serverDetails, atrc.response.AccessToken)
+
+	log.Info("Validating the new access token...")
+	if err = NewPingCommand().SetServerDetails(newDetails).Run(); err != nil {
+		// Don't leave an unused token behind.
+		if revokeErr := NewAccessTokenRevokeCommand().SetServerDetails(tokenOnlyServerDetails(serverDetails, previousToken)).SetToken(atrc.response.AccessToken).Run(); revokeErr != nil {
+			log.Warn("Failed to revoke the new access token:", revokeErr.Error())
+		}
+		return errorutils.CheckError(fmt.Errorf("the new access token could not be validated, the configuration was not changed: %s", err.Error()))
+	}
+
+	if err = saveServerTokens(serverDetails, atrc.response.AccessToken, atrc.response.RefreshToken); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("The new access token was saved to the configuration of the server '%s'.", serverDetails.ServerId))
+
+	log.Info("Revoking the previous access token...")
+	if err = NewAccessTokenRevokeCommand().SetServerDetails(newDetails).SetToken(previousToken).Run(); err != nil {
+		return errorutils.CheckError(errors.New("the new access token was saved, but the previous one could not be revoked: " + err.Error()))
+	}
+	return nil
+}
+
+// Create a token for the same user as the previous token, refreshable if the previous token was.
+// Unless groups or admin privileges are set, the new token has the scope of the previous one.
+func (atrc *AccessTokenRotateCommand) createTokenCommand(serverDetails *config.ServerDetails) (*AccessTokenCreateCommand, error) {
+	payload, err := extractAccessTokenPayload(serverDetails.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	userName := serverDetails.User
+	if userName == "" {
+		if userName, err = auth.ExtractUsernameFromAccessToken(serverDetails.AccessToken); err != nil {
+			return nil, err
+		}
+	}
+	expiry := atrc.expiry
+	if expiry < 0 {
+		expiry = getAccessTokenLifetime(payload)
+	}
+	createCmd := NewAccessTokenCreateCommand().
+		SetServerDetails(serverDetails).
+		SetUserName(userName).
+		SetExpiry(expiry).
+		SetRefreshable(serverDetails.RefreshToken != "").
+		SetGroups(atrc.groups).
+		SetGrantAdmin(atrc.grantAdmin)
+	if atrc.groups == "" && !atrc.grantAdmin {
+		createCmd.SetScope(payload.Scope)
+	}
+	return createCmd, nil
+}
+
+// The lifetime of the token in seconds. Tokens without an expiration time never expire, which an expiry of 0 requests.
+func getAccessTokenLifetime(payload *auth.TokenPayload) int {
+	if payload.ExpirationTime == 0 {
+		return 0
+	}
+	return payload.ExpirationTime - payload.IssuedAt
+}
+
+// Decode the payload of an access token, which is a JWT.
+func extractAccessTokenPayload(token string) (*auth.TokenPayload, error) {
+	tokenParts := strings.Split(token, ".")
+	if len(tokenParts) != 3 {
+		return nil, errorutils.CheckError(errors.New("invalid access token"))
+	}
+	// The payload is base64url encoded, but some tokens use the standard encoding.
+	payload, err := base64.RawURLEncoding.DecodeString(strings.NewReplacer("+", "-", "/", "_").Replace(strings.TrimRight(tokenParts[1], "=")))
+	if err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("invalid access token: %s", err.Error()))
+	}
+	tokenPayload := new(auth.TokenPayload)
+	if err = json.Unmarshal(payload, tokenPayload); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("invalid access token: %s", err.Error()))
+	}
+	return tokenPayload, nil
+}
+
+// Return a copy of the server details which authenticates with the given access token only.
+func tokenOnlyServerDetails(serverDetails *config.ServerDetails, accessToken string) *config.ServerDetails {
+	details := *serverDetails
+	details.User = ""
+	details.Password = ""
+	details.SshKeyPath = ""
+	details.RefreshToken = ""
+	details.AccessToken = accessToken
+	return &details
+}
+
+// Replace the tokens of the server in the configuration, keeping its position in the servers list.
+func saveServerTokens(serverDetails *config.ServerDetails, accessToken, refreshToken string) error {
+	configurations, err := config.GetAllServersConfigs()
+	if err != nil {
+		return err
+	}
+	for _, configuration := range configurations {
+		if configuration.ServerId == serverDetails.ServerId {
+			configuration.SetAccessToken(accessToken)
+			configuration.SetRefreshToken(refreshToken)
+			return config.SaveServersConf(configurations)
+		}
+	}
+	return errorutils.CheckError(fmt.Errorf("server ID '%s' does not exist", serverDetails.ServerId))
+}
@@ -0,0 +1,136 @@
+package generic
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// Create an unsigned token with the given subject and lifetime.
+func createTestAccessToken(subject string, issuedAt, expiresIn int) string {
+	payload := fmt.Sprintf(`{"sub":%q,"iat":%d,"exp":%d}`, subject, issuedAt, issuedAt+expiresIn)
+	return "header." + base64.RawStdEncoding.EncodeToString([]byte(payload)) + ".signature"
+}
+
+// Serve the token and ping APIs, accepting only the tokens which were issued and not revoked.
+type tokenServer struct {
+	validTokens map[string]bool
+	created     []string
+	revoked     []string
+	failPing    bool
+}
+
+func (ts *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ts.validTokens[token] {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch r.URL.Path {
+	case "/api/system/ping":
+		if ts.failPing {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, "OK")
+	case "/api/security/token":
+		r.ParseForm()
+		newToken := createTestAccessToken("jfrt@01/users/"+r.PostForm.Get("username"), 1000, 3600)
+		ts.validTokens[newToken] = true
+		ts.created = append(ts.created, r.PostForm.Encode())
+		fmt.Fprintf(w, `{"access_token":%q,"expires_in":3600}`, newToken)
+	case "/api/security/token/revoke":
+		r.ParseForm()
+		revoked := r.PostForm.Get("token")
+		delete(ts.validTokens, revoked)
+		ts.revoked = append(ts.revoked, revoked)
+		fmt.Fprint(w, "Token revoked")
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestRotateAccessToken(t *testing.T) {
+	log.SetDefaultLogger()
+	homeDir, err := ioutil.TempDir("", "rotate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(homeDir)
+	oldHome := os.Getenv(coreutils.HomeDir)
+	assert.NoError(t, os.Setenv(coreutils.HomeDir, homeDir))
+	defer os.Setenv(coreutils.HomeDir, oldHome)
+
+	previousToken := createTestAccessToken("jfrt@01/users/deployer", 1000, 7200)
+	server := &tokenServer{validTokens: map[string]bool{previousToken: true}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	assert.NoError(t, config.SaveServersConf([]*config.ServerDetails{
+		{ServerId: "other", ArtifactoryUrl: "http://other/", AccessToken: "other-token"},
+		{ServerId: "agent", ArtifactoryUrl: ts.URL + "/", AccessToken: previousToken, IsDefault: true},
+	}))
+
+	// The new token is discarded if it cannot be validated.
+	server.failPing = true
+	assert.Error(t, NewAccessTokenRotateCommand().SetServerId("agent").Run())
+	details, err := config.GetSpecificConfig("agent", false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, previousToken, details.AccessToken)
+	assert.Len(t, server.revoked, 1)
+	assert.NotEqual(t, previousToken, server.revoked[0])
+
+	server.failPing = false
+	assert.NoError(t, NewAccessTokenRotateCommand().SetServerId("agent").Run())
+	assert.Contains(t, server.created[1], "username=deployer")
+	assert.Contains(t, server.created[1], "expires_in=7200")
+	assert.Equal(t, previousToken, server.revoked[1])
+
+	configurations, err := config.GetAllServersConfigs()
+	assert.NoError(t, err)
+	if assert.Len(t, configurations, 2) {
+		assert.Equal(t, "other-token", configurations[0].AccessToken)
+		assert.Equal(t, "agent", configurations[1].ServerId)
+		assert.NotEqual(t, previousToken, configurations[1].AccessToken)
+		assert.True(t, server.validTokens[configurations[1].AccessToken])
+	}
+}
+
+func TestRotateAccessTokenParams(t *testing.T) {
+	// A token which never expires, with a scope of groups and API access.
+	payload := `{"sub":"jfrt@01/users/deployer","scp":"member-of-groups:readers api:*","iat":1000}`
+	serverDetails := &config.ServerDetails{AccessToken: "header." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"}
+	createCmd, err := NewAccessTokenRotateCommand().createTokenCommand(serverDetails)
+	assert.NoError(t, err)
+	tokenParams, err := createCmd.getTokenParams()
+	assert.NoError(t, err)
+	assert.Equal(t, "deployer", tokenParams.Username)
+	assert.Equal(t, 0, tokenParams.ExpiresIn)
+	assert.Equal(t, "member-of-groups:readers api:*", tokenParams.Scope)
+
+	// Groups replace the scope of the previous token.
+	createCmd, err = NewAccessTokenRotateCommand().SetGroups("deployers").SetExpiry(60).createTokenCommand(serverDetails)
+	assert.NoError(t, err)
+	tokenParams, err = createCmd.getTokenParams()
+	assert.NoError(t, err)
+	assert.Equal(t, 60, tokenParams.ExpiresIn)
+	assert.Equal(t, "member-of-groups:deployers", tokenParams.Scope)
+}
+
+func TestFilterTokensByUser(t *testing.T) {
+	tokens := []services.Token{
+		{TokenId: "1", Subject: "jfrt@01/users/admin"},
+		{TokenId: "2", Subject: "jfrt@01/users/deployer"},
+		{TokenId: "3", Subject: "jfrt@01/users/deployer2"},
+	}
+	assert.Equal(t, tokens, filterTokensByUser(tokens, ""))
+	assert.Equal(t, []services.Token{tokens[1]}, filterTokensByUser(tokens, "Deployer"))
+}
@@ -10,12 +10,32 @@ import (
 
 type SearchCommand struct {
 	GenericCommand
+	outputConfiguration *utils.SearchOutputConfiguration
 }
 
 func NewSearchCommand() *SearchCommand {
 	return &SearchCommand{GenericCommand: *NewGenericCommand()}
 }
 
+// Determines how PrintResults prints the search results. If not set, they are printed as JSON.
+func (sc *SearchCommand) SetOutputConfiguration(outputConfiguration *utils.SearchOutputConfiguration) *SearchCommand {
+	sc.outputConfiguration = outputConfiguration
+	return sc
+}
+
+func (sc *SearchCommand) OutputConfiguration() *utils.SearchOutputConfiguration {
+	return sc.outputConfiguration
+}
+
+// Print the search results of the last run, according to the output configuration.
+func (sc *SearchCommand) PrintResults() error {
+	reader := sc.Result().Reader()
+	if reader == nil {
+		return nil
+	}
+	return utils.PrintSearchResultsWithConfiguration(reader, sc.outputConfiguration)
+}
+
 func (sc *SearchCommand) CommandName() string {
 	return "rt_search"
 }
@@ -0,0 +1,462 @@
+package generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	StorageReportJsonOutput  = "json"
+	StorageReportTableOutput = "table"
+
+	defaultStorageReportTopN        = 10
+	defaultStorageReportFolderDepth = 1
+	neverDownloadedBucket           = "never"
+	noExtension                     = "(none)"
+)
+
+var (
+	day = 24 * time.Hour
+	// Age buckets, by the maximum age of the items in each bucket. The last bucket has no limit.
+	storageAgeBuckets = []struct {
+		name   string
+		maxAge time.Duration
+	}{
+		{"< 1 month", 30 * day},
+		{"1-3 months", 90 * day},
+		{"3-6 months", 180 * day},
+		{"6-12 months", 365 * day},
+		{"> 1 year", 0},
+	}
+	// Extensions which include a second extension, counted as one.
+	compoundExtensions = []string{".tar.gz", ".tar.bz2", ".tar.xz"}
+)
+
+// Report what takes up storage in Artifactory.
+// The files matched by a spec, or all the files in a list of repositories, are aggregated by repository, folder,
+// file extension, age and time since last download, and the largest and stalest files are listed.
+type StorageReportCommand struct {
+	GenericCommand
+	repos        []string
+	topN         int
+	folderDepth  int
+	outputFormat string
+	report       *StorageReport
+}
+
+func NewStorageReportCommand() *StorageReportCommand {
+	return &StorageReportCommand{GenericCommand: *NewGenericCommand(), topN: defaultStorageReportTopN, folderDepth: defaultStorageReportFolderDepth, outputFormat: StorageReportTableOutput}
+}
+
+// The repositories to report on, if no spec is provided.
+func (src *StorageReportCommand) SetRepos(repos []string) *StorageReportCommand {
+	src.repos = repos
+	return src
+}
+
+// The number of largest and stalest items to list.
+func (src *StorageReportCommand) SetTopN(topN int) *StorageReportCommand {
+	src.topN = topN
+	return src
+}
+
+// The number of folders below the repository root to aggregate by.
+func (src *StorageReportCommand) SetFolderDepth(folderDepth int) *StorageReportCommand {
+	src.folderDepth = folderDepth
+	return src
+}
+
+// json or table.
+func (src *StorageReportCommand) SetOutputFormat(outputFormat string) *StorageReportCommand {
+	src.outputFormat = outputFormat
+	return src
+}
+
+func (src *StorageReportCommand) Report() *StorageReport {
+	return src.report
+}
+
+func (src *StorageReportCommand) CommandName() string {
+	return "rt_storage_report"
+}
+
+func (src *StorageReportCommand) Run() error {
+	if src.outputFormat != StorageReportJsonOutput && src.outputFormat != StorageReportTableOutput {
+		return errorutils.CheckError(fmt.Errorf("unsupported output format '%s'. Possible values are: json and table", src.outputFormat))
+	}
+	queries, err := src.createAqlQueries()
+	if err != nil {
+		return err
+	}
+	servicesManager, err := utils.CreateServiceManager(src.serverDetails, src.retries, false)
+	if err != nil {
+		return err
+	}
+	builder := newStorageReportBuilder(time.Now(), src.topN, src.folderDepth)
+	for _, query := range queries {
+		log.Debug("Collecting storage information using AQL query:", query)
+		stream, err := servicesManager.Aql(query)
+		if err != nil {
+			return err
+		}
+		err = readAqlItems(stream, builder.add)
+		stream.Close()
+		if err != nil {
+			return err
+		}
+	}
+	src.report = builder.build()
+	if src.outputFormat == StorageReportJsonOutput {
+		data, err := json.MarshalIndent(src.report, "", "  ")
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		log.Output(string(data))
+		return nil
+	}
+	log.Output(src.report.String())
+	return nil
+}
+
+// Create a query per spec file or repository, including the download statistics of the items.
+// Items matched by more than one query are counted once by the report builder.
+func (src *StorageReportCommand) createAqlQueries() ([]string, error) {
+	var bodies []string
+	if src.spec != nil && len(src.spec.Files) > 0 {
+		for i := range src.spec.Files {
+			body, err := createStorageAqlBody(src.spec.Get(i))
+			if err != nil {
+				return nil, err
+			}
+			// Spec queries may match folders as well.
+			bodies = append(bodies, fmt.Sprintf(`{"$and":[%s,{"type":"file"}]}`, body))
+		}
+	} else {
+		for _, repo := range src.repos {
+			bodies = append(bodies, fmt.Sprintf(`{"repo":%q,"type":"file"}`, repo))
+		}
+	}
+	if len(bodies) == 0 {
+		return nil, errorutils.CheckError(fmt.Errorf("a spec or a list of repositories is required"))
+	}
+	var queries []string
+	for _, body := range bodies {
+		queries = append(queries, fmt.Sprintf(`items.find(%s).include("repo","path","name","size","created","modified","stat.downloaded")`, body))
+	}
+	return queries, nil
+}
+
+func createStorageAqlBody(file *spec.File) (string, error) {
+	params, err := file.ToCommonParams()
+	if err != nil {
+		return "", err
+	}
+	if params.Aql.ItemsFind != "" {
+		return params.Aql.ItemsFind, nil
+	}
+	if params.Recursive, err = file.IsRecursive(true); err != nil {
+		return "", err
+	}
+	return clientutils.CreateAqlBodyForSpecWithPattern(params)
+}
+
+// An item in an AQL response, which may include download statistics and properties.
+type aqlItem struct {
+	Repo     string `json:"repo"`
+	Path     string `json:"path"`
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	Created  string `json:"created"`
+	Modified string `json:"modified"`
+	Stats    []struct {
+		Downloaded string `json:"downloaded"`
+	} `json:"stats"`
+	Properties []clientutils.Property `json:"properties"`
+}
+
+func (item *aqlItem) fullPath() string {
+	return path.Join(item.Repo, item.Path, item.Name)
+}
+
+// Return the last download time, or the zero time if the item was never downloaded.
+func (item *aqlItem) lastDownloaded() time.Time {
+	if len(item.Stats) == 0 || item.Stats[0].Downloaded == "" {
+		return time.Time{}
+	}
+	downloaded, _ := time.Parse(artifactoryTimeFormat, item.Stats[0].Downloaded)
+	return downloaded
+}
+
+// Decode the items of an AQL response one by one, to avoid loading large responses into memory.
+func readAqlItems(stream io.Reader, handle func(*aqlItem)) error {
+	decoder := json.NewDecoder(stream)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return errorutils.CheckError(fmt.Errorf("the AQL response does not include results"))
+		}
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		if key, ok := token.(string); ok && key == "results" {
+			break
+		}
+	}
+	// Consume the opening bracket of the results array.
+	if _, err := decoder.Token(); err != nil {
+		return errorutils.CheckError(err)
+	}
+	for decoder.More() {
+		item := new(aqlItem)
+		if err := decoder.Decode(item); err != nil {
+			return errorutils.CheckError(err)
+		}
+		handle(item)
+	}
+	return nil
+}
+
+// The count and total size of the items in a group.
+type StorageGroup struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	Size  int64  `json:"size"`
+}
+
+type StorageItem struct {
+	Path           string `json:"path"`
+	Size           int64  `json:"size"`
+	Created        string `json:"created"`
+	LastDownloaded string `json:"lastDownloaded,omitempty"`
+	// The last download time, or the creation time if the item was never downloaded.
+	lastUsed time.Time
+}
+
+type StorageReport struct {
+	GeneratedAt      string         `json:"generatedAt"`
+	TotalCount       int            `json:"totalCount"`
+	TotalSize        int64          `json:"totalSize"`
+	ByRepo           []StorageGroup `json:"byRepo"`
+	ByFolder         []StorageGroup `json:"byFolder"`
+	ByExtension      []StorageGroup `json:"byExtension"`
+	ByAge            []StorageGroup `json:"byAge"`
+	ByLastDownloaded []StorageGroup `json:"byLastDownloaded"`
+	Largest          []StorageItem  `json:"largest"`
+	Stalest          []StorageItem  `json:"stalest"`
+}
+
+func (report *StorageReport) String() string {
+	sections := []string{fmt.Sprintf("Total: %d files, %s", report.TotalCount, formatSize(report.TotalSize))}
+	for _, groups := range []struct {
+		title  string
+		groups []StorageGroup
+	}{
+		{"Repository", report.ByRepo},
+		{"Folder", report.ByFolder},
+		{"Extension", report.ByExtension},
+		{"Age", report.ByAge},
+		{"Last downloaded", report.ByLastDownloaded},
+	} {
+		tableWriter := newStorageTableWriter(groups.title, "Files", "Size")
+		for _, group := range groups.groups {
+			tableWriter.AppendRow(table.Row{group.Name, group.Count, formatSize(group.Size)})
+		}
+		sections = append(sections, tableWriter.Render())
+	}
+	for _, items := range []struct {
+		title string
+		items []StorageItem
+	}{
+		{"Largest", report.Largest},
+		{"Stalest", report.Stalest},
+	} {
+		tableWriter := newStorageTableWriter(items.title, "Size", "Created", "Last downloaded")
+		for _, item := range items.items {
+			tableWriter.AppendRow(table.Row{item.Path, formatSize(item.Size), item.Created, item.LastDownloaded})
+		}
+		sections = append(sections, tableWriter.Render())
+	}
+	return strings.Join(sections, "\n")
+}
+
+func newStorageTableWriter(header ...interface{}) table.Writer {
+	tableWriter := table.NewWriter()
+	tableWriter.SetStyle(table.StyleLight)
+	tableWriter.AppendHeader(header)
+	return tableWriter
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+type storageReportBuilder struct {
+	now              time.Time
+	topN             int
+	folderDepth      int
+	totalCount       int
+	totalSize        int64
+	byRepo           map[string]*StorageGroup
+	byFolder         map[string]*StorageGroup
+	byExtension      map[string]*StorageGroup
+	byAge            map[string]*StorageGroup
+	byLastDownloaded map[string]*StorageGroup
+	largest          []StorageItem
+	stalest          []StorageItem
+	// The full paths of the items added so far, to count items matched by overlapping queries once.
+	added map[string]bool
+}
+
+func newStorageReportBuilder(now time.Time, topN, folderDepth int) *storageReportBuilder {
+	return &storageReportBuilder{
+		now:              now,
+		topN:             topN,
+		folderDepth:      folderDepth,
+		byRepo:           make(map[string]*StorageGroup),
+		byFolder:         make(map[string]*StorageGroup),
+		byExtension:      make(map[string]*StorageGroup),
+		byAge:            make(map[string]*StorageGroup),
+		byLastDownloaded: make(map[string]*StorageGroup),
+		added:            make(map[string]bool),
+	}
+}
+
+func (builder *storageReportBuilder) add(aqlItem *aqlItem) {
+	itemPath := aqlItem.fullPath()
+	if builder.added[itemPath] {
+		return
+	}
+	builder.added[itemPath] = true
+	created, _ := time.Parse(artifactoryTimeFormat, aqlItem.Created)
+	item := StorageItem{Path: itemPath, Size: aqlItem.Size, Created: aqlItem.Created, lastUsed: created}
+	downloadedBucket := neverDownloadedBucket
+	if downloaded := aqlItem.lastDownloaded(); !downloaded.IsZero() {
+		item.LastDownloaded = aqlItem.Stats[0].Downloaded
+		item.lastUsed = downloaded
+		downloadedBucket = builder.ageBucket(downloaded)
+	}
+
+	builder.totalCount++
+	builder.totalSize += item.Size
+	addToGroup(builder.byRepo, aqlItem.Repo, item.Size)
+	addToGroup(builder.byFolder, utils.GetFolderAtDepth(itemPath, builder.folderDepth), item.Size)
+	addToGroup(builder.byExtension, fileExtension(aqlItem.Name), item.Size)
+	addToGroup(builder.byAge, builder.ageBucket(created), item.Size)
+	addToGroup(builder.byLastDownloaded, downloadedBucket, item.Size)
+	builder.largest = insertTopN(builder.largest, item, builder.topN, func(a, b StorageItem) bool { return a.Size > b.Size })
+	builder.stalest = insertTopN(builder.stalest, item, builder.topN, func(a, b StorageItem) bool { return a.lastUsed.Before(b.lastUsed) })
+}
+
+func (builder *storageReportBuilder) ageBucket(t time.Time) string {
+	age := builder.now.Sub(t)
+	for _, bucket := range storageAgeBuckets {
+		if bucket.maxAge == 0 || age < bucket.maxAge {
+			return bucket.name
+		}
+	}
+	return ""
+}
+
+func (builder *storageReportBuilder) build() *StorageReport {
+	var ageBucketNames []string
+	for _, bucket := range storageAgeBuckets {
+		ageBucketNames = append(ageBucketNames, bucket.name)
+	}
+	return &StorageReport{
+		GeneratedAt:      builder.now.Format(artifactoryTimeFormat),
+		TotalCount:       builder.totalCount,
+		TotalSize:        builder.totalSize,
+		ByRepo:           sortGroupsBySize(builder.byRepo),
+		ByFolder:         sortGroupsBySize(builder.byFolder),
+		ByExtension:      sortGroupsBySize(builder.byExtension),
+		ByAge:            sortGroupsByName(builder.byAge, ageBucketNames),
+		ByLastDownloaded: sortGroupsByName(builder.byLastDownloaded, append([]string{neverDownloadedBucket}, ageBucketNames...)),
+		Largest:          builder.largest,
+		Stalest:          builder.stalest,
+	}
+}
+
+func addToGroup(groups map[string]*StorageGroup, name string, size int64) {
+	group, ok := groups[name]
+	if !ok {
+		group = &StorageGroup{Name: name}
+		groups[name] = group
+	}
+	group.Count++
+	group.Size += size
+}
+
+// Insert the item into a list sorted by less, keeping at most n items.
+func insertTopN(items []StorageItem, item StorageItem, n int, less func(a, b StorageItem) bool) []StorageItem {
+	index := sort.Search(len(items), func(i int) bool { return less(item, items[i]) })
+	if index >= n {
+		return items
+	}
+	items = append(items, StorageItem{})
+	copy(items[index+1:], items[index:])
+	items[index] = item
+	if len(items) > n {
+		items = items[:n]
+	}
+	return items
+}
+
+// Return the groups sorted by size, largest first.
+func sortGroupsBySize(groups map[string]*StorageGroup) []StorageGroup {
+	result := make([]StorageGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Size != result[j].Size {
+			return result[i].Size > result[j].Size
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Return the groups in the order of the given names, skipping empty groups.
+func sortGroupsByName(groups map[string]*StorageGroup, names []string) []StorageGroup {
+	var result []StorageGroup
+	for _, name := range names {
+		if group, ok := groups[name]; ok {
+			result = append(result, *group)
+		}
+	}
+	return result
+}
+
+func fileExtension(name string) string {
+	lowerName := strings.ToLower(name)
+	for _, extension := range compoundExtensions {
+		if strings.HasSuffix(lowerName, extension) {
+			return extension
+		}
+	}
+	if extension := path.Ext(lowerName); extension != "" {
+		return extension
+	}
+	return noExtension
+}
@@ -0,0 +1,88 @@
+package generic
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+const storageAqlResponse = `{
+  "results": [
+    {"repo": "libs", "path": "org/app/1.0", "name": "app-1.0.jar", "size": 3000, "created": "2021-01-10T10:00:00.000Z", "stats": [{"downloaded": "2021-06-25T10:00:00.000Z"}]},
+    {"repo": "libs", "path": "org/app/2.0", "name": "app-2.0.jar", "size": 5000, "created": "2021-06-20T10:00:00.000Z", "stats": [{}]},
+    {"repo": "libs", "path": "org/lib/1.0", "name": "lib-1.0.tar.gz", "size": 1000, "created": "2020-03-01T10:00:00.000Z", "stats": [{"downloaded": "2020-04-01T10:00:00.000Z"}]},
+    {"repo": "docker", "path": "app/latest", "name": "manifest.json", "size": 100, "created": "2021-06-29T10:00:00.000Z"},
+    {"repo": "docker", "path": ".", "name": "README", "size": 10, "created": "2021-02-01T10:00:00.000Z"}
+  ],
+  "range": {"start_pos": 0, "end_pos": 5, "total": 5}
+}`
+
+func TestStorageReport(t *testing.T) {
+	now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	builder := newStorageReportBuilder(now, 2, 1)
+	assert.NoError(t, readAqlItems(strings.NewReader(storageAqlResponse), builder.add))
+	report := builder.build()
+
+	assert.Equal(t, 5, report.TotalCount)
+	assert.Equal(t, int64(9110), report.TotalSize)
+	assert.Equal(t, []StorageGroup{{"libs", 3, 9000}, {"docker", 2, 110}}, report.ByRepo)
+	assert.Equal(t, []StorageGroup{{"libs/org", 3, 9000}, {"docker/app", 1, 100}, {"docker", 1, 10}}, report.ByFolder)
+	assert.Equal(t, []StorageGroup{{".jar", 2, 8000}, {".tar.gz", 1, 1000}, {".json", 1, 100}, {noExtension, 1, 10}}, report.ByExtension)
+	assert.Equal(t, []StorageGroup{{"< 1 month", 2, 5100}, {"3-6 months", 2, 3010}, {"> 1 year", 1, 1000}}, report.ByAge)
+	assert.Equal(t, []StorageGroup{{neverDownloadedBucket, 3, 5110}, {"< 1 month", 1, 3000}, {"> 1 year", 1, 1000}}, report.ByLastDownloaded)
+
+	if assert.Len(t, report.Largest, 2) {
+		assert.Equal(t, "libs/org/app/2.0/app-2.0.jar", report.Largest[0].Path)
+		assert.Equal(t, "libs/org/app/1.0/app-1.0.jar", report.Largest[1].Path)
+	}
+	// Items which were never downloaded are ranked by their creation time.
+	if assert.Len(t, report.Stalest, 2) {
+		assert.Equal(t, "libs/org/lib/1.0/lib-1.0.tar.gz", report.Stalest[0].Path)
+		assert.Equal(t, "docker/README", report.Stalest[1].Path)
+	}
+	assert.Contains(t, report.String(), "Total: 5 files, 8.9 KiB")
+}
+
+func TestCreateStorageAqlQueries(t *testing.T) {
+	command := NewStorageReportCommand().SetRepos([]string{"libs", "docker"})
+	queries, err := command.createAqlQueries()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`items.find({"repo":"libs","type":"file"}).include("repo","path","name","size","created","modified","stat.downloaded")`,
+		`items.find({"repo":"docker","type":"file"}).include("repo","path","name","size","created","modified","stat.downloaded")`,
+	}, queries)
+
+	_, err = NewStorageReportCommand().createAqlQueries()
+	assert.Error(t, err)
+
+	// Spec queries are limited to files.
+	specFiles := spec.NewBuilder().Pattern("libs/org/").BuildSpec()
+	command = NewStorageReportCommand()
+	command.SetSpec(specFiles)
+	queries, err = command.createAqlQueries()
+	assert.NoError(t, err)
+	if assert.Len(t, queries, 1) {
+		assert.True(t, strings.HasPrefix(queries[0], `items.find({"$and":[{`), queries[0])
+		assert.Contains(t, queries[0], `,{"type":"file"}]})`)
+	}
+}
+
+func TestStorageReportOverlappingQueries(t *testing.T) {
+	builder := newStorageReportBuilder(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC), 10, 1)
+	// The same items, returned by two queries.
+	assert.NoError(t, readAqlItems(strings.NewReader(storageAqlResponse), builder.add))
+	assert.NoError(t, readAqlItems(strings.NewReader(storageAqlResponse), builder.add))
+	report := builder.build()
+	assert.Equal(t, 5, report.TotalCount)
+	assert.Equal(t, int64(9110), report.TotalSize)
+	assert.Len(t, report.Largest, 5)
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", formatSize(512))
+	assert.Equal(t, "1.5 KiB", formatSize(1536))
+	assert.Equal(t, "2.0 GiB", formatSize(2<<30))
+}
@@ -0,0 +1,407 @@
+package generic
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const artifactoryTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// Synchronize a local directory and a repository path in both directions.
+// Files are compared by SHA1. Changes are detected relative to the state recorded at the end of the previous sync,
+// so that a file deleted on one side is deleted on the other side rather than restored.
+type SyncCommand struct {
+	GenericCommand
+	localDir       string
+	repoPath       string
+	conflictPolicy ConflictPolicy
+	threads        int
+	stateFilePath  string
+	plan           []SyncPlanItem
+}
+
+func NewSyncCommand() *SyncCommand {
+	return &SyncCommand{GenericCommand: *NewGenericCommand(), conflictPolicy: SkipConflicts}
+}
+
+func (sc *SyncCommand) SetLocalDir(localDir string) *SyncCommand {
+	sc.localDir = localDir
+	return sc
+}
+
+// The repository path to sync, in the form of 'repo/path'.
+func (sc *SyncCommand) SetRepoPath(repoPath string) *SyncCommand {
+	sc.repoPath = strings.TrimSuffix(repoPath, "/")
+	return sc
+}
+
+func (sc *SyncCommand) SetConflictPolicy(conflictPolicy ConflictPolicy) *SyncCommand {
+	sc.conflictPolicy = conflictPolicy
+	return sc
+}
+
+func (sc *SyncCommand) SetThreads(threads int) *SyncCommand {
+	sc.threads = threads
+	return sc
+}
+
+// The file recording the state of the previous sync. By default, it is kept in the JFrog home directory.
+func (sc *SyncCommand) SetStateFilePath(stateFilePath string) *SyncCommand {
+	sc.stateFilePath = stateFilePath
+	return sc
+}
+
+// The plan computed by the last run.
+func (sc *SyncCommand) Plan() []SyncPlanItem {
+	return sc.plan
+}
+
+func (sc *SyncCommand) CommandName() string {
+	return "rt_sync"
+}
+
+func (sc *SyncCommand) Run() (err error) {
+	if sc.localDir == "" || sc.repoPath == "" {
+		return errorutils.CheckError(errors.New("a local directory and a repository path are required"))
+	}
+	if err = fileutils.CreateDirIfNotExist(sc.localDir); err != nil {
+		return err
+	}
+	if sc.stateFilePath == "" {
+		if sc.stateFilePath, err = sc.defaultStateFilePath(); err != nil {
+			return err
+		}
+	}
+	servicesManager, err := utils.CreateServiceManager(sc.serverDetails, sc.retries, false)
+	if err != nil {
+		return err
+	}
+	local, remote, err := sc.listFiles(servicesManager)
+	if err != nil {
+		return err
+	}
+	state, err := readSyncState(sc.stateFilePath)
+	if err != nil {
+		return err
+	}
+	sc.plan, err = createSyncPlan(local, remote, state.Files, sc.conflictPolicy)
+	if err != nil {
+		return err
+	}
+	log.Output(formatSyncPlan(sc.plan))
+	counts := countSyncActions(sc.plan)
+	if sc.dryRun || len(sc.plan) == counts[ConflictAction] {
+		return nil
+	}
+	if !sc.quiet && counts[DeleteLocalAction]+counts[DeleteRemoteAction] > 0 && !coreutils.AskYesNo("The plan deletes files. Are you sure you want to apply it?", false) {
+		return nil
+	}
+	succeeded, failed, applyErr := sc.apply()
+	sc.result.SetSuccessCount(succeeded)
+	sc.result.SetFailCount(failed)
+	// Record the state even if some of the changes failed, so that the changes which succeeded are not treated as conflicts by the next sync.
+	if err = sc.saveState(servicesManager, state.Files); err != nil {
+		return err
+	}
+	return applyErr
+}
+
+func (sc *SyncCommand) apply() (succeeded, failed int, err error) {
+	var uploads []services.UploadParams
+	var downloads []services.DownloadParams
+	var remoteDeletes, localDeletes []string
+	var errs []string
+	addResult := func(s, f int, e error) {
+		succeeded += s
+		failed += f
+		if e != nil {
+			errs = append(errs, e.Error())
+		}
+	}
+	for _, item := range sc.plan {
+		switch item.Action {
+		case UploadAction:
+			uploadParams, e := sc.createUploadParams(item.Path)
+			if e != nil {
+				addResult(0, 1, e)
+				continue
+			}
+			uploads = append(uploads, uploadParams)
+		case DownloadAction:
+			downloadParams, e := sc.createDownloadParams(item.Path)
+			if e != nil {
+				addResult(0, 1, e)
+				continue
+			}
+			downloads = append(downloads, downloadParams)
+		case DeleteRemoteAction:
+			remoteDeletes = append(remoteDeletes, item.Path)
+		case DeleteLocalAction:
+			localDeletes = append(localDeletes, item.Path)
+		}
+	}
+	if len(uploads) > 0 {
+		addResult(sc.upload(uploads))
+	}
+	if len(downloads) > 0 {
+		addResult(sc.download(downloads))
+	}
+	if len(remoteDeletes) > 0 {
+		addResult(sc.deleteRemote(remoteDeletes))
+	}
+	for _, localPath := range localDeletes {
+		addResult(sc.deleteLocal(localPath))
+	}
+	if failed > 0 && len(errs) == 0 {
+		errs = append(errs, fmt.Sprintf("%d of the sync actions failed", failed))
+	}
+	if len(errs) > 0 {
+		err = errorutils.CheckError(errors.New(strings.Join(errs, "\n")))
+	}
+	return
+}
+
+// Upload the file by its exact path. The pattern type is chosen so that the whole path is the pattern's root path,
+// which is uploaded as is, rather than matched as a pattern - so that characters such as '*' and '(' in its name are kept.
+func (sc *SyncCommand) createUploadParams(relativePath string) (services.UploadParams, error) {
+	uploadParams := services.NewUploadParams()
+	localPath := filepath.Join(sc.localDir, filepath.FromSlash(relativePath))
+	switch {
+	case !strings.Contains(localPath, "*"):
+		// Without a placeholder in the target, parentheses are not treated as wildcards.
+	case !strings.Contains(localPath, "("):
+		uploadParams.Regexp = true
+	default:
+		return uploadParams, errorutils.CheckError(fmt.Errorf("the path of %s includes both '*' and '(', so it cannot be uploaded by its exact path", localPath))
+	}
+	uploadParams.CommonParams = &clientutils.CommonParams{Pattern: localPath, Target: sc.repoPath + "/" + relativePath}
+	uploadParams.Flat = true
+	return uploadParams, nil
+}
+
+// Download the file by its exact path, using a query rather than a pattern, so that characters such as '*' in its name are kept.
+func (sc *SyncCommand) createDownloadParams(relativePath string) (services.DownloadParams, error) {
+	downloadParams := services.NewDownloadParams()
+	repo, itemPath := splitRepoPath(sc.repoPath + "/" + relativePath)
+	query, err := json.Marshal(map[string]string{"repo": repo, "path": path.Dir(itemPath), "name": path.Base(itemPath)})
+	if err != nil {
+		return downloadParams, errorutils.CheckError(err)
+	}
+	targetDir := filepath.Dir(filepath.Join(sc.localDir, filepath.FromSlash(relativePath)))
+	downloadParams.CommonParams = &clientutils.CommonParams{Aql: clientutils.Aql{ItemsFind: string(query)}, Target: targetDir + string(filepath.Separator)}
+	downloadParams.Flat = true
+	return downloadParams, nil
+}
+
+func (sc *SyncCommand) upload(uploads []services.UploadParams) (int, int, error) {
+	servicesManager, err := utils.CreateUploadServiceManager(sc.serverDetails, sc.threads, sc.retries, false, nil)
+	if err != nil {
+		return 0, len(uploads), err
+	}
+	summary, err := servicesManager.UploadFilesWithSummary(uploads...)
+	if err != nil {
+		return 0, len(uploads), err
+	}
+	summary.TransferDetailsReader.Close()
+	summary.ArtifactsDetailsReader.Close()
+	return summary.TotalSucceeded, summary.TotalFailed, nil
+}
+
+func (sc *SyncCommand) download(downloads []services.DownloadParams) (int, int, error) {
+	servicesManager, err := utils.CreateDownloadServiceManager(sc.serverDetails, sc.threads, sc.retries, false, nil)
+	if err != nil {
+		return 0, len(downloads), err
+	}
+	return servicesManager.DownloadFiles(downloads...)
+}
+
+// Delete a local file, along with the directories it leaves empty under the local directory.
+func (sc *SyncCommand) deleteLocal(relativePath string) (int, int, error) {
+	localPath := filepath.Join(sc.localDir, filepath.FromSlash(relativePath))
+	if err := os.Remove(localPath); err != nil {
+		return 0, 1, errorutils.CheckError(err)
+	}
+	for dir := filepath.Dir(relativePath); dir != "." && dir != "/"; dir = filepath.Dir(dir) {
+		dirPath := filepath.Join(sc.localDir, dir)
+		if empty, err := fileutils.IsDirEmpty(dirPath); err != nil || !empty {
+			break
+		}
+		if err := os.Remove(dirPath); err != nil {
+			log.Warn(fmt.Sprintf("Failed to remove the empty directory %s: %s", dirPath, err.Error()))
+			break
+		}
+	}
+	return 1, 0, nil
+}
+
+func (sc *SyncCommand) deleteRemote(paths []string) (int, int, error) {
+	writer, err := content.NewContentWriter(content.DefaultKey, true, false)
+	if err != nil {
+		return 0, len(paths), err
+	}
+	for _, relativePath := range paths {
+		repo, itemPath := splitRepoPath(sc.repoPath + "/" + relativePath)
+		writer.Write(clientutils.ResultItem{Repo: repo, Path: path.Dir(itemPath), Name: path.Base(itemPath), Type: "file"})
+	}
+	if err = writer.Close(); err != nil {
+		return 0, len(paths), err
+	}
+	reader := content.NewContentReader(writer.GetFilePath(), content.DefaultKey)
+	defer reader.Close()
+	servicesManager, err := utils.CreateDeleteServiceManager(sc.serverDetails, sc.threads, sc.retries, false)
+	if err != nil {
+		return 0, len(paths), err
+	}
+	deleted, err := servicesManager.DeleteFiles(reader)
+	return deleted, len(paths) - deleted, err
+}
+
+// Return the files on both sides, by their path relative to the local directory and the repository path.
+func (sc *SyncCommand) listFiles(servicesManager artifactory.ArtifactoryServicesManager) (local, remote map[string]*syncFile, err error) {
+	if local, err = listLocalFiles(sc.localDir); err != nil {
+		return
+	}
+	remote, err = listRemoteFiles(servicesManager, sc.repoPath)
+	return
+}
+
+func listLocalFiles(localDir string) (map[string]*syncFile, error) {
+	files := make(map[string]*syncFile)
+	err := filepath.Walk(localDir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		details, err := fileutils.GetFileDetails(filePath)
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(localDir, filePath)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relativePath)] = &syncFile{Sha1: details.Checksum.Sha1, Size: info.Size(), Modified: info.ModTime()}
+		return nil
+	})
+	return files, errorutils.CheckError(err)
+}
+
+func listRemoteFiles(servicesManager artifactory.ArtifactoryServicesManager, repoPath string) (map[string]*syncFile, error) {
+	searchParams := services.NewSearchParams()
+	searchParams.CommonParams = &clientutils.CommonParams{Pattern: repoPath + "/*"}
+	searchParams.Recursive = true
+	reader, err := servicesManager.SearchFiles(searchParams)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	files := make(map[string]*syncFile)
+	prefix := repoPath + "/"
+	for item := new(clientutils.ResultItem); reader.NextRecord(item) == nil; item = new(clientutils.ResultItem) {
+		itemPath := item.Repo + "/" + item.Name
+		if item.Path != "." {
+			itemPath = item.Repo + "/" + item.Path + "/" + item.Name
+		}
+		if !strings.HasPrefix(itemPath, prefix) {
+			continue
+		}
+		modified, err := time.Parse(artifactoryTimeFormat, item.Modified)
+		if err != nil {
+			return nil, errorutils.CheckError(fmt.Errorf("failed to parse the modification time of %s: %s", itemPath, err.Error()))
+		}
+		files[strings.TrimPrefix(itemPath, prefix)] = &syncFile{Sha1: item.Actual_Sha1, Size: item.Size, Modified: modified}
+	}
+	return files, reader.GetError()
+}
+
+func splitRepoPath(repoPath string) (repo, itemPath string) {
+	parts := strings.SplitN(repoPath, "/", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// The state of a sync at its end - the SHA1 of each file which was identical on both sides.
+type syncState struct {
+	LocalDir string            `json:"localDir"`
+	RepoPath string            `json:"repoPath"`
+	Files    map[string]string `json:"files"`
+}
+
+func readSyncState(stateFilePath string) (*syncState, error) {
+	state := &syncState{Files: make(map[string]string)}
+	data, err := ioutil.ReadFile(stateFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	if err = json.Unmarshal(data, state); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if state.Files == nil {
+		state.Files = make(map[string]string)
+	}
+	return state, nil
+}
+
+// List both sides again and record the files which are in sync. Skipped conflicts keep their previous state, to be detected again by the next sync.
+func (sc *SyncCommand) saveState(servicesManager artifactory.ArtifactoryServicesManager, previous map[string]string) error {
+	local, remote, err := sc.listFiles(servicesManager)
+	if err != nil {
+		return err
+	}
+	state := &syncState{LocalDir: sc.localDir, RepoPath: sc.repoPath, Files: make(map[string]string)}
+	for filePath, localFile := range local {
+		if remoteFile, ok := remote[filePath]; ok && remoteFile.Sha1 == localFile.Sha1 {
+			state.Files[filePath] = localFile.Sha1
+		}
+	}
+	for _, item := range sc.plan {
+		if sha1, ok := previous[item.Path]; ok && item.Action == ConflictAction {
+			state.Files[item.Path] = sha1
+		}
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = fileutils.CreateDirIfNotExist(filepath.Dir(sc.stateFilePath)); err != nil {
+		return err
+	}
+	return errorutils.CheckError(ioutil.WriteFile(sc.stateFilePath, data, 0600))
+}
+
+// The state file is identified by the server, the local directory and the repository path.
+func (sc *SyncCommand) defaultStateFilePath() (string, error) {
+	absLocalDir, err := filepath.Abs(sc.localDir)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	syncDir, err := coreutils.CreateDirInJfrogHome(coreutils.JfrogSyncDirName)
+	if err != nil {
+		return "", err
+	}
+	checksum := sha1.Sum([]byte(sc.serverDetails.ArtifactoryUrl + "|" + absLocalDir + "|" + sc.repoPath))
+	return filepath.Join(syncDir, hex.EncodeToString(checksum[:])+".json"), nil
+}
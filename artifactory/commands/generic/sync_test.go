@@ -0,0 +1,104 @@
+package generic
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// Serve the files of repositories from memory. AQL queries return the files whose name matches the query's exact name, if any.
+type repoServer struct {
+	mutex sync.Mutex
+	files map[string][]byte
+}
+
+var aqlNamePattern = regexp.MustCompile(`"name":"([^"$]*)"`)
+
+func (rs *repoServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	body, _ := ioutil.ReadAll(r.Body)
+	itemPath, _ := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), "/"))
+	switch {
+	case itemPath == "api/system/version":
+		w.Write([]byte(`{"version":"7.20.0"}`))
+	case itemPath == "api/search/aql":
+		match := aqlNamePattern.FindSubmatch(body)
+		var results []map[string]interface{}
+		for filePath, content := range rs.files {
+			if match != nil && path.Base(filePath) != string(match[1]) {
+				continue
+			}
+			sha1Sum, md5Sum := sha1.Sum(content), md5.Sum(content)
+			parts := strings.SplitN(filePath, "/", 2)
+			results = append(results, map[string]interface{}{"repo": parts[0], "path": path.Dir(parts[1]), "name": path.Base(parts[1]), "type": "file",
+				"size": len(content), "modified": "2021-01-01T00:00:00.000Z", "actual_sha1": hex.EncodeToString(sha1Sum[:]), "actual_md5": hex.EncodeToString(md5Sum[:])})
+		}
+		data, _ := json.Marshal(map[string]interface{}{"results": results})
+		w.Write(data)
+	case r.Method == http.MethodPut:
+		if r.Header.Get("X-Checksum-Deploy") == "true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rs.files[strings.SplitN(itemPath, ";", 2)[0]] = body
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		content, ok := rs.files[itemPath]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestSyncCommand(t *testing.T) {
+	log.SetDefaultLogger()
+	server := &repoServer{files: map[string][]byte{"libs/sync/docs/b*.txt": []byte("remote")}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	tempDir, err := ioutil.TempDir("", "sync")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	localDir := filepath.Join(tempDir, "local")
+	assert.NoError(t, os.MkdirAll(filepath.Join(localDir, "old", "deep"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(localDir, "a(1).txt"), []byte("local"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(localDir, "old", "deep", "c.txt"), []byte("deleted remotely"), 0644))
+	// The previous sync recorded c.txt on both sides, so it is deleted locally now that it is missing from the repository.
+	stateFilePath := filepath.Join(tempDir, "state.json")
+	sha1Sum := sha1.Sum([]byte("deleted remotely"))
+	state, err := json.Marshal(syncState{Files: map[string]string{"old/deep/c.txt": hex.EncodeToString(sha1Sum[:])}})
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(stateFilePath, state, 0600))
+
+	command := NewSyncCommand().SetLocalDir(localDir).SetRepoPath("libs/sync").SetStateFilePath(stateFilePath).SetThreads(2)
+	command.SetServerDetails(&config.ServerDetails{ArtifactoryUrl: ts.URL + "/"}).SetQuiet(true)
+	assert.NoError(t, command.Run())
+	assert.Equal(t, []byte("local"), server.files["libs/sync/a(1).txt"])
+	content, err := ioutil.ReadFile(filepath.Join(localDir, "docs", "b*.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "remote", string(content))
+	// The directories left empty by the deletion are removed.
+	assert.NoDirExists(t, filepath.Join(localDir, "old"))
+	assert.DirExists(t, localDir)
+}
@@ -0,0 +1,177 @@
+package generic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+type SyncAction string
+
+const (
+	UploadAction       SyncAction = "upload"
+	DownloadAction     SyncAction = "download"
+	DeleteLocalAction  SyncAction = "delete-local"
+	DeleteRemoteAction SyncAction = "delete-remote"
+	ConflictAction     SyncAction = "conflict"
+)
+
+// Determines how a file changed on both sides since the last sync is handled.
+type ConflictPolicy string
+
+const (
+	// Leave the conflicting file as is on both sides, and report it.
+	SkipConflicts ConflictPolicy = "skip"
+	// Fail before applying any change.
+	FailOnConflict ConflictPolicy = "fail"
+	// Apply the local state of the file to the repository.
+	LocalWins ConflictPolicy = "local"
+	// Apply the state of the file in the repository to the local directory.
+	RemoteWins ConflictPolicy = "remote"
+	// Keep the most recently modified version. A modified file wins over a deleted one.
+	NewerWins ConflictPolicy = "newer"
+)
+
+func GetConflictPolicy(policy string) (ConflictPolicy, error) {
+	switch conflictPolicy := ConflictPolicy(policy); conflictPolicy {
+	case "":
+		return SkipConflicts, nil
+	case SkipConflicts, FailOnConflict, LocalWins, RemoteWins, NewerWins:
+		return conflictPolicy, nil
+	}
+	return "", errorutils.CheckError(fmt.Errorf("unsupported conflict policy '%s'. Possible values are: skip, fail, local, remote and newer", policy))
+}
+
+// The state of a file on one side of the sync.
+type syncFile struct {
+	Sha1     string
+	Size     int64
+	Modified time.Time
+}
+
+// A planned action on a file, identified by its path relative to the local directory and the repository path.
+type SyncPlanItem struct {
+	Path   string
+	Action SyncAction
+	// Why the file is considered conflicting, for ConflictAction.
+	Reason string
+}
+
+// Compute the actions that bring the local directory and the repository path in sync.
+// base holds the SHA1 of each file at the end of the previous sync. It tells which side changed a file, and whether a missing file was deleted or is new.
+// Without a base, a file that differs between the two sides is a conflict.
+func createSyncPlan(local, remote map[string]*syncFile, base map[string]string, policy ConflictPolicy) ([]SyncPlanItem, error) {
+	paths := make(map[string]bool, len(local)+len(remote))
+	for path := range local {
+		paths[path] = true
+	}
+	for path := range remote {
+		paths[path] = true
+	}
+	var plan []SyncPlanItem
+	for path := range paths {
+		action, reason := planFile(local[path], remote[path], base[path])
+		if action == ConflictAction {
+			action = resolveConflict(local[path], remote[path], policy)
+		}
+		if action == "" {
+			continue
+		}
+		if action == ConflictAction && policy == FailOnConflict {
+			return nil, errorutils.CheckError(fmt.Errorf("the file %s has conflicting changes: %s", path, reason))
+		}
+		plan = append(plan, SyncPlanItem{Path: path, Action: action, Reason: reason})
+	}
+	sort.Slice(plan, func(i, j int) bool {
+		return plan[i].Path < plan[j].Path
+	})
+	return plan, nil
+}
+
+// Return the action for a file, or an empty action if it is in sync.
+func planFile(local, remote *syncFile, baseSha1 string) (SyncAction, string) {
+	switch {
+	case local != nil && remote != nil:
+		if local.Sha1 == remote.Sha1 {
+			return "", ""
+		}
+		if baseSha1 == local.Sha1 {
+			return DownloadAction, ""
+		}
+		if baseSha1 == remote.Sha1 {
+			return UploadAction, ""
+		}
+		return ConflictAction, "modified on both sides"
+	case local != nil:
+		if baseSha1 == "" {
+			return UploadAction, ""
+		}
+		if baseSha1 == local.Sha1 {
+			return DeleteLocalAction, ""
+		}
+		return ConflictAction, "modified locally and deleted from the repository"
+	case remote != nil:
+		if baseSha1 == "" {
+			return DownloadAction, ""
+		}
+		if baseSha1 == remote.Sha1 {
+			return DeleteRemoteAction, ""
+		}
+		return ConflictAction, "deleted locally and modified in the repository"
+	}
+	return "", ""
+}
+
+func resolveConflict(local, remote *syncFile, policy ConflictPolicy) SyncAction {
+	switch policy {
+	case LocalWins:
+		if local == nil {
+			return DeleteRemoteAction
+		}
+		return UploadAction
+	case RemoteWins:
+		if remote == nil {
+			return DeleteLocalAction
+		}
+		return DownloadAction
+	case NewerWins:
+		if local == nil {
+			return DownloadAction
+		}
+		if remote == nil || !remote.Modified.After(local.Modified) {
+			return UploadAction
+		}
+		return DownloadAction
+	}
+	return ConflictAction
+}
+
+// Return the number of plan items per action.
+func countSyncActions(plan []SyncPlanItem) map[SyncAction]int {
+	counts := make(map[SyncAction]int)
+	for _, item := range plan {
+		counts[item.Action]++
+	}
+	return counts
+}
+
+func formatSyncPlan(plan []SyncPlanItem) string {
+	if len(plan) == 0 {
+		return "The local directory and the repository path are in sync."
+	}
+	var lines []string
+	for _, item := range plan {
+		line := fmt.Sprintf("%-14s %s", item.Action, item.Path)
+		if item.Reason != "" {
+			line += " (" + item.Reason + ")"
+		}
+		lines = append(lines, line)
+	}
+	counts := countSyncActions(plan)
+	lines = append(lines, fmt.Sprintf("%d to upload, %d to download, %d to delete locally, %d to delete from the repository, %d conflicts.",
+		counts[UploadAction], counts[DownloadAction], counts[DeleteLocalAction], counts[DeleteRemoteAction], counts[ConflictAction]))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,86 @@
+package generic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSyncPlan(t *testing.T) {
+	older := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	local := map[string]*syncFile{
+		"same.txt":            {Sha1: "s"},
+		"new-local.txt":       {Sha1: "n"},
+		"changed-local.txt":   {Sha1: "l2"},
+		"changed-remote.txt":  {Sha1: "r1"},
+		"deleted-remote.txt":  {Sha1: "d"},
+		"both-changed.txt":    {Sha1: "b-local", Modified: newer},
+		"modified-vs-deleted": {Sha1: "m2"},
+	}
+	remote := map[string]*syncFile{
+		"same.txt":           {Sha1: "s"},
+		"new-remote.txt":     {Sha1: "n"},
+		"changed-local.txt":  {Sha1: "l1"},
+		"changed-remote.txt": {Sha1: "r2"},
+		"deleted-local.txt":  {Sha1: "d"},
+		"both-changed.txt":   {Sha1: "b-remote", Modified: older},
+	}
+	base := map[string]string{
+		"same.txt":            "s",
+		"changed-local.txt":   "l1",
+		"changed-remote.txt":  "r1",
+		"deleted-remote.txt":  "d",
+		"deleted-local.txt":   "d",
+		"both-changed.txt":    "b",
+		"modified-vs-deleted": "m1",
+	}
+
+	plan, err := createSyncPlan(local, remote, base, SkipConflicts)
+	assert.NoError(t, err)
+	assert.Equal(t, []SyncPlanItem{
+		{Path: "both-changed.txt", Action: ConflictAction, Reason: "modified on both sides"},
+		{Path: "changed-local.txt", Action: UploadAction},
+		{Path: "changed-remote.txt", Action: DownloadAction},
+		{Path: "deleted-local.txt", Action: DeleteRemoteAction},
+		{Path: "deleted-remote.txt", Action: DeleteLocalAction},
+		{Path: "modified-vs-deleted", Action: ConflictAction, Reason: "modified locally and deleted from the repository"},
+		{Path: "new-local.txt", Action: UploadAction},
+		{Path: "new-remote.txt", Action: DownloadAction},
+	}, plan)
+
+	plan, err = createSyncPlan(local, remote, base, RemoteWins)
+	assert.NoError(t, err)
+	assert.Equal(t, SyncPlanItem{Path: "both-changed.txt", Action: DownloadAction, Reason: "modified on both sides"}, plan[0])
+	assert.Equal(t, DeleteLocalAction, plan[5].Action)
+
+	plan, err = createSyncPlan(local, remote, base, NewerWins)
+	assert.NoError(t, err)
+	assert.Equal(t, UploadAction, plan[0].Action)
+	// A modified file wins over a deleted one.
+	assert.Equal(t, UploadAction, plan[5].Action)
+
+	_, err = createSyncPlan(local, remote, base, FailOnConflict)
+	assert.Error(t, err)
+}
+
+func TestCreateSyncPlanWithoutState(t *testing.T) {
+	local := map[string]*syncFile{"a": {Sha1: "1"}, "b": {Sha1: "2"}}
+	remote := map[string]*syncFile{"b": {Sha1: "3"}, "c": {Sha1: "4"}}
+	plan, err := createSyncPlan(local, remote, nil, LocalWins)
+	assert.NoError(t, err)
+	assert.Equal(t, []SyncPlanItem{
+		{Path: "a", Action: UploadAction},
+		{Path: "b", Action: UploadAction, Reason: "modified on both sides"},
+		{Path: "c", Action: DownloadAction},
+	}, plan)
+}
+
+func TestGetConflictPolicy(t *testing.T) {
+	policy, err := GetConflictPolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, SkipConflicts, policy)
+	_, err = GetConflictPolicy("mine")
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package helm
+
+import (
+	"os"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils/helm"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// Package a Helm chart directory into a chart archive.
+type HelmPackageCommand struct {
+	chartDir    string
+	destination string
+	archivePath string
+}
+
+func NewHelmPackageCommand() *HelmPackageCommand {
+	return &HelmPackageCommand{}
+}
+
+func (hpc *HelmPackageCommand) SetChartDir(chartDir string) *HelmPackageCommand {
+	hpc.chartDir = chartDir
+	return hpc
+}
+
+// The directory to save the chart archive in. If not set, the working directory is used.
+func (hpc *HelmPackageCommand) SetDestination(destination string) *HelmPackageCommand {
+	hpc.destination = destination
+	return hpc
+}
+
+// The path to the created chart archive. Available after Run.
+func (hpc *HelmPackageCommand) ArchivePath() string {
+	return hpc.archivePath
+}
+
+func (hpc *HelmPackageCommand) Run() (err error) {
+	if hpc.chartDir == "" {
+		hpc.chartDir = "."
+	}
+	if hpc.destination == "" {
+		if hpc.destination, err = os.Getwd(); err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	hpc.archivePath, err = helm.Package(hpc.chartDir, hpc.destination)
+	return
+}
+
+func (hpc *HelmPackageCommand) CommandName() string {
+	return "rt_helm_package"
+}
+
+func (hpc *HelmPackageCommand) ServerDetails() (*config.ServerDetails, error) {
+	return nil, nil
+}
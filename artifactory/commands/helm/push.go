@@ -0,0 +1,223 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	commandsutils "github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils/helm"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	specutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Deploy a Helm chart to a Helm repository in Artifactory.
+// When build name and number are provided, the chart is recorded as a build-info artifact,
+// and its Chart.lock dependencies and the container images it references by digest are recorded as dependencies.
+type HelmPushCommand struct {
+	chartPath          string
+	repo               string
+	serverDetails      *config.ServerDetails
+	buildConfiguration *utils.BuildConfiguration
+	detailedSummary    bool
+	result             *commandsutils.Result
+}
+
+func NewHelmPushCommand() *HelmPushCommand {
+	return &HelmPushCommand{result: new(commandsutils.Result)}
+}
+
+// A chart directory, which is packaged before deployment, or a chart archive.
+func (hpc *HelmPushCommand) SetChartPath(chartPath string) *HelmPushCommand {
+	hpc.chartPath = chartPath
+	return hpc
+}
+
+func (hpc *HelmPushCommand) SetRepo(repo string) *HelmPushCommand {
+	hpc.repo = repo
+	return hpc
+}
+
+func (hpc *HelmPushCommand) SetServerDetails(serverDetails *config.ServerDetails) *HelmPushCommand {
+	hpc.serverDetails = serverDetails
+	return hpc
+}
+
+func (hpc *HelmPushCommand) SetBuildConfiguration(buildConfiguration *utils.BuildConfiguration) *HelmPushCommand {
+	hpc.buildConfiguration = buildConfiguration
+	return hpc
+}
+
+func (hpc *HelmPushCommand) SetDetailedSummary(detailedSummary bool) *HelmPushCommand {
+	hpc.detailedSummary = detailedSummary
+	return hpc
+}
+
+func (hpc *HelmPushCommand) IsDetailedSummary() bool {
+	return hpc.detailedSummary
+}
+
+func (hpc *HelmPushCommand) Result() *commandsutils.Result {
+	return hpc.result
+}
+
+func (hpc *HelmPushCommand) Run() (err error) {
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if deferErr := fileutils.RemoveTempDir(tempDir); err == nil {
+			err = deferErr
+		}
+	}()
+	chartDir, archivePath, err := prepareChart(hpc.chartPath, tempDir)
+	if err != nil {
+		return err
+	}
+	chart, err := helm.LoadChart(chartDir)
+	if err != nil {
+		return err
+	}
+	servicesManager, err := utils.CreateServiceManager(hpc.serverDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	collectBuildInfo := hpc.buildConfiguration != nil && hpc.buildConfiguration.BuildName != "" && hpc.buildConfiguration.BuildNumber != ""
+	artifacts, err := hpc.deploy(servicesManager, archivePath, collectBuildInfo)
+	if err != nil || !collectBuildInfo {
+		return err
+	}
+	dependencies, err := helm.CreateChartDependencies(chartDir)
+	if err != nil {
+		return err
+	}
+	imageDependencies, err := createImageDependencies(chartDir, servicesManager)
+	if err != nil {
+		return err
+	}
+	dependencies = append(dependencies, imageDependencies...)
+	populateFunc := func(partial *buildinfo.Partial) {
+		partial.Artifacts = artifacts
+		partial.Dependencies = dependencies
+		if hpc.buildConfiguration.Module == "" {
+			hpc.buildConfiguration.Module = chart.BuildInfoModuleId()
+		}
+		partial.ModuleId = hpc.buildConfiguration.Module
+		partial.ModuleType = helm.ModuleType
+	}
+	return utils.SavePartialBuildInfo(hpc.buildConfiguration.BuildName, hpc.buildConfiguration.BuildNumber, hpc.buildConfiguration.Project, populateFunc)
+}
+
+// Return the chart directory and archive. A chart directory is packaged, and a chart archive is extracted, into tempDir.
+func prepareChart(chartPath, tempDir string) (chartDir, archivePath string, err error) {
+	if chartPath == "" {
+		chartPath = "."
+	}
+	info, err := os.Stat(chartPath)
+	if err != nil {
+		return "", "", errorutils.CheckError(err)
+	}
+	if info.IsDir() {
+		archivePath, err = helm.Package(chartPath, tempDir)
+		return chartPath, archivePath, err
+	}
+	chartDir, err = helm.ExtractChart(chartPath, tempDir)
+	return chartDir, chartPath, err
+}
+
+// Upload the chart archive to the root of the Helm repository, where Artifactory indexes it.
+func (hpc *HelmPushCommand) deploy(servicesManager artifactory.ArtifactoryServicesManager, archivePath string, collectBuildInfo bool) (artifacts []buildinfo.Artifact, err error) {
+	log.Info(fmt.Sprintf("Deploying %s to %s...", archivePath, hpc.repo))
+	up := services.UploadParams{}
+	up.CommonParams = &specutils.CommonParams{Pattern: archivePath, Target: hpc.repo + "/"}
+	up.Flat = true
+	if collectBuildInfo {
+		if err = utils.SaveBuildGeneralDetails(hpc.buildConfiguration.BuildName, hpc.buildConfiguration.BuildNumber, hpc.buildConfiguration.Project); err != nil {
+			return
+		}
+		up.BuildProps, err = utils.CreateBuildProperties(hpc.buildConfiguration.BuildName, hpc.buildConfiguration.BuildNumber, hpc.buildConfiguration.Project)
+		if err != nil {
+			return
+		}
+	}
+	summary, err := servicesManager.UploadFilesWithSummary(up)
+	if err != nil {
+		return
+	}
+	defer summary.ArtifactsDetailsReader.Close()
+	if hpc.detailedSummary {
+		hpc.result.SetReader(summary.TransferDetailsReader)
+	} else {
+		summary.TransferDetailsReader.Close()
+	}
+	hpc.result.SetSuccessCount(summary.TotalSucceeded)
+	hpc.result.SetFailCount(summary.TotalFailed)
+	if summary.TotalFailed > 0 {
+		return nil, errorutils.CheckError(errors.New("Failed to upload the Helm chart to Artifactory. See Artifactory logs for more details."))
+	}
+	if !collectBuildInfo {
+		return
+	}
+	artifacts, err = specutils.ConvertArtifactsDetailsToBuildInfoArtifacts(summary.ArtifactsDetailsReader)
+	for i := range artifacts {
+		artifacts[i].Type = "tgz"
+	}
+	return
+}
+
+// Create build-info dependencies of the container images referenced by the chart.
+// The images are looked up in Artifactory by their manifest digest, to record their checksums.
+func createImageDependencies(chartDir string, servicesManager artifactory.ArtifactoryServicesManager) ([]buildinfo.Dependency, error) {
+	references, err := helm.FindImageReferences(chartDir)
+	if err != nil {
+		return nil, err
+	}
+	var dependencies []buildinfo.Dependency
+	for _, reference := range references {
+		dependency := buildinfo.Dependency{Id: reference.Name + "@" + reference.Digest, Type: helm.ImageDependencyType}
+		manifest, err := searchImageManifest(reference.Digest, servicesManager)
+		if err != nil {
+			return nil, err
+		}
+		if manifest != nil {
+			dependency.Checksum = &buildinfo.Checksum{Sha1: manifest.Actual_Sha1, Md5: manifest.Actual_Md5}
+		} else {
+			log.Warn("The image " + dependency.Id + " was not found in Artifactory. Its checksums will not be recorded.")
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, nil
+}
+
+// Search for the manifest of an image by its digest. Multi-platform images are referenced by the digest of their list.manifest.json.
+func searchImageManifest(digest string, servicesManager artifactory.ArtifactoryServicesManager) (*specutils.ResultItem, error) {
+	searchParams := services.NewSearchParams()
+	query := fmt.Sprintf(`{"$or":[{"name":"manifest.json"},{"name":"list.manifest.json"}],"@docker.manifest.digest":"%s"}`, digest)
+	searchParams.CommonParams = &specutils.CommonParams{Aql: specutils.Aql{ItemsFind: query}}
+	reader, err := servicesManager.SearchFiles(searchParams)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	item := new(specutils.ResultItem)
+	if reader.NextRecord(item) != nil {
+		return nil, reader.GetError()
+	}
+	return item, nil
+}
+
+func (hpc *HelmPushCommand) CommandName() string {
+	return "rt_helm_push"
+}
+
+func (hpc *HelmPushCommand) ServerDetails() (*config.ServerDetails, error) {
+	return hpc.serverDetails, nil
+}
@@ -0,0 +1,152 @@
+package permissiontarget
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
+	rtUtils "github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Export existing permission targets as templates, which can be used by the permission target create and update commands.
+type PermissionTargetExportCommand struct {
+	serverDetails *config.ServerDetails
+	names         []string
+	outputDir     string
+	vars          string
+}
+
+func NewPermissionTargetExportCommand() *PermissionTargetExportCommand {
+	return &PermissionTargetExportCommand{}
+}
+
+func (ptec *PermissionTargetExportCommand) SetServerDetails(serverDetails *config.ServerDetails) *PermissionTargetExportCommand {
+	ptec.serverDetails = serverDetails
+	return ptec
+}
+
+// The permission targets to export. If empty, all the permission targets are exported.
+func (ptec *PermissionTargetExportCommand) SetNames(names []string) *PermissionTargetExportCommand {
+	ptec.names = names
+	return ptec
+}
+
+// The directory in which a <name>.json template is created for each permission target.
+func (ptec *PermissionTargetExportCommand) SetOutputDir(outputDir string) *PermissionTargetExportCommand {
+	ptec.outputDir = outputDir
+	return ptec
+}
+
+func (ptec *PermissionTargetExportCommand) SetVars(vars string) *PermissionTargetExportCommand {
+	ptec.vars = vars
+	return ptec
+}
+
+func (ptec *PermissionTargetExportCommand) ServerDetails() (*config.ServerDetails, error) {
+	return ptec.serverDetails, nil
+}
+
+func (ptec *PermissionTargetExportCommand) CommandName() string {
+	return "rt_permission_target_export"
+}
+
+func (ptec *PermissionTargetExportCommand) Run() error {
+	servicesManager, err := rtUtils.CreateServiceManager(ptec.serverDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	names := ptec.names
+	if len(names) == 0 {
+		if names, err = getAllPermissionTargetNames(servicesManager); err != nil {
+			return err
+		}
+	}
+	var templates []utils.ExportedTemplate
+	for _, name := range names {
+		params, err := servicesManager.GetPermissionTarget(name)
+		if err != nil {
+			return err
+		}
+		if params == nil {
+			return errorutils.CheckError(fmt.Errorf("the permission target '%s' does not exist", name))
+		}
+		templates = append(templates, utils.ExportedTemplate{Name: name, Template: createPermissionTargetTemplate(params)})
+	}
+	if err = utils.ExportTemplates(ptec.outputDir, templates, ptec.vars); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Exported %d permission targets.", len(names)))
+	return nil
+}
+
+func getAllPermissionTargetNames(servicesManager artifactory.ArtifactoryServicesManager) ([]string, error) {
+	artDetails := servicesManager.GetConfig().GetServiceDetails()
+	httpDetails := artDetails.CreateHttpClientDetails()
+	resp, body, _, err := servicesManager.Client().SendGet(artDetails.GetUrl()+"api/v2/security/permissions", true, &httpDetails)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorutils.CheckError(errors.New("Artifactory response: " + resp.Status + "\n" + clientutils.IndentJson(body)))
+	}
+	var permissionTargets []struct {
+		Name string `json:"name"`
+	}
+	if err = json.Unmarshal(body, &permissionTargets); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var names []string
+	for _, permissionTarget := range permissionTargets {
+		names = append(names, permissionTarget.Name)
+	}
+	return names, nil
+}
+
+// Convert a permission target, as returned by the server, to a template.
+func createPermissionTargetTemplate(params *services.PermissionTargetParams) map[string]interface{} {
+	template := map[string]interface{}{Name: params.Name}
+	for key, section := range map[string]*services.PermissionTargetSection{Repo: params.Repo, Build: params.Build, ReleaseBundle: params.ReleaseBundle} {
+		if section != nil {
+			template[key] = createPermissionSectionAnswer(section, key == Build)
+		}
+	}
+	return template
+}
+
+// The reverse of covertPermissionSection.
+func createPermissionSectionAnswer(section *services.PermissionTargetSection, isBuildSection bool) *PermissionSectionAnswer {
+	answer := &PermissionSectionAnswer{
+		IncludePatterns: strings.Join(section.IncludePatterns, ","),
+		ExcludePatterns: strings.Join(section.ExcludePatterns, ","),
+	}
+	// The repositories of the 'build' section have a default value which cannot be changed.
+	if !isBuildSection {
+		answer.Repositories = strings.Join(section.Repositories, ",")
+	}
+	if section.Actions != nil {
+		answer.ActionsUsers = joinActionMap(section.Actions.Users)
+		answer.ActionsGroups = joinActionMap(section.Actions.Groups)
+	}
+	return answer
+}
+
+// The reverse of convertActionMap.
+func joinActionMap(srcMap map[string][]string) map[string]string {
+	if len(srcMap) == 0 {
+		return nil
+	}
+	tgtMap := make(map[string]string)
+	for key, permissions := range srcMap {
+		tgtMap[key] = strings.Join(permissions, ",")
+	}
+	return tgtMap
+}
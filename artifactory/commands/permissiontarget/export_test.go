@@ -0,0 +1,45 @@
+package permissiontarget
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePermissionTargetTemplate(t *testing.T) {
+	params := &services.PermissionTargetParams{
+		Name: "deployers",
+		Repo: &services.PermissionTargetSection{
+			IncludePatterns: []string{"**"},
+			ExcludePatterns: []string{"tmp/**", "*.bak"},
+			Repositories:    []string{"libs-release-local", "libs-snapshot-local"},
+			Actions: &services.Actions{
+				Users:  map[string][]string{"deployer": {"read", "write"}},
+				Groups: map[string][]string{"readers": {"read"}},
+			},
+		},
+		Build: &services.PermissionTargetSection{
+			IncludePatterns: []string{"**"},
+			Repositories:    []string{DefaultBuildRepositoriesValue},
+			Actions:         &services.Actions{Users: map[string][]string{"ci": {"read", "write", "manage"}}},
+		},
+	}
+	template := createPermissionTargetTemplate(params)
+	assert.Equal(t, "deployers", template[Name])
+	assert.NotContains(t, template, ReleaseBundle)
+	assert.Equal(t, &PermissionSectionAnswer{
+		Repositories:    "libs-release-local,libs-snapshot-local",
+		IncludePatterns: "**",
+		ExcludePatterns: "tmp/**,*.bak",
+		ActionsUsers:    map[string]string{"deployer": "read,write"},
+		ActionsGroups:   map[string]string{"readers": "read"},
+	}, template[Repo])
+
+	// Converting the template back results in the original permission target.
+	for key, expected := range map[string]*services.PermissionTargetSection{Repo: params.Repo, Build: params.Build} {
+		section, err := covertPermissionSection(template[key], key == Build)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, section)
+	}
+}
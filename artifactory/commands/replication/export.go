@@ -0,0 +1,177 @@
+package replication
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
+	rtUtils "github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	clientServicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Written as the server ID of push replications whose target is not one of the configured servers.
+const ServerIdVar = "${serverId}"
+
+// Export the replication jobs of existing repositories as templates, which can be used by the replication create command.
+type ReplicationExportCommand struct {
+	serverDetails *config.ServerDetails
+	repoKeys      []string
+	outputDir     string
+	vars          string
+}
+
+func NewReplicationExportCommand() *ReplicationExportCommand {
+	return &ReplicationExportCommand{}
+}
+
+func (rec *ReplicationExportCommand) SetServerDetails(serverDetails *config.ServerDetails) *ReplicationExportCommand {
+	rec.serverDetails = serverDetails
+	return rec
+}
+
+// The repositories whose replication jobs are exported. If empty, the replication jobs of all the repositories are exported.
+func (rec *ReplicationExportCommand) SetRepoKeys(repoKeys []string) *ReplicationExportCommand {
+	rec.repoKeys = repoKeys
+	return rec
+}
+
+// The directory in which a <repo-key>-replication.json template is created for each replication job.
+func (rec *ReplicationExportCommand) SetOutputDir(outputDir string) *ReplicationExportCommand {
+	rec.outputDir = outputDir
+	return rec
+}
+
+func (rec *ReplicationExportCommand) SetVars(vars string) *ReplicationExportCommand {
+	rec.vars = vars
+	return rec
+}
+
+func (rec *ReplicationExportCommand) ServerDetails() (*config.ServerDetails, error) {
+	return rec.serverDetails, nil
+}
+
+func (rec *ReplicationExportCommand) CommandName() string {
+	return "rt_replication_export"
+}
+
+func (rec *ReplicationExportCommand) Run() error {
+	servicesManager, err := rtUtils.CreateServiceManager(rec.serverDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	repoKeys := rec.repoKeys
+	if len(repoKeys) == 0 {
+		if repoKeys, err = getReplicatedRepoKeys(servicesManager); err != nil {
+			return err
+		}
+	}
+	// Push replications are exported with the ID of the configured server they replicate to.
+	servers, err := config.GetAllServersConfigs()
+	if err != nil {
+		return err
+	}
+	var templates []utils.ExportedTemplate
+	for _, repoKey := range repoKeys {
+		replications, err := servicesManager.GetReplication(repoKey)
+		if err != nil {
+			return err
+		}
+		for i, replication := range replications {
+			name := repoKey + "-replication"
+			if len(replications) > 1 {
+				name = fmt.Sprintf("%s-replication-%d", repoKey, i+1)
+			}
+			templates = append(templates, utils.ExportedTemplate{Name: name, Template: createReplicationTemplate(replication, servers)})
+		}
+	}
+	if err = utils.ExportTemplates(rec.outputDir, templates, rec.vars); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Exported %d replication jobs.", len(templates)))
+	return nil
+}
+
+// Return the keys of the repositories which have replication jobs.
+func getReplicatedRepoKeys(servicesManager artifactory.ArtifactoryServicesManager) ([]string, error) {
+	artDetails := servicesManager.GetConfig().GetServiceDetails()
+	httpDetails := artDetails.CreateHttpClientDetails()
+	resp, body, _, err := servicesManager.Client().SendGet(artDetails.GetUrl()+"api/replications", true, &httpDetails)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorutils.CheckError(errors.New("Artifactory response: " + resp.Status + "\n" + clientutils.IndentJson(body)))
+	}
+	var replications []struct {
+		RepoKey string `json:"repoKey"`
+	}
+	if err = json.Unmarshal(body, &replications); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var repoKeys []string
+	exported := make(map[string]bool)
+	for _, replication := range replications {
+		if !exported[replication.RepoKey] {
+			exported[replication.RepoKey] = true
+			repoKeys = append(repoKeys, replication.RepoKey)
+		}
+	}
+	return repoKeys, nil
+}
+
+// Convert a replication job, as returned by the server, to a template.
+// Pull replications have no URL. The URL of a push replication is converted to the ID of the configured server
+// it points to and the key of the target repository, which are used by the replication create command to rebuild it.
+func createReplicationTemplate(replication clientServicesUtils.ReplicationParams, servers []*config.ServerDetails) map[string]string {
+	template := map[string]string{
+		RepoKey:                replication.RepoKey,
+		EnableEventReplication: strconv.FormatBool(replication.EnableEventReplication),
+		Enabled:                strconv.FormatBool(replication.Enabled),
+		SyncDeletes:            strconv.FormatBool(replication.SyncDeletes),
+		SyncProperties:         strconv.FormatBool(replication.SyncProperties),
+		SyncStatistics:         strconv.FormatBool(replication.SyncStatistics),
+	}
+	if replication.CronExp != "" {
+		template[CronExp] = replication.CronExp
+	}
+	if replication.PathPrefix != "" {
+		template[PathPrefix] = replication.PathPrefix
+	}
+	if replication.SocketTimeoutMillis > 0 {
+		template[SocketTimeoutMillis] = strconv.Itoa(replication.SocketTimeoutMillis)
+	}
+	if replication.Url != "" {
+		serverId, targetRepoKey := matchReplicationTarget(replication.Url, servers)
+		if serverId == "" {
+			log.Warn(fmt.Sprintf("The replication target %s of the repository '%s' is not one of the configured servers. "+
+				"The template will include the %s variable, which should be replaced with a server ID.", replication.Url, replication.RepoKey, ServerIdVar))
+			serverId = ServerIdVar
+		}
+		template[ServerId] = serverId
+		template[TargetRepoKey] = targetRepoKey
+	}
+	return template
+}
+
+// Find the configured server whose Artifactory URL is a prefix of the replication URL.
+// Returns an empty server ID if there is no such server. The target repository key is the last part of the URL in any case.
+func matchReplicationTarget(replicationUrl string, servers []*config.ServerDetails) (serverId, targetRepoKey string) {
+	replicationUrl = strings.TrimSuffix(replicationUrl, "/")
+	targetRepoKey = replicationUrl[strings.LastIndex(replicationUrl, "/")+1:]
+	for _, server := range servers {
+		artifactoryUrl := strings.TrimSuffix(server.GetArtifactoryUrl(), "/")
+		if artifactoryUrl != "" && replicationUrl == artifactoryUrl+"/"+targetRepoKey {
+			return server.ServerId, targetRepoKey
+		}
+	}
+	return "", targetRepoKey
+}
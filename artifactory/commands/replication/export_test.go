@@ -0,0 +1,91 @@
+package replication
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientServicesUtils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var replicationServers = []*config.ServerDetails{
+	{ServerId: "dev", ArtifactoryUrl: "https://dev.example.com/artifactory/"},
+	{ServerId: "prod", ArtifactoryUrl: "https://prod.example.com/artifactory/"},
+}
+
+func TestMatchReplicationTarget(t *testing.T) {
+	tests := []struct {
+		url           string
+		serverId      string
+		targetRepoKey string
+	}{
+		{"https://prod.example.com/artifactory/libs-release", "prod", "libs-release"},
+		{"https://prod.example.com/artifactory/libs-release/", "prod", "libs-release"},
+		{"https://other.example.com/artifactory/libs-release", "", "libs-release"},
+		// The target must be a repository directly under the Artifactory URL.
+		{"https://prod.example.com/artifactory/api/libs-release", "", "libs-release"},
+	}
+	for _, test := range tests {
+		serverId, targetRepoKey := matchReplicationTarget(test.url, replicationServers)
+		assert.Equal(t, test.serverId, serverId, test.url)
+		assert.Equal(t, test.targetRepoKey, targetRepoKey, test.url)
+	}
+}
+
+func TestCreateReplicationTemplate(t *testing.T) {
+	replication := clientServicesUtils.ReplicationParams{
+		Username:               "replicator",
+		Url:                    "https://prod.example.com/artifactory/libs-release",
+		CronExp:                "0 0 12 * * ?",
+		RepoKey:                "libs-release-local",
+		EnableEventReplication: true,
+		SocketTimeoutMillis:    15000,
+		Enabled:                true,
+		SyncProperties:         true,
+		PathPrefix:             "org/",
+	}
+	template := createReplicationTemplate(replication, replicationServers)
+	assert.Equal(t, map[string]string{
+		RepoKey:                "libs-release-local",
+		ServerId:               "prod",
+		TargetRepoKey:          "libs-release",
+		CronExp:                "0 0 12 * * ?",
+		EnableEventReplication: "true",
+		Enabled:                "true",
+		SyncDeletes:            "false",
+		SyncProperties:         "true",
+		SyncStatistics:         "false",
+		PathPrefix:             "org/",
+		SocketTimeoutMillis:    "15000",
+	}, template)
+
+	// Converting the template back, as the replication create command does, results in the original replication.
+	configMap := make(map[string]interface{})
+	for key, value := range template {
+		if key != ServerId {
+			assert.NoError(t, writersMap[key](&configMap, key, value))
+		}
+	}
+	content, err := json.Marshal(configMap)
+	assert.NoError(t, err)
+	var params services.CreateReplicationParams
+	assert.NoError(t, json.Unmarshal(content, &params))
+	params.Url = strings.TrimSuffix(replicationServers[1].ArtifactoryUrl, "/") + "/" + template[TargetRepoKey]
+	// The username and password are taken from the server configuration.
+	params.Username = replication.Username
+	assert.Equal(t, replication, params.ReplicationParams)
+
+	// Pull replications have no target.
+	replication.Url = ""
+	template = createReplicationTemplate(replication, replicationServers)
+	assert.NotContains(t, template, ServerId)
+	assert.NotContains(t, template, TargetRepoKey)
+
+	// A target which is not one of the configured servers is exported with a variable.
+	replication.Url = "https://other.example.com/artifactory/libs-release"
+	template = createReplicationTemplate(replication, replicationServers)
+	assert.Equal(t, ServerIdVar, template[ServerId])
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
+	rtUtils "github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The password of a remote repository cannot be read from the server, so a placeholder is written instead.
+const PasswordVar = "${password}"
+
+// Export the configuration of existing repositories as templates, which can be used by the repo create and update commands.
+type RepoExportCommand struct {
+	serverDetails *config.ServerDetails
+	repoKeys      []string
+	outputDir     string
+	vars          string
+}
+
+func NewRepoExportCommand() *RepoExportCommand {
+	return &RepoExportCommand{}
+}
+
+func (rec *RepoExportCommand) SetServerDetails(serverDetails *config.ServerDetails) *RepoExportCommand {
+	rec.serverDetails = serverDetails
+	return rec
+}
+
+// The repositories to export. If empty, all the repositories are exported.
+func (rec *RepoExportCommand) SetRepoKeys(repoKeys []string) *RepoExportCommand {
+	rec.repoKeys = repoKeys
+	return rec
+}
+
+// The directory in which a <repo-key>.json template is created for each repository.
+func (rec *RepoExportCommand) SetOutputDir(outputDir string) *RepoExportCommand {
+	rec.outputDir = outputDir
+	return rec
+}
+
+func (rec *RepoExportCommand) SetVars(vars string) *RepoExportCommand {
+	rec.vars = vars
+	return rec
+}
+
+func (rec *RepoExportCommand) ServerDetails() (*config.ServerDetails, error) {
+	return rec.serverDetails, nil
+}
+
+func (rec *RepoExportCommand) CommandName() string {
+	return "rt_repo_export"
+}
+
+func (rec *RepoExportCommand) Run() error {
+	servicesManager, err := rtUtils.CreateServiceManager(rec.serverDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	repoKeys := rec.repoKeys
+	if len(repoKeys) == 0 {
+		repos, err := servicesManager.GetAllRepositories()
+		if err != nil {
+			return err
+		}
+		for _, repo := range *repos {
+			repoKeys = append(repoKeys, repo.Key)
+		}
+	}
+	var templates []utils.ExportedTemplate
+	for _, repoKey := range repoKeys {
+		var repoDetails map[string]interface{}
+		if err = servicesManager.GetRepository(repoKey, &repoDetails); err != nil {
+			return err
+		}
+		templates = append(templates, utils.ExportedTemplate{Name: repoKey, Template: createRepoTemplate(repoDetails)})
+	}
+	if err = utils.ExportTemplates(rec.outputDir, templates, rec.vars); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Exported %d repositories.", len(repoKeys)))
+	return nil
+}
+
+// Convert a repository configuration, as returned by the server, to a template.
+// Only the keys supported by the templates are kept, and empty values are dropped.
+func createRepoTemplate(repoDetails map[string]interface{}) map[string]string {
+	template := make(map[string]string)
+	for key, value := range repoDetails {
+		if _, ok := writersMap[key]; !ok || key == Password {
+			continue
+		}
+		var templateValue string
+		var ok bool
+		if key == ContentSynchronisation {
+			templateValue, ok = contentSynchronisationToString(value)
+		} else {
+			templateValue, ok = utils.TemplateValueToString(value)
+		}
+		if !ok {
+			log.Warn(fmt.Sprintf("The value of the key '%s' of the repository '%s' cannot be exported.", key, repoDetails[Key]))
+			continue
+		}
+		if templateValue != "" {
+			template[key] = templateValue
+		}
+	}
+	if template[Username] != "" {
+		template[Password] = PasswordVar
+	}
+	return template
+}
+
+// The reverse of writeContentSynchronisation.
+func contentSynchronisationToString(value interface{}) (string, bool) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	var cs services.ContentSynchronisation
+	if err = json.Unmarshal(content, &cs); err != nil {
+		return "", false
+	}
+	return strings.Join([]string{strconv.FormatBool(cs.Enabled), strconv.FormatBool(cs.Statistics.Enabled),
+		strconv.FormatBool(cs.Properties.Enabled), strconv.FormatBool(cs.Source.OriginAbsenceDetection)}, ","), true
+}
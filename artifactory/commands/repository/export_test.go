@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+)
+
+const remoteRepoDetails = `{
+  "key": "npm-remote",
+  "rclass": "remote",
+  "packageType": "npm",
+  "url": "https://registry.npmjs.org",
+  "username": "reader",
+  "password": "",
+  "description": "",
+  "repoLayoutRef": "npm-default",
+  "hardFail": false,
+  "offline": true,
+  "socketTimeoutMillis": 15000,
+  "propertySets": ["artifactory"],
+  "contentSynchronisation": {"enabled": true, "statistics": {"enabled": false}, "properties": {"enabled": true}, "source": {"originAbsenceDetection": true}},
+  "unknownKey": "dropped",
+  "nested": {"key": "value"}
+}`
+
+func TestCreateRepoTemplate(t *testing.T) {
+	var repoDetails map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(remoteRepoDetails), &repoDetails))
+	template := createRepoTemplate(repoDetails)
+	assert.Equal(t, map[string]string{
+		Key:                    "npm-remote",
+		Rclass:                 "remote",
+		PackageType:            "npm",
+		Url:                    "https://registry.npmjs.org",
+		Username:               "reader",
+		Password:               PasswordVar,
+		RepoLayoutRef:          "npm-default",
+		HardFail:               "false",
+		Offline:                "true",
+		SocketTimeoutMillis:    "15000",
+		PropertySets:           "artifactory",
+		ContentSynchronisation: "true,false,true,true",
+	}, template)
+
+	// Converting the template back results in the original values.
+	configMap := make(map[string]interface{})
+	for key, value := range template {
+		assert.NoError(t, writersMap[key](&configMap, key, value))
+	}
+	assert.Equal(t, 15000, configMap[SocketTimeoutMillis])
+	assert.Equal(t, []string{"artifactory"}, configMap[PropertySets])
+	assert.Equal(t, true, configMap[Offline])
+	var expectedCs services.ContentSynchronisation
+	content, err := json.Marshal(repoDetails[ContentSynchronisation])
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(content, &expectedCs))
+	assert.Equal(t, expectedCs, configMap[ContentSynchronisation])
+}
+
+func TestContentSynchronisationToString(t *testing.T) {
+	for _, value := range []string{"false,false,false,false", "true,true,true,true", "true,false,false,true"} {
+		configMap := make(map[string]interface{})
+		assert.NoError(t, writeContentSynchronisation(&configMap, ContentSynchronisation, value))
+		exported, ok := contentSynchronisationToString(configMap[ContentSynchronisation])
+		assert.True(t, ok)
+		assert.Equal(t, value, exported)
+	}
+	_, ok := contentSynchronisationToString("invalid")
+	assert.False(t, ok)
+}
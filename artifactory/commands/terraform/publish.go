@@ -0,0 +1,213 @@
+package terraform
+
+import (
+	"errors"
+	"path/filepath"
+
+	commandsutils "github.com/jfrog/jfrog-cli-core/v2/artifactory/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils/terraform"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	specutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Publish the Terraform modules found under a directory to a Terraform repository in Artifactory.
+// Each module is deployed as '<namespace>/<module-name>/<provider>/<tag>.zip'.
+// When build name and number are provided, each module is recorded as a build-info module,
+// with the providers locked in .terraform.lock.hcl and the installed remote modules as its dependencies.
+type TerraformPublishCommand struct {
+	namespace          string
+	provider           string
+	tag                string
+	repo               string
+	workingDir         string
+	serverDetails      *config.ServerDetails
+	buildConfiguration *utils.BuildConfiguration
+	detailedSummary    bool
+	result             *commandsutils.Result
+}
+
+func NewTerraformPublishCommand() *TerraformPublishCommand {
+	return &TerraformPublishCommand{result: new(commandsutils.Result)}
+}
+
+func (tpc *TerraformPublishCommand) SetNamespace(namespace string) *TerraformPublishCommand {
+	tpc.namespace = namespace
+	return tpc
+}
+
+func (tpc *TerraformPublishCommand) SetProvider(provider string) *TerraformPublishCommand {
+	tpc.provider = provider
+	return tpc
+}
+
+// The version of the published modules.
+func (tpc *TerraformPublishCommand) SetTag(tag string) *TerraformPublishCommand {
+	tpc.tag = tag
+	return tpc
+}
+
+func (tpc *TerraformPublishCommand) SetRepo(repo string) *TerraformPublishCommand {
+	tpc.repo = repo
+	return tpc
+}
+
+// The directory to search for modules in. If not set, the working directory is used.
+func (tpc *TerraformPublishCommand) SetWorkingDir(workingDir string) *TerraformPublishCommand {
+	tpc.workingDir = workingDir
+	return tpc
+}
+
+func (tpc *TerraformPublishCommand) SetServerDetails(serverDetails *config.ServerDetails) *TerraformPublishCommand {
+	tpc.serverDetails = serverDetails
+	return tpc
+}
+
+func (tpc *TerraformPublishCommand) SetBuildConfiguration(buildConfiguration *utils.BuildConfiguration) *TerraformPublishCommand {
+	tpc.buildConfiguration = buildConfiguration
+	return tpc
+}
+
+func (tpc *TerraformPublishCommand) SetDetailedSummary(detailedSummary bool) *TerraformPublishCommand {
+	tpc.detailedSummary = detailedSummary
+	return tpc
+}
+
+func (tpc *TerraformPublishCommand) IsDetailedSummary() bool {
+	return tpc.detailedSummary
+}
+
+func (tpc *TerraformPublishCommand) Result() *commandsutils.Result {
+	return tpc.result
+}
+
+func (tpc *TerraformPublishCommand) Run() (err error) {
+	if tpc.namespace == "" || tpc.provider == "" || tpc.tag == "" || tpc.repo == "" {
+		return errorutils.CheckError(errors.New("the namespace, provider, tag and repository are mandatory"))
+	}
+	if tpc.workingDir == "" {
+		tpc.workingDir = "."
+	}
+	modules, err := terraform.FindModules(tpc.workingDir)
+	if err != nil {
+		return err
+	}
+	if len(modules) == 0 {
+		return errorutils.CheckError(errors.New("no Terraform modules were found in " + tpc.workingDir))
+	}
+	servicesManager, err := utils.CreateServiceManager(tpc.serverDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	collectBuildInfo := tpc.buildConfiguration != nil && tpc.buildConfiguration.BuildName != "" && tpc.buildConfiguration.BuildNumber != ""
+	var buildProps string
+	if collectBuildInfo {
+		if err = utils.SaveBuildGeneralDetails(tpc.buildConfiguration.BuildName, tpc.buildConfiguration.BuildNumber, tpc.buildConfiguration.Project); err != nil {
+			return err
+		}
+		if buildProps, err = utils.CreateBuildProperties(tpc.buildConfiguration.BuildName, tpc.buildConfiguration.BuildNumber, tpc.buildConfiguration.Project); err != nil {
+			return err
+		}
+	}
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if deferErr := fileutils.RemoveTempDir(tempDir); err == nil {
+			err = deferErr
+		}
+	}()
+	// The transfer details of the published modules are closed once merged, or if a later module fails.
+	var transferDetails []*content.ContentReader
+	defer func() {
+		for _, reader := range transferDetails {
+			if closeErr := reader.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	}()
+	for _, module := range modules {
+		summary, err := tpc.publishModule(module, tempDir, buildProps, servicesManager, collectBuildInfo)
+		if err != nil {
+			return err
+		}
+		transferDetails = append(transferDetails, summary.TransferDetailsReader)
+		tpc.result.SetSuccessCount(tpc.result.SuccessCount() + summary.TotalSucceeded)
+	}
+	if !tpc.detailedSummary {
+		return nil
+	}
+	reader, err := content.MergeReaders(transferDetails, content.DefaultKey)
+	if err != nil {
+		return err
+	}
+	tpc.result.SetReader(reader)
+	return nil
+}
+
+// Zip and deploy a single module, and save its build-info if needed.
+// On failure, the transfer details of the module are closed.
+func (tpc *TerraformPublishCommand) publishModule(module *terraform.Module, tempDir, buildProps string, servicesManager artifactory.ArtifactoryServicesManager, collectBuildInfo bool) (summary *specutils.OperationSummary, err error) {
+	zipPath := filepath.Join(tempDir, module.Name+".zip")
+	if err = module.Zip(zipPath); err != nil {
+		return nil, err
+	}
+	target := tpc.repo + "/" + module.DeployPath(tpc.namespace, tpc.provider, tpc.tag)
+	log.Info("Publishing module " + module.Name + " to " + target)
+	up := services.UploadParams{}
+	up.CommonParams = &specutils.CommonParams{Pattern: zipPath, Target: target}
+	up.Flat = true
+	up.BuildProps = buildProps
+	summary, err = servicesManager.UploadFilesWithSummary(up)
+	if err != nil {
+		return nil, err
+	}
+	defer summary.ArtifactsDetailsReader.Close()
+	transferDetailsReader := summary.TransferDetailsReader
+	defer func() {
+		if err != nil {
+			transferDetailsReader.Close()
+		}
+	}()
+	if summary.TotalFailed > 0 {
+		return nil, errorutils.CheckError(errors.New("Failed to upload the Terraform module " + module.Name + " to Artifactory. See Artifactory logs for more details."))
+	}
+	if !collectBuildInfo {
+		return summary, nil
+	}
+	artifacts, err := specutils.ConvertArtifactsDetailsToBuildInfoArtifacts(summary.ArtifactsDetailsReader)
+	if err != nil {
+		return nil, err
+	}
+	for i := range artifacts {
+		artifacts[i].Type = "zip"
+	}
+	dependencies, err := terraform.CreateDependencies(module.Dir)
+	if err != nil {
+		return nil, err
+	}
+	moduleId := module.BuildInfoModuleId(tpc.namespace, tpc.provider, tpc.tag)
+	populateFunc := func(partial *buildinfo.Partial) {
+		partial.Artifacts = artifacts
+		partial.Dependencies = dependencies
+		partial.ModuleId = moduleId
+		partial.ModuleType = terraform.ModuleType
+	}
+	return summary, utils.SavePartialBuildInfo(tpc.buildConfiguration.BuildName, tpc.buildConfiguration.BuildNumber, tpc.buildConfiguration.Project, populateFunc)
+}
+
+func (tpc *TerraformPublishCommand) CommandName() string {
+	return "rt_terraform_publish"
+}
+
+func (tpc *TerraformPublishCommand) ServerDetails() (*config.ServerDetails, error) {
+	return tpc.serverDetails, nil
+}
@@ -0,0 +1,171 @@
+package usersmanagement
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+)
+
+type SecurityEntityKind string
+
+const (
+	UserEntity  SecurityEntityKind = "user"
+	GroupEntity SecurityEntityKind = "group"
+)
+
+type SecurityAction string
+
+const (
+	CreateAction SecurityAction = "create"
+	UpdateAction SecurityAction = "update"
+	DeleteAction SecurityAction = "delete"
+)
+
+// A change needed to bring a user or a group on the server to its desired state.
+type SecurityChange struct {
+	Kind   SecurityEntityKind `json:"kind"`
+	Name   string             `json:"name"`
+	Action SecurityAction     `json:"action"`
+	// The fields which differ, for updates.
+	Fields []string `json:"fields,omitempty"`
+	user   *services.User
+	group  *services.Group
+}
+
+// Compute the changes which bring the current state to the desired one.
+// Users and groups missing from the desired state are deleted only when reconciling, and protected users and groups are never deleted.
+// The changes are ordered so that they can be applied one by one: groups are created and updated before the users
+// which join them, and deleted after the users which leave them.
+func createSecurityPlan(desired, current *SecurityState, reconcile bool, protectedUsers, protectedGroups []string) []SecurityChange {
+	var groupChanges, userChanges, userDeletes, groupDeletes []SecurityChange
+	currentGroups := make(map[string]*services.Group)
+	for i := range current.Groups {
+		currentGroups[current.Groups[i].Name] = &current.Groups[i]
+	}
+	desiredGroups := make(map[string]bool)
+	for i := range desired.Groups {
+		group := &desired.Groups[i]
+		desiredGroups[group.Name] = true
+		existing, ok := currentGroups[group.Name]
+		if !ok {
+			groupChanges = append(groupChanges, SecurityChange{Kind: GroupEntity, Name: group.Name, Action: CreateAction, group: group})
+		} else if fields := diffGroups(group, existing); len(fields) > 0 {
+			groupChanges = append(groupChanges, SecurityChange{Kind: GroupEntity, Name: group.Name, Action: UpdateAction, Fields: fields, group: group})
+		}
+	}
+
+	currentUsers := make(map[string]*services.User)
+	for i := range current.Users {
+		currentUsers[current.Users[i].Name] = &current.Users[i]
+	}
+	desiredUsers := make(map[string]bool)
+	for i := range desired.Users {
+		user := &desired.Users[i]
+		desiredUsers[user.Name] = true
+		existing, ok := currentUsers[user.Name]
+		if !ok {
+			userChanges = append(userChanges, SecurityChange{Kind: UserEntity, Name: user.Name, Action: CreateAction, user: user})
+		} else if fields := diffUsers(user, existing); len(fields) > 0 {
+			userChanges = append(userChanges, SecurityChange{Kind: UserEntity, Name: user.Name, Action: UpdateAction, Fields: fields, user: user})
+		}
+	}
+
+	if reconcile {
+		protected := make(map[string]bool)
+		for _, name := range protectedUsers {
+			protected[name] = true
+		}
+		groupProtected := make(map[string]bool)
+		for _, name := range protectedGroups {
+			groupProtected[name] = true
+		}
+		for _, user := range current.Users {
+			if !desiredUsers[user.Name] && !protected[user.Name] {
+				userDeletes = append(userDeletes, SecurityChange{Kind: UserEntity, Name: user.Name, Action: DeleteAction})
+			}
+		}
+		for _, group := range current.Groups {
+			if !desiredGroups[group.Name] && !groupProtected[group.Name] {
+				groupDeletes = append(groupDeletes, SecurityChange{Kind: GroupEntity, Name: group.Name, Action: DeleteAction})
+			}
+		}
+	}
+	var plan []SecurityChange
+	for _, changes := range [][]SecurityChange{groupChanges, userChanges, userDeletes, groupDeletes} {
+		sort.Slice(changes, func(i, j int) bool {
+			return changes[i].Name < changes[j].Name
+		})
+		plan = append(plan, changes...)
+	}
+	return plan
+}
+
+// Return the names of the fields which differ. The password is not compared, since it cannot be read from the server.
+func diffUsers(desired, current *services.User) []string {
+	var fields []string
+	if desired.Email != current.Email {
+		fields = append(fields, "email")
+	}
+	if desired.Admin != current.Admin {
+		fields = append(fields, "admin")
+	}
+	if desired.ProfileUpdatable != current.ProfileUpdatable {
+		fields = append(fields, "profileUpdatable")
+	}
+	if desired.DisableUIAccess != current.DisableUIAccess {
+		fields = append(fields, "disableUIAccess")
+	}
+	if desired.InternalPasswordDisabled != current.InternalPasswordDisabled {
+		fields = append(fields, "internalPasswordDisabled")
+	}
+	if !equalSets(desired.Groups, current.Groups) {
+		fields = append(fields, "groups")
+	}
+	return fields
+}
+
+func diffGroups(desired, current *services.Group) []string {
+	var fields []string
+	if desired.Description != current.Description {
+		fields = append(fields, "description")
+	}
+	if desired.AutoJoin != current.AutoJoin {
+		fields = append(fields, "autoJoin")
+	}
+	if desired.AdminPrivileges != current.AdminPrivileges {
+		fields = append(fields, "adminPrivileges")
+	}
+	if desired.Realm != "" && desired.Realm != current.Realm {
+		fields = append(fields, "realm")
+	}
+	if desired.RealmAttributes != current.RealmAttributes {
+		fields = append(fields, "realmAttributes")
+	}
+	return fields
+}
+
+func equalSets(first, second []string) bool {
+	if len(first) != len(second) {
+		return false
+	}
+	values := make(map[string]bool)
+	for _, value := range first {
+		values[value] = true
+	}
+	for _, value := range second {
+		if !values[value] {
+			return false
+		}
+	}
+	return true
+}
+
+func (change *SecurityChange) String() string {
+	description := fmt.Sprintf("%s %s '%s'", change.Action, change.Kind, change.Name)
+	if len(change.Fields) > 0 {
+		description += " (" + strings.Join(change.Fields, ", ") + ")"
+	}
+	return description
+}
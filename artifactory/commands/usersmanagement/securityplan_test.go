@@ -0,0 +1,92 @@
+package usersmanagement
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSecurityPlan(t *testing.T) {
+	current := &SecurityState{
+		Users: []services.User{
+			{Name: "admin", Admin: true},
+			{Name: "anonymous"},
+			{Name: "alice", Email: "alice@example.com", Groups: []string{"dev", "readers"}},
+			{Name: "bob", Email: "bob@example.com", Admin: true, Groups: []string{"dev"}},
+			{Name: "carol"},
+		},
+		Groups: []services.Group{{Name: "dev"}, {Name: "readers", AutoJoin: true}, {Name: "legacy"}},
+	}
+	desired := &SecurityState{
+		Users: []services.User{
+			{Name: "alice", Email: "alice@example.com", Groups: []string{"readers", "dev"}},
+			{Name: "bob", Email: "bob@example.com", Groups: []string{"dev", "ops"}},
+			{Name: "dave", Password: "secret", Groups: []string{"ops"}},
+		},
+		Groups: []services.Group{{Name: "dev", Description: "Developers"}, {Name: "readers", AutoJoin: true}, {Name: "ops"}},
+	}
+
+	plan := createSecurityPlan(desired, current, false, nil, nil)
+	assert.Equal(t, []string{
+		"update group 'dev' (description)",
+		"create group 'ops'",
+		"update user 'bob' (admin, groups)",
+		"create user 'dave'",
+	}, planDescriptions(plan))
+
+	plan = createSecurityPlan(desired, current, true, []string{"anonymous", "admin"}, nil)
+	assert.Equal(t, []string{
+		"update group 'dev' (description)",
+		"create group 'ops'",
+		"update user 'bob' (admin, groups)",
+		"create user 'dave'",
+		"delete user 'carol'",
+		"delete group 'legacy'",
+	}, planDescriptions(plan))
+
+	// Protected groups are not deleted.
+	plan = createSecurityPlan(desired, current, true, []string{"anonymous", "admin"}, []string{"legacy"})
+	assert.NotContains(t, planDescriptions(plan), "delete group 'legacy'")
+
+	// Once applied, there is nothing left to change.
+	assert.Empty(t, createSecurityPlan(desired, desired, true, nil, nil))
+}
+
+func planDescriptions(plan []SecurityChange) []string {
+	descriptions := []string{}
+	for _, change := range plan {
+		descriptions = append(descriptions, change.String())
+	}
+	return descriptions
+}
+
+func TestSecurityStateFiles(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "security")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	state := &SecurityState{
+		Users: []services.User{
+			{Name: "bob", Email: "bob@example.com", Admin: true, Groups: []string{"ops", "dev"}},
+			{Name: "alice", Realm: "ldap", ProfileUpdatable: true},
+		},
+		Groups: []services.Group{{Name: "ops", Description: "Operations, on call"}, {Name: "dev", AutoJoin: true}},
+	}
+	for _, statePath := range []string{filepath.Join(tempDir, "state.json"), filepath.Join(tempDir, "csv")} {
+		assert.NoError(t, WriteSecurityState(state, statePath))
+		read, err := ReadSecurityState(statePath)
+		assert.NoError(t, err)
+		assert.Equal(t, state, read, statePath)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(tempDir, "csv", membershipsCsvFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "username,group\nbob,dev\nbob,ops\n", string(content))
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "csv", membershipsCsvFile), []byte("username,group\ncarol,dev\n"), 0600))
+	_, err = ReadSecurityState(filepath.Join(tempDir, "csv"))
+	assert.Error(t, err)
+}
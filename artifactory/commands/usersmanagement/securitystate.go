@@ -0,0 +1,299 @@
+package usersmanagement
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	usersCsvFile       = "users.csv"
+	groupsCsvFile      = "groups.csv"
+	membershipsCsvFile = "memberships.csv"
+)
+
+var (
+	usersCsvHeader       = []string{"username", "email", "password", "admin", "profileUpdatable", "disableUIAccess", "internalPasswordDisabled", "realm"}
+	groupsCsvHeader      = []string{"name", "description", "autoJoin", "adminPrivileges", "realm", "realmAttributes"}
+	membershipsCsvHeader = []string{"username", "group"}
+)
+
+// The users and groups of an Artifactory instance. Group memberships are kept in the groups of each user.
+//
+// A state is stored either as a single JSON file, or as a directory with the users.csv, groups.csv and memberships.csv files.
+type SecurityState struct {
+	Users  []services.User  `json:"users"`
+	Groups []services.Group `json:"groups"`
+}
+
+// Read a state from a JSON file, if the path has the .json extension, or from a directory of CSV files.
+func ReadSecurityState(statePath string) (*SecurityState, error) {
+	var state *SecurityState
+	var err error
+	if isJsonStatePath(statePath) {
+		state, err = readJsonSecurityState(statePath)
+	} else {
+		state, err = readCsvSecurityState(statePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return state, state.validate()
+}
+
+// Write a state to a JSON file, if the path has the .json extension, or to a directory of CSV files.
+func WriteSecurityState(state *SecurityState, statePath string) error {
+	state.sort()
+	if isJsonStatePath(statePath) {
+		content, err := json.MarshalIndent(state, "", "  ")
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		return errorutils.CheckError(ioutil.WriteFile(statePath, content, 0600))
+	}
+	return writeCsvSecurityState(state, statePath)
+}
+
+func isJsonStatePath(statePath string) bool {
+	return strings.EqualFold(filepath.Ext(statePath), ".json")
+}
+
+func readJsonSecurityState(statePath string) (*SecurityState, error) {
+	content, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	state := new(SecurityState)
+	if err = json.Unmarshal(content, state); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("failed to parse %s: %s", statePath, err.Error()))
+	}
+	return state, nil
+}
+
+func readCsvSecurityState(stateDir string) (*SecurityState, error) {
+	state := new(SecurityState)
+	users := make(map[string]int)
+	err := readCsvFile(filepath.Join(stateDir, usersCsvFile), func(record map[string]string) error {
+		user := services.User{Name: record["username"], Email: record["email"], Password: record["password"], Realm: record["realm"]}
+		for column, value := range map[string]*bool{"admin": &user.Admin, "profileUpdatable": &user.ProfileUpdatable,
+			"disableUIAccess": &user.DisableUIAccess, "internalPasswordDisabled": &user.InternalPasswordDisabled} {
+			if err := parseCsvBool(record[column], value); err != nil {
+				return fmt.Errorf("column '%s': %s", column, err.Error())
+			}
+		}
+		users[user.Name] = len(state.Users)
+		state.Users = append(state.Users, user)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = readCsvFile(filepath.Join(stateDir, groupsCsvFile), func(record map[string]string) error {
+		group := services.Group{Name: record["name"], Description: record["description"], Realm: record["realm"], RealmAttributes: record["realmAttributes"]}
+		for column, value := range map[string]*bool{"autoJoin": &group.AutoJoin, "adminPrivileges": &group.AdminPrivileges} {
+			if err := parseCsvBool(record[column], value); err != nil {
+				return fmt.Errorf("column '%s': %s", column, err.Error())
+			}
+		}
+		state.Groups = append(state.Groups, group)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = readCsvFile(filepath.Join(stateDir, membershipsCsvFile), func(record map[string]string) error {
+		index, ok := users[record["username"]]
+		if !ok {
+			return fmt.Errorf("the user '%s' is not included in %s", record["username"], usersCsvFile)
+		}
+		state.Users[index].Groups = append(state.Users[index].Groups, record["group"])
+		return nil
+	})
+	return state, err
+}
+
+// Read a CSV file with a header row, passing each row as a map from column name to value.
+// A missing file is treated as an empty one.
+func readCsvFile(csvPath string, handle func(record map[string]string) error) error {
+	file, err := os.Open(csvPath)
+	if os.IsNotExist(err) {
+		log.Debug("The file", csvPath, "does not exist.")
+		return nil
+	}
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return errorutils.CheckError(fmt.Errorf("failed to parse %s: %s", csvPath, err.Error()))
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	header := records[0]
+	for i, record := range records[1:] {
+		values := make(map[string]string)
+		for j, column := range header {
+			values[strings.TrimSpace(column)] = strings.TrimSpace(record[j])
+		}
+		if err = handle(values); err != nil {
+			return errorutils.CheckError(fmt.Errorf("%s, line %d: %s", csvPath, i+2, err.Error()))
+		}
+	}
+	return nil
+}
+
+func parseCsvBool(value string, target *bool) (err error) {
+	if value == "" {
+		return nil
+	}
+	*target, err = strconv.ParseBool(value)
+	return
+}
+
+func writeCsvSecurityState(state *SecurityState, stateDir string) error {
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return errorutils.CheckError(err)
+	}
+	users := [][]string{usersCsvHeader}
+	memberships := [][]string{membershipsCsvHeader}
+	for _, user := range state.Users {
+		users = append(users, []string{user.Name, user.Email, "", strconv.FormatBool(user.Admin), strconv.FormatBool(user.ProfileUpdatable),
+			strconv.FormatBool(user.DisableUIAccess), strconv.FormatBool(user.InternalPasswordDisabled), user.Realm})
+		for _, group := range user.Groups {
+			memberships = append(memberships, []string{user.Name, group})
+		}
+	}
+	groups := [][]string{groupsCsvHeader}
+	for _, group := range state.Groups {
+		groups = append(groups, []string{group.Name, group.Description, strconv.FormatBool(group.AutoJoin),
+			strconv.FormatBool(group.AdminPrivileges), group.Realm, group.RealmAttributes})
+	}
+	for fileName, records := range map[string][][]string{usersCsvFile: users, groupsCsvFile: groups, membershipsCsvFile: memberships} {
+		if err := writeCsvFile(filepath.Join(stateDir, fileName), records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCsvFile(csvPath string, records [][]string) error {
+	file, err := os.OpenFile(csvPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer file.Close()
+	return errorutils.CheckError(csv.NewWriter(file).WriteAll(records))
+}
+
+func (state *SecurityState) validate() error {
+	groups := make(map[string]bool)
+	for _, group := range state.Groups {
+		if group.Name == "" {
+			return errorutils.CheckError(errors.New("a group without a name was found"))
+		}
+		if groups[group.Name] {
+			return errorutils.CheckError(fmt.Errorf("the group '%s' appears more than once", group.Name))
+		}
+		groups[group.Name] = true
+	}
+	users := make(map[string]bool)
+	for _, user := range state.Users {
+		if user.Name == "" {
+			return errorutils.CheckError(errors.New("a user without a name was found"))
+		}
+		if users[user.Name] {
+			return errorutils.CheckError(fmt.Errorf("the user '%s' appears more than once", user.Name))
+		}
+		users[user.Name] = true
+	}
+	return nil
+}
+
+func (state *SecurityState) sort() {
+	sort.Slice(state.Users, func(i, j int) bool {
+		return state.Users[i].Name < state.Users[j].Name
+	})
+	for i := range state.Users {
+		sort.Strings(state.Users[i].Groups)
+	}
+	sort.Slice(state.Groups, func(i, j int) bool {
+		return state.Groups[i].Name < state.Groups[j].Name
+	})
+}
+
+// Read the users and groups of the server, including the groups of each user.
+func GetServerSecurityState(servicesManager artifactory.ArtifactoryServicesManager) (*SecurityState, error) {
+	state := new(SecurityState)
+	users, err := servicesManager.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, listedUser := range users {
+		userParams := services.NewUserParams()
+		userParams.UserDetails.Name = listedUser.Name
+		user, err := servicesManager.GetUser(userParams)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil {
+			user.LastLoggedIn = ""
+			state.Users = append(state.Users, *user)
+		}
+	}
+	groupNames, err := getAllGroupNames(servicesManager)
+	if err != nil {
+		return nil, err
+	}
+	for _, groupName := range groupNames {
+		groupParams := services.NewGroupParams()
+		groupParams.GroupDetails.Name = groupName
+		group, err := servicesManager.GetGroup(groupParams)
+		if err != nil {
+			return nil, err
+		}
+		if group != nil {
+			state.Groups = append(state.Groups, *group)
+		}
+	}
+	state.sort()
+	return state, nil
+}
+
+func getAllGroupNames(servicesManager artifactory.ArtifactoryServicesManager) ([]string, error) {
+	artDetails := servicesManager.GetConfig().GetServiceDetails()
+	httpDetails := artDetails.CreateHttpClientDetails()
+	resp, body, _, err := servicesManager.Client().SendGet(artDetails.GetUrl()+"api/security/groups", true, &httpDetails)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorutils.CheckError(errors.New("Artifactory response: " + resp.Status + "\n" + clientutils.IndentJson(body)))
+	}
+	var groups []struct {
+		Name string `json:"name"`
+	}
+	if err = json.Unmarshal(body, &groups); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var names []string
+	for _, group := range groups {
+		names = append(names, group.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,54 @@
+package usersmanagement
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Export the users, groups and group memberships of the server to a JSON file or a directory of CSV files.
+// Passwords cannot be read from the server, so they are not exported.
+// Before importing the state into another server, set the passwords of the internal users which do not exist there yet.
+type UsersExportCommand struct {
+	serverDetails *config.ServerDetails
+	statePath     string
+}
+
+func NewUsersExportCommand() *UsersExportCommand {
+	return &UsersExportCommand{}
+}
+
+func (uec *UsersExportCommand) ServerDetails() (*config.ServerDetails, error) {
+	return uec.serverDetails, nil
+}
+
+func (uec *UsersExportCommand) SetServerDetails(serverDetails *config.ServerDetails) *UsersExportCommand {
+	uec.serverDetails = serverDetails
+	return uec
+}
+
+// A path with the .json extension is exported as JSON. Any other path is a directory to export CSV files to.
+func (uec *UsersExportCommand) SetStatePath(statePath string) *UsersExportCommand {
+	uec.statePath = statePath
+	return uec
+}
+
+func (uec *UsersExportCommand) CommandName() string {
+	return "rt_users_export"
+}
+
+func (uec *UsersExportCommand) Run() error {
+	servicesManager, err := utils.CreateServiceManager(uec.serverDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	state, err := GetServerSecurityState(servicesManager)
+	if err != nil {
+		return err
+	}
+	if err = WriteSecurityState(state, uec.statePath); err != nil {
+		return err
+	}
+	log.Info("Exported", len(state.Users), "users and", len(state.Groups), "groups to", uec.statePath)
+	return nil
+}
@@ -0,0 +1,275 @@
+package usersmanagement
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/auth"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	SecurityChangePlanned   = "planned"
+	SecurityChangeSucceeded = "succeeded"
+	SecurityChangeFailed    = "failed"
+
+	anonymousUser = "anonymous"
+)
+
+// Groups which are never deleted when reconciling. Artifactory adds new users to the 'readers' group by default.
+var defaultProtectedGroups = []string{"readers"}
+
+type SecurityChangeResult struct {
+	SecurityChange
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Import users, groups and group memberships from a JSON file or a directory of CSV files.
+// Only the differences from the server state are applied, so importing the same state twice changes nothing.
+// In reconcile mode, users and groups which are not in the imported state are deleted from the server,
+// except for the anonymous user, the user running the import, the protected users and the protected groups.
+// A failure to apply a change is reported, and the import continues with the next change.
+// Exported states have no passwords, so the import fails if it would create an internal user without a password.
+// Set the passwords of the new users in the state, or disable their internal passwords, before importing it.
+type UsersImportCommand struct {
+	serverDetails   *config.ServerDetails
+	statePath       string
+	reconcile       bool
+	dryRun          bool
+	protectedUsers  []string
+	protectedGroups []string
+	results         []SecurityChangeResult
+}
+
+func NewUsersImportCommand() *UsersImportCommand {
+	return &UsersImportCommand{protectedGroups: defaultProtectedGroups}
+}
+
+func (uic *UsersImportCommand) ServerDetails() (*config.ServerDetails, error) {
+	return uic.serverDetails, nil
+}
+
+func (uic *UsersImportCommand) SetServerDetails(serverDetails *config.ServerDetails) *UsersImportCommand {
+	uic.serverDetails = serverDetails
+	return uic
+}
+
+// A path with the .json extension is imported as JSON. Any other path is a directory to import CSV files from.
+func (uic *UsersImportCommand) SetStatePath(statePath string) *UsersImportCommand {
+	uic.statePath = statePath
+	return uic
+}
+
+func (uic *UsersImportCommand) SetReconcile(reconcile bool) *UsersImportCommand {
+	uic.reconcile = reconcile
+	return uic
+}
+
+// Report the changes without applying them.
+func (uic *UsersImportCommand) SetDryRun(dryRun bool) *UsersImportCommand {
+	uic.dryRun = dryRun
+	return uic
+}
+
+// Users which are never deleted when reconciling.
+func (uic *UsersImportCommand) SetProtectedUsers(protectedUsers []string) *UsersImportCommand {
+	uic.protectedUsers = protectedUsers
+	return uic
+}
+
+// Groups which are never deleted when reconciling. Replaces the default protected groups.
+func (uic *UsersImportCommand) SetProtectedGroups(protectedGroups []string) *UsersImportCommand {
+	uic.protectedGroups = protectedGroups
+	return uic
+}
+
+func (uic *UsersImportCommand) Results() []SecurityChangeResult {
+	return uic.results
+}
+
+func (uic *UsersImportCommand) CommandName() string {
+	return "rt_users_import"
+}
+
+func (uic *UsersImportCommand) Run() error {
+	desired, err := ReadSecurityState(uic.statePath)
+	if err != nil {
+		return err
+	}
+	servicesManager, err := utils.CreateServiceManager(uic.serverDetails, -1, false)
+	if err != nil {
+		return err
+	}
+	current, err := GetServerSecurityState(servicesManager)
+	if err != nil {
+		return err
+	}
+	protectedUsers := append([]string{anonymousUser}, uic.protectedUsers...)
+	if uic.reconcile {
+		callerUser, err := getCallerUser(uic.serverDetails)
+		if err != nil {
+			return err
+		}
+		protectedUsers = append(protectedUsers, callerUser)
+	}
+	plan := createSecurityPlan(desired, current, uic.reconcile, protectedUsers, uic.protectedGroups)
+	if err = validateNewUsersPasswords(plan); err != nil {
+		return err
+	}
+	uic.results = nil
+	failed := 0
+	for _, change := range plan {
+		result := SecurityChangeResult{SecurityChange: change, Status: SecurityChangePlanned}
+		if !uic.dryRun {
+			log.Info(fmt.Sprintf("Applying: %s...", change.String()))
+			if err = applySecurityChange(servicesManager, &change); err != nil {
+				log.Error(err)
+				result.Status, result.Error = SecurityChangeFailed, err.Error()
+				failed++
+			} else {
+				result.Status = SecurityChangeSucceeded
+			}
+		}
+		uic.results = append(uic.results, result)
+	}
+	if len(uic.results) == 0 {
+		log.Info("The users and groups are up to date.")
+		return nil
+	}
+	log.Output(formatSecurityChangeResults(uic.results))
+	if failed > 0 {
+		return errorutils.CheckError(fmt.Errorf("%d out of %d changes failed", failed, len(uic.results)))
+	}
+	return nil
+}
+
+// Artifactory creates a user without a password as an internal user nobody can log in as,
+// which is what importing an exported state into another instance would do.
+func validateNewUsersPasswords(plan []SecurityChange) error {
+	var users []string
+	for _, change := range plan {
+		if change.Kind == UserEntity && change.Action == CreateAction && change.user.Password == "" && !change.user.InternalPasswordDisabled {
+			users = append(users, change.Name)
+		}
+	}
+	if len(users) == 0 {
+		return nil
+	}
+	return errorutils.CheckError(fmt.Errorf("the following new users have no password: %s. "+
+		"Exported states do not include passwords. Set the users' passwords, or set internalPasswordDisabled for them, and import again",
+		strings.Join(users, ", ")))
+}
+
+// Return the name of the user running the import, so that reconciling does not delete it.
+// With token authentication, the user is the subject of the access token.
+func getCallerUser(serverDetails *config.ServerDetails) (string, error) {
+	if serverDetails.User != "" {
+		return serverDetails.User, nil
+	}
+	if serverDetails.AccessToken != "" {
+		if user, err := auth.ExtractUsernameFromAccessToken(serverDetails.AccessToken); err == nil {
+			return user, nil
+		}
+	}
+	return "", errorutils.CheckError(errors.New("cannot determine the user running the import, which must not be deleted when reconciling. " +
+		"Configure the server with a username, or with an access token issued for a user"))
+}
+
+func applySecurityChange(servicesManager artifactory.ArtifactoryServicesManager, change *SecurityChange) error {
+	switch {
+	case change.Kind == GroupEntity && change.Action == CreateAction:
+		params := services.NewGroupParams()
+		params.GroupDetails = *change.group
+		return servicesManager.CreateGroup(params)
+	case change.Kind == GroupEntity && change.Action == UpdateAction:
+		return updateSecurityEntity(servicesManager, "groups", change.Name, newGroupUpdate(change.group))
+	case change.Kind == GroupEntity && change.Action == DeleteAction:
+		return servicesManager.DeleteGroup(change.Name)
+	case change.Kind == UserEntity && change.Action == CreateAction:
+		params := services.NewUserParams()
+		params.UserDetails = *change.user
+		return servicesManager.CreateUser(params)
+	case change.Kind == UserEntity && change.Action == UpdateAction:
+		return updateSecurityEntity(servicesManager, "users", change.Name, newUserUpdate(change.user))
+	case change.Kind == UserEntity && change.Action == DeleteAction:
+		return servicesManager.DeleteUser(change.Name)
+	}
+	return errorutils.CheckError(fmt.Errorf("unsupported change: %s", change.String()))
+}
+
+// The fields sent when updating a user or a group. Unlike services.User and services.Group, false values and empty
+// lists are sent too, so that an update can revoke admin privileges or remove a user from all of its groups.
+type userUpdate struct {
+	Email                    string   `json:"email"`
+	Admin                    bool     `json:"admin"`
+	ProfileUpdatable         bool     `json:"profileUpdatable"`
+	DisableUIAccess          bool     `json:"disableUIAccess"`
+	InternalPasswordDisabled bool     `json:"internalPasswordDisabled"`
+	Groups                   []string `json:"groups"`
+}
+
+func newUserUpdate(user *services.User) *userUpdate {
+	groups := user.Groups
+	if groups == nil {
+		groups = []string{}
+	}
+	return &userUpdate{Email: user.Email, Admin: user.Admin, ProfileUpdatable: user.ProfileUpdatable, DisableUIAccess: user.DisableUIAccess,
+		InternalPasswordDisabled: user.InternalPasswordDisabled, Groups: groups}
+}
+
+type groupUpdate struct {
+	Description     string `json:"description"`
+	AutoJoin        bool   `json:"autoJoin"`
+	AdminPrivileges bool   `json:"adminPrivileges"`
+	Realm           string `json:"realm,omitempty"`
+	RealmAttributes string `json:"realmAttributes"`
+}
+
+func newGroupUpdate(group *services.Group) *groupUpdate {
+	return &groupUpdate{Description: group.Description, AutoJoin: group.AutoJoin, AdminPrivileges: group.AdminPrivileges,
+		Realm: group.Realm, RealmAttributes: group.RealmAttributes}
+}
+
+func updateSecurityEntity(servicesManager artifactory.ArtifactoryServicesManager, entityType, name string, update interface{}) error {
+	content, err := json.Marshal(update)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	artDetails := servicesManager.GetConfig().GetServiceDetails()
+	httpDetails := artDetails.CreateHttpClientDetails()
+	servicesutils.SetContentType("application/json", &httpDetails.Headers)
+	resp, body, err := servicesManager.Client().SendPost(fmt.Sprintf("%sapi/security/%s/%s", artDetails.GetUrl(), entityType, url.PathEscape(name)), content, &httpDetails)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errorutils.CheckError(errors.New("Artifactory response: " + resp.Status + "\n" + clientutils.IndentJson(body)))
+	}
+	return nil
+}
+
+func formatSecurityChangeResults(results []SecurityChangeResult) string {
+	buffer := &bytes.Buffer{}
+	writer := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "KIND\tNAME\tACTION\tFIELDS\tSTATUS\tERROR")
+	for _, result := range results {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n", result.Kind, result.Name, result.Action, strings.Join(result.Fields, ","), result.Status,
+			strings.ReplaceAll(result.Error, "\n", " "))
+	}
+	writer.Flush()
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
@@ -0,0 +1,45 @@
+package usersmanagement
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCallerUser(t *testing.T) {
+	user, err := getCallerUser(&config.ServerDetails{User: "admin", AccessToken: "ignored"})
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", user)
+
+	payload := base64.RawStdEncoding.EncodeToString([]byte(`{"sub":"jfrt@01abc/users/alice","scp":"applied-permissions/user"}`))
+	user, err = getCallerUser(&config.ServerDetails{AccessToken: "header." + payload + ".signature"})
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", user)
+
+	// Reconciling without knowing the user running the import could delete it.
+	_, err = getCallerUser(&config.ServerDetails{AccessToken: "not-a-token"})
+	assert.Error(t, err)
+	_, err = getCallerUser(&config.ServerDetails{})
+	assert.Error(t, err)
+}
+
+func TestValidateNewUsersPasswords(t *testing.T) {
+	current := &SecurityState{Users: []services.User{{Name: "alice"}}}
+	desired := &SecurityState{Users: []services.User{
+		{Name: "alice", Email: "alice@example.com"},
+		{Name: "bob", Password: "secret"},
+		{Name: "carol", InternalPasswordDisabled: true, Realm: "ldap"},
+	}}
+	// Updated users keep their passwords, and users without internal passwords need none.
+	assert.NoError(t, validateNewUsersPasswords(createSecurityPlan(desired, current, false, nil, nil)))
+
+	// An exported state has no passwords.
+	desired.Users = append(desired.Users, services.User{Name: "dave"}, services.User{Name: "erin"})
+	err := validateNewUsersPasswords(createSecurityPlan(desired, current, false, nil, nil))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "dave, erin")
+	}
+}
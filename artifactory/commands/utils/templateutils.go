@@ -3,9 +3,15 @@ package utils
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
@@ -64,3 +70,136 @@ func ValidateTemplatePath(templatePath string) error {
 	}
 	return nil
 }
+
+// A template exported from the server, and the name of the file to write it to, without the .json extension.
+type ExportedTemplate struct {
+	Name     string
+	Template interface{}
+}
+
+// The characters which cannot appear in a file name on some of the supported platforms.
+var templateFileNameReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+
+// Return the path of the file a template is exported to. Repository keys and permission target names may include
+// characters such as '/', which are replaced, so that every template is written directly under the output directory.
+func exportedTemplatePath(outputDir, name string) string {
+	return filepath.Join(outputDir, templateFileNameReplacer.Replace(name)+".json")
+}
+
+// Write templates exported from the server to files in the output directory, as ExportTemplate does.
+// The output directory is created if it does not exist.
+// All the paths are validated before any file is written, so that an invalid path does not leave a partial export.
+func ExportTemplates(outputDir string, templates []ExportedTemplate, vars string) error {
+	if err := fileutils.CreateDirIfNotExist(outputDir); err != nil {
+		return errorutils.CheckError(err)
+	}
+	paths := make(map[string]bool)
+	for _, template := range templates {
+		templatePath := exportedTemplatePath(outputDir, template.Name)
+		if paths[templatePath] {
+			return errorutils.CheckError(fmt.Errorf("more than one template would be exported to %s", templatePath))
+		}
+		paths[templatePath] = true
+		if err := ValidateTemplatePath(templatePath); err != nil {
+			return errorutils.CheckError(fmt.Errorf("%s: %s", templatePath, err.Error()))
+		}
+	}
+	for _, template := range templates {
+		if err := ExportTemplate(template.Template, exportedTemplatePath(outputDir, template.Name), vars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write a template exported from the server to a file.
+// The vars are in the same format as the vars used to fill templates ("key1=value1;key2=value2").
+// Each occurrence of a value in the template is replaced with the ${key} placeholder of its var.
+func ExportTemplate(template interface{}, templatePath, vars string) error {
+	if err := ValidateTemplatePath(templatePath); err != nil {
+		return err
+	}
+	content, err := json.Marshal(template)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	var templateMap map[string]interface{}
+	if err = json.Unmarshal(content, &templateMap); err != nil {
+		return errorutils.CheckError(err)
+	}
+	ParameterizeTemplate(templateMap, coreutils.SpecVarsStringToMap(vars))
+	content, err = json.MarshalIndent(templateMap, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = ioutil.WriteFile(templatePath, content, 0644); err != nil {
+		return errorutils.CheckError(err)
+	}
+	log.Info(fmt.Sprintf("Template successfully exported to %s.", templatePath))
+	return nil
+}
+
+// The reverse of filling a template with vars - replace the values of the vars in the template's string values
+// with their ${key} placeholders. Longer values are replaced first, so that a value which contains another is not split.
+func ParameterizeTemplate(templateMap map[string]interface{}, templateVars map[string]string) {
+	var keys []string
+	for key, value := range templateVars {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(templateVars[keys[i]]) != len(templateVars[keys[j]]) {
+			return len(templateVars[keys[i]]) > len(templateVars[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+	var oldNew []string
+	for _, key := range keys {
+		oldNew = append(oldNew, templateVars[key], "${"+key+"}")
+	}
+	parameterizeValue(templateMap, strings.NewReplacer(oldNew...))
+}
+
+func parameterizeValue(value interface{}, replacer *strings.Replacer) interface{} {
+	switch typedValue := value.(type) {
+	case string:
+		return replacer.Replace(typedValue)
+	case map[string]interface{}:
+		for key, nested := range typedValue {
+			typedValue[key] = parameterizeValue(nested, replacer)
+		}
+	case []interface{}:
+		for i, nested := range typedValue {
+			typedValue[i] = parameterizeValue(nested, replacer)
+		}
+	}
+	return value
+}
+
+// Convert a value read from the server to the string format of template values.
+// Arrays are joined with commas, as expected by WriteStringArrayAnswer. Returns false for values which have no such format.
+func TemplateValueToString(value interface{}) (string, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, true
+	case bool:
+		return strconv.FormatBool(typedValue), true
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64), true
+	case []interface{}:
+		var values []string
+		for _, nested := range typedValue {
+			nestedString, ok := TemplateValueToString(nested)
+			if !ok {
+				return "", false
+			}
+			values = append(values, nestedString)
+		}
+		return strings.Join(values, ","), true
+	}
+	return "", false
+}
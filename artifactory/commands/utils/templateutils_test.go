@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterizeTemplate(t *testing.T) {
+	var templateMap map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"key": "prod-maven-local",
+		"url": "https://repo.example.com/artifactory/maven",
+		"repo": {"repositories": "prod-maven-local,prod-npm-local", "actions-users": {"deployer": "read,write"}}
+	}`), &templateMap))
+	ParameterizeTemplate(templateMap, map[string]string{"env": "prod", "repo": "prod-maven-local", "host": "repo.example.com", "empty": ""})
+	assert.Equal(t, map[string]interface{}{
+		"key": "${repo}",
+		"url": "https://${host}/artifactory/maven",
+		"repo": map[string]interface{}{
+			"repositories":  "${repo},${env}-npm-local",
+			"actions-users": map[string]interface{}{"deployer": "read,write"},
+		},
+	}, templateMap)
+}
+
+func TestTemplateValueToString(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+		ok       bool
+	}{
+		{"maven", "maven", true},
+		{true, "true", true},
+		{float64(15000), "15000", true},
+		{[]interface{}{"a", "b"}, "a,b", true},
+		{map[string]interface{}{"enabled": true}, "", false},
+	}
+	for _, test := range tests {
+		actual, ok := TemplateValueToString(test.value)
+		assert.Equal(t, test.ok, ok)
+		assert.Equal(t, test.expected, actual)
+	}
+}
+
+func TestExportTemplates(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "templates")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "existing.json"), []byte("{}"), 0644))
+
+	// No template is written if one of the paths is invalid.
+	first := filepath.Join(tempDir, "first.json")
+	templates := []ExportedTemplate{{Name: "first", Template: map[string]string{"key": "first"}}, {Name: "existing", Template: map[string]string{}}}
+	assert.Error(t, ExportTemplates(tempDir, templates, ""))
+	assert.NoFileExists(t, first)
+	templates = []ExportedTemplate{{Name: "first", Template: map[string]string{}}, {Name: "first", Template: map[string]string{}}}
+	assert.Error(t, ExportTemplates(tempDir, templates, ""))
+	assert.NoFileExists(t, first)
+
+	second := filepath.Join(tempDir, "second.json")
+	templates = []ExportedTemplate{{Name: "first", Template: map[string]string{"key": "first"}}, {Name: "second", Template: map[string]string{"key": "second"}}}
+	assert.NoError(t, ExportTemplates(tempDir, templates, ""))
+	content, err := ioutil.ReadFile(second)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"key": "second"}`, string(content))
+
+	// The output directory is created, and names which are not valid file names are sanitised.
+	outputDir := filepath.Join(tempDir, "output", "nested")
+	templates = []ExportedTemplate{{Name: "team/libs", Template: map[string]string{}}, {Name: `a\b:c`, Template: map[string]string{}}}
+	assert.NoError(t, ExportTemplates(outputDir, templates, ""))
+	assert.FileExists(t, filepath.Join(outputDir, "team_libs.json"))
+	assert.FileExists(t, filepath.Join(outputDir, "a_b_c.json"))
+}
@@ -22,6 +22,8 @@ const (
 	Pull                      CommandType = "pull"
 	Push                      CommandType = "push"
 	foreignLayerMediaType     string      = "application/vnd.docker.image.rootfs.foreign.diff.tar.gzip"
+	fatManifestFileName       string      = "list.manifest.json"
+	manifestFileName          string      = "manifest.json"
 	imageNotFoundErrorMessage string      = "Could not find docker image in Artifactory, expecting image tag: %s"
 	markerLayerSuffix         string      = ".marker"
 )
@@ -70,6 +72,17 @@ func NewKanikoBuildInfoBuilder(image *Image, repository, buildName, buildNumber,
 	return builder, err
 }
 
+// Create a build info builder for a multi-platform image.
+// The image's fat manifest is recorded as an artifact (or a dependency, when pulling), and every platform referenced by it is recorded as a separate module.
+// A multi-platform image does not have a single image ID, therefore the container manager is not queried for it.
+func NewMultiPlatformBuildInfoBuilder(image *Image, repository, buildName, buildNumber, project string, serviceManager artifactory.ArtifactoryServicesManager, commandType CommandType, containerManager ContainerManager) (Builder, error) {
+	builder := &buildInfoBuilder{multiPlatform: true}
+	if err := newBuildInfoBuilder(builder, image, repository, buildName, buildNumber, project, serviceManager, commandType, containerManager); err != nil {
+		return nil, err
+	}
+	return builder, nil
+}
+
 type buildInfoBuilder struct {
 	image             *Image
 	containerManager  ContainerManager
@@ -86,6 +99,17 @@ type buildInfoBuilder struct {
 	artifacts      []buildinfo.Artifact
 	dependencies   []buildinfo.Dependency
 	commandType    CommandType
+	multiPlatform  bool
+	// Used for multi-platform images only - one entry per platform of the fat manifest.
+	platformModules []platformModule
+}
+
+// The artifacts and dependencies of a single platform of a multi-platform image.
+type platformModule struct {
+	platform     Platform
+	imageId      string
+	artifacts    []buildinfo.Artifact
+	dependencies []buildinfo.Dependency
 }
 
 type RepositoryDetails struct {
@@ -126,12 +150,20 @@ func (builder *buildInfoBuilder) getSearchableRepo() string {
 
 // Search, validate and create image's artifacts and dependencies.
 func (builder *buildInfoBuilder) UpdateArtifactsAndDependencies() error {
+	if builder.multiPlatform {
+		return builder.updateMultiPlatformArtifactsAndDependencies()
+	}
 	// Search for image's manifest and layers.
 	manifestLayers, manifestContent, err := builder.getManifestAndLayersDetails()
 	if err != nil {
 		return err
 	}
 	log.Debug("Found manifest.json. Proceeding to collect build-info.")
+	return builder.collectImageDetails(manifestContent, manifestLayers)
+}
+
+// Create the image's artifacts and dependencies, based on its manifest and the layers found in Artifactory.
+func (builder *buildInfoBuilder) collectImageDetails(manifestContent *manifest, manifestLayers map[string]*utils.ResultItem) error {
 	// Manifest may hold 'empty layers'. As a result, promotion will fail to promote the same layer more than once.
 	manifestContent.Layers = removeDuplicateLayers(manifestContent.Layers)
 	manifestArtifact, manifestDependency := getManifestArtifact(manifestLayers), getManifestDependency(manifestLayers)
@@ -181,6 +213,121 @@ func (builder *buildInfoBuilder) getManifestAndLayersDetails() (layers map[strin
 	return nil, nil, errorutils.CheckError(errors.New(fmt.Sprintf(imageNotFoundErrorMessage, builder.image.tag)))
 }
 
+// Search for the image's fat manifest and collect the artifacts and dependencies of all of its platforms.
+// If the image turns out to be a single-platform image, it is handled as a regular image.
+func (builder *buildInfoBuilder) updateMultiPlatformArtifactsAndDependencies() error {
+	imagePath, err := builder.image.Path()
+	if err != nil {
+		return err
+	}
+	log.Debug("Start searching for image list.manifest.json")
+	for _, pathPattern := range getManifestPaths(imagePath, builder.getSearchableRepo(), builder.commandType) {
+		log.Debug(`Searching in:"` + pathPattern + `"`)
+		resultMap, err := searchHandler(pathPattern, builder)
+		if err != nil {
+			return err
+		}
+		if fatManifestItem, ok := resultMap[fatManifestFileName]; ok {
+			log.Debug("Found list.manifest.json. Proceeding to collect build-info for all platforms.")
+			return builder.handleFatManifest(pathPattern, fatManifestItem)
+		}
+		if manifestItem, ok := resultMap[manifestFileName]; ok {
+			log.Debug("Found manifest.json of a single-platform image. Proceeding to collect build-info.")
+			var imageManifest *manifest
+			if err = builder.downloadLayer(*manifestItem, &imageManifest); err != nil {
+				return err
+			}
+			builder.imageId = imageManifest.Config.Digest
+			return builder.collectImageDetails(imageManifest, resultMap)
+		}
+	}
+	return errorutils.CheckError(errors.New(fmt.Sprintf(imageNotFoundErrorMessage, builder.image.tag)))
+}
+
+// Record the fat manifest itself and collect a module for each of the platforms it references.
+func (builder *buildInfoBuilder) handleFatManifest(imagePathPattern string, fatManifestItem *utils.ResultItem) error {
+	var fatManifestContent *FatManifest
+	if err := builder.downloadLayer(*fatManifestItem, &fatManifestContent); err != nil {
+		return err
+	}
+	checksum := &buildinfo.Checksum{Sha1: fatManifestItem.Actual_Sha1, Md5: fatManifestItem.Actual_Md5}
+	if builder.commandType == Push {
+		builder.artifacts = append(builder.artifacts, buildinfo.Artifact{Name: fatManifestFileName, Type: "json", Checksum: checksum, Path: path.Join(fatManifestItem.Repo, fatManifestItem.Path, fatManifestItem.Name)})
+		builder.layers = append(builder.layers, *fatManifestItem)
+	} else {
+		builder.dependencies = append(builder.dependencies, buildinfo.Dependency{Id: fatManifestFileName, Type: "json", Checksum: checksum})
+	}
+	for _, manifestDetails := range fatManifestContent.Manifests {
+		if manifestDetails.Platform.isUnknown() {
+			// Attestation manifests, such as the ones created by buildx, are not images.
+			log.Debug("Skipping manifest " + manifestDetails.Digest + " of an unknown platform.")
+			continue
+		}
+		if err := builder.collectPlatformDetails(imagePathPattern, manifestDetails); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Collect the artifacts and dependencies of a single platform of the fat manifest.
+func (builder *buildInfoBuilder) collectPlatformDetails(imagePathPattern string, manifestDetails ManifestDetails) error {
+	platformBuilder := builder.newPlatformBuilder()
+	digestPathPattern := toDigestPathPattern(imagePathPattern, manifestDetails.Digest)
+	log.Debug(`Searching for platform "` + manifestDetails.Platform.String() + `" in:"` + digestPathPattern + `"`)
+	resultMap, err := searchHandler(digestPathPattern, platformBuilder)
+	if err != nil {
+		return err
+	}
+	manifestItem, ok := resultMap[manifestFileName]
+	if !ok {
+		// When pulling, only the platform of the local machine is cached in Artifactory.
+		if builder.commandType == Pull {
+			log.Debug(`Platform "` + manifestDetails.Platform.String() + `" wasn't found in Artifactory and therefore will not be added to the build-info.`)
+			return nil
+		}
+		return errorutils.CheckError(errors.New(`Could not find the manifest of platform "` + manifestDetails.Platform.String() + `" (` + manifestDetails.Digest + `) in Artifactory`))
+	}
+	var imageManifest *manifest
+	if err = platformBuilder.downloadLayer(*manifestItem, &imageManifest); err != nil {
+		return err
+	}
+	platformBuilder.imageId = imageManifest.Config.Digest
+	if err = platformBuilder.collectImageDetails(imageManifest, resultMap); err != nil {
+		return err
+	}
+	builder.layers = append(builder.layers, platformBuilder.layers...)
+	builder.platformModules = append(builder.platformModules, platformModule{
+		platform:     manifestDetails.Platform,
+		imageId:      platformBuilder.imageId,
+		artifacts:    platformBuilder.artifacts,
+		dependencies: platformBuilder.dependencies,
+	})
+	return nil
+}
+
+// Create a builder for a single platform of a multi-platform image, sharing the image and repository details of the parent builder.
+func (builder *buildInfoBuilder) newPlatformBuilder() *buildInfoBuilder {
+	return &buildInfoBuilder{
+		image:             builder.image,
+		containerManager:  builder.containerManager,
+		repositoryDetails: builder.repositoryDetails,
+		buildName:         builder.buildName,
+		buildNumber:       builder.buildNumber,
+		project:           builder.project,
+		serviceManager:    builder.serviceManager,
+		commandType:       builder.commandType,
+	}
+}
+
+// In case of a fat-manifest, Artifactory stores each platform's manifest and layers in a folder named as the manifest digest, next to the image tag folder.
+// Replace the tag in the search pattern with the manifest digest.
+// For example: docker-local/hello-world/1.0/* -> docker-local/hello-world/sha256__a1b2c3/*
+func toDigestPathPattern(imagePathPattern, digest string) string {
+	imagePathPattern = strings.Replace(imagePathPattern, "/*", "", 1)
+	return path.Join(imagePathPattern[:strings.LastIndex(imagePathPattern, "/")], digestToLayer(digest), "*")
+}
+
 func (builder *buildInfoBuilder) handlePull(manifestDependency, configLayerDependency buildinfo.Dependency, imageManifest *manifest, searchResults map[string]*utils.ResultItem) error {
 	// Add dependencies.
 	builder.dependencies = append(builder.dependencies, manifestDependency)
@@ -206,7 +353,7 @@ func (builder *buildInfoBuilder) handlePush(manifestArtifact, configLayerArtifac
 	builder.artifacts = append(builder.artifacts, manifestArtifact)
 	builder.artifacts = append(builder.artifacts, configLayerArtifact)
 	// Add layers.
-	builder.layers = append(builder.layers, *searchResults["manifest.json"])
+	builder.layers = append(builder.layers, *searchResults[manifestFileName])
 	builder.layers = append(builder.layers, *searchResults[digestToLayer(builder.imageId)])
 	totalLayers := len(imageManifest.Layers)
 	totalDependencies := configurationLayer.getNumberOfDependentLayers()
@@ -283,7 +430,9 @@ func writeLayersToFile(layers []utils.ResultItem) (filePath string, err error) {
 // Create a docker build info.
 func (builder *buildInfoBuilder) createBuildInfo(module string) (*buildinfo.BuildInfo, error) {
 	imageProperties := map[string]string{}
-	imageProperties["docker.image.id"] = builder.imageId
+	if builder.imageId != "" {
+		imageProperties["docker.image.id"] = builder.imageId
+	}
 	imageProperties["docker.image.tag"] = builder.image.Tag()
 	if module == "" {
 		imageName, err := builder.image.Name()
@@ -299,19 +448,33 @@ func (builder *buildInfoBuilder) createBuildInfo(module string) (*buildinfo.Buil
 		Artifacts:    builder.artifacts,
 		Dependencies: builder.dependencies,
 	}}}
+	// Add a submodule for each platform of a multi-platform image.
+	for _, platformModule := range builder.platformModules {
+		buildInfo.Modules = append(buildInfo.Modules, buildinfo.Module{
+			Id:   path.Join(platformModule.platform.String(), module),
+			Type: buildinfo.Docker,
+			Properties: map[string]string{
+				"docker.image.id":       platformModule.imageId,
+				"docker.image.tag":      builder.image.Tag(),
+				"docker.image.platform": platformModule.platform.String(),
+			},
+			Artifacts:    platformModule.artifacts,
+			Dependencies: platformModule.dependencies,
+		})
+	}
 	return buildInfo, nil
 }
 
 // Return - manifest artifacts as buildinfo.Artifact struct.
 func getManifestArtifact(searchResults map[string]*utils.ResultItem) (artifact buildinfo.Artifact) {
-	item := searchResults["manifest.json"]
-	return buildinfo.Artifact{Name: "manifest.json", Type: "json", Checksum: &buildinfo.Checksum{Sha1: item.Actual_Sha1, Md5: item.Actual_Md5}, Path: path.Join(item.Repo, item.Path, item.Name)}
+	item := searchResults[manifestFileName]
+	return buildinfo.Artifact{Name: manifestFileName, Type: "json", Checksum: &buildinfo.Checksum{Sha1: item.Actual_Sha1, Md5: item.Actual_Md5}, Path: path.Join(item.Repo, item.Path, item.Name)}
 }
 
 // Return - manifest dependency as buildinfo.Dependency struct.
 func getManifestDependency(searchResults map[string]*utils.ResultItem) (dependency buildinfo.Dependency) {
-	item := searchResults["manifest.json"]
-	return buildinfo.Dependency{Id: "manifest.json", Type: "json", Checksum: &buildinfo.Checksum{Sha1: item.Actual_Sha1, Md5: item.Actual_Md5}}
+	item := searchResults[manifestFileName]
+	return buildinfo.Dependency{Id: manifestFileName, Type: "json", Checksum: &buildinfo.Checksum{Sha1: item.Actual_Sha1, Md5: item.Actual_Md5}}
 }
 
 // Download and read the config layer from Artifactory.
@@ -338,7 +501,7 @@ func searchManifestAndLayersDetails(builder *buildInfoBuilder, imagePathPattern
 		return
 	}
 	// Check if search results contain manifest.json
-	searchesult, ok := resultMap["manifest.json"]
+	searchesult, ok := resultMap[manifestFileName]
 	if ok {
 		// Found a manifest. Verify manifest is the same as the builder image.
 		if builder.containerManager.GetContainerManagerType() == Kaniko {
@@ -348,7 +511,7 @@ func searchManifestAndLayersDetails(builder *buildInfoBuilder, imagePathPattern
 		}
 	} else {
 		// Check if search results contain fat-manifest.
-		if searchResult, ok := resultMap["list.manifest.json"]; ok {
+		if searchResult, ok := resultMap[fatManifestFileName]; ok {
 			// In case of a fat-manifest, Artifactory will create two folders.
 			// One folder named as the image tag, which contains the fat manifest.
 			// The second folder, named as image's manifest digest, contains the image layers and the image's manifest.
@@ -357,10 +520,8 @@ func searchManifestAndLayersDetails(builder *buildInfoBuilder, imagePathPattern
 			digest, err = getImageDigestFromFatManifest(*searchResult, builder)
 			if err == nil && digest != "" {
 				// Remove tag from pattern, place the manifest digest instead.
-				imagePathPattern = strings.Replace(imagePathPattern, "/*", "", 1)
-				imagePathPattern = path.Join(imagePathPattern[:strings.LastIndex(imagePathPattern, "/")], strings.Replace(digest, ":", "__", 1), "*")
 				// Retry search.
-				return searchManifestAndLayersDetails(builder, imagePathPattern)
+				return searchManifestAndLayersDetails(builder, toDigestPathPattern(imagePathPattern, digest))
 			}
 			log.Debug("Couldn't find maching digest in list.manifest.json")
 		}
@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -42,3 +43,35 @@ func TestGetImageWithDigest(t *testing.T) {
 	assert.Equal(t, "my-image-tag", tag)
 	assert.Equal(t, "sha256:12345", sha256)
 }
+
+func TestToDigestPathPattern(t *testing.T) {
+	assert.Equal(t, "docker-local/hello-world/sha256__1234/*", toDigestPathPattern("docker-local/hello-world/latest/*", "sha256:1234"))
+	assert.Equal(t, "docker-local/path/hello-world/sha256__1234/*", toDigestPathPattern("docker-local/path/hello-world/1.0/*", "sha256:1234"))
+}
+
+func TestPlatformString(t *testing.T) {
+	assert.Equal(t, "linux/amd64", Platform{Os: "linux", Architecture: "amd64"}.String())
+	assert.Equal(t, "linux/arm64/v8", Platform{Os: "linux", Architecture: "arm64", Variant: "v8"}.String())
+	assert.True(t, Platform{Os: "unknown", Architecture: "unknown"}.isUnknown())
+}
+
+func TestCreateMultiPlatformBuildInfo(t *testing.T) {
+	builder := &buildInfoBuilder{
+		image:         NewImage("domain/hello-world:1.0"),
+		multiPlatform: true,
+		artifacts:     []buildinfo.Artifact{{Name: fatManifestFileName}},
+		platformModules: []platformModule{
+			{platform: Platform{Os: "linux", Architecture: "amd64"}, imageId: "sha256:1", artifacts: []buildinfo.Artifact{{Name: manifestFileName}}},
+			{platform: Platform{Os: "linux", Architecture: "arm64", Variant: "v8"}, imageId: "sha256:2", artifacts: []buildinfo.Artifact{{Name: manifestFileName}}},
+		},
+	}
+	buildInfo, err := builder.createBuildInfo("")
+	assert.NoError(t, err)
+	assert.Len(t, buildInfo.Modules, 3)
+	assert.Equal(t, "hello-world:1.0", buildInfo.Modules[0].Id)
+	assert.NotContains(t, buildInfo.Modules[0].Properties, "docker.image.id")
+	assert.Equal(t, fatManifestFileName, buildInfo.Modules[0].Artifacts[0].Name)
+	assert.Equal(t, "linux/amd64/hello-world:1.0", buildInfo.Modules[1].Id)
+	assert.Equal(t, "sha256:1", buildInfo.Modules[1].Properties.(map[string]string)["docker.image.id"])
+	assert.Equal(t, "linux/arm64/v8/hello-world:1.0", buildInfo.Modules[2].Id)
+}
@@ -36,10 +36,19 @@ const (
 	DockerClient ContainerManagerType = iota
 	Podman
 	Kaniko
+	Nerdctl
+	Buildah
+	// Works directly against the registry API of Artifactory, see registryclient.go.
+	RegistryClient
 )
 
 func (cmt ContainerManagerType) String() string {
-	return [...]string{"docker", "podman", "kaniko"}[cmt]
+	return [...]string{"docker", "podman", "kaniko", "nerdctl", "buildah", "registry-client"}[cmt]
+}
+
+// Return true if the container manager is operated through its CLI executable.
+func (cmt ContainerManagerType) IsCli() bool {
+	return cmt != Kaniko && cmt != RegistryClient
 }
 
 // Container image
@@ -165,6 +174,20 @@ type ManifestDetails struct {
 type Platform struct {
 	Architecture string `json:"architecture"`
 	Os           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// Return the platform in the os/architecture[/variant] format, e.g. linux/arm64/v8.
+func (platform Platform) String() string {
+	result := platform.Os + "/" + platform.Architecture
+	if platform.Variant != "" {
+		result += "/" + platform.Variant
+	}
+	return result
+}
+
+func (platform Platform) isUnknown() bool {
+	return platform.Os == "unknown" || platform.Architecture == "unknown"
 }
 
 // Get image system compatibility details
@@ -175,6 +198,14 @@ type getImageSystemCompatibilityCmd struct {
 
 func (getImageSystemCompatibilityCmd *getImageSystemCompatibilityCmd) GetCmd() *exec.Cmd {
 	var cmd []string
+	if getImageSystemCompatibilityCmd.containerManager == Buildah {
+		// Buildah exposes the image config in the OCI format.
+		cmd = append(cmd, "inspect")
+		cmd = append(cmd, "--type", "image")
+		cmd = append(cmd, "--format", "{{ .OCIv1.OS}},{{ .OCIv1.Architecture}}")
+		cmd = append(cmd, getImageSystemCompatibilityCmd.image.tag)
+		return exec.Command(getImageSystemCompatibilityCmd.containerManager.String(), cmd[:]...)
+	}
 	cmd = append(cmd, "image")
 	cmd = append(cmd, "inspect")
 	cmd = append(cmd, getImageSystemCompatibilityCmd.image.tag)
@@ -222,7 +253,7 @@ type LoginCmd struct {
 
 func (loginCmd *LoginCmd) GetCmd() *exec.Cmd {
 	if coreutils.IsWindows() {
-		return exec.Command("cmd", "/C", "echo", "%CONTAINER_MANAGER_PASS%|", "docker", "login", loginCmd.DockerRegistry, "--username", loginCmd.Username, "--password-stdin")
+		return exec.Command("cmd", "/C", "echo", "%CONTAINER_MANAGER_PASS%|", loginCmd.containerManager.String(), "login", loginCmd.DockerRegistry, "--username", loginCmd.Username, "--password-stdin")
 	}
 	cmd := "echo $CONTAINER_MANAGER_PASS " + fmt.Sprintf(`| `+loginCmd.containerManager.String()+` login %s --username="%s" --password-stdin`, loginCmd.DockerRegistry, loginCmd.Username)
 	return exec.Command("sh", "-c", cmd)
@@ -295,7 +326,7 @@ func ContainerManagerLogin(imageTag string, config *ContainerManagerLoginConfig,
 	if indexOfSlash < 0 {
 		return errorutils.CheckError(errors.New(fmt.Sprintf(LoginFailureMessage, containerManager.String(), imageRegistry, containerManager.String())))
 	}
-	cmd = &LoginCmd{DockerRegistry: imageRegistry[:indexOfSlash], Username: config.ServerDetails.User, Password: config.ServerDetails.Password}
+	cmd = &LoginCmd{DockerRegistry: imageRegistry[:indexOfSlash], Username: username, Password: password, containerManager: containerManager}
 	err = gofrogcmd.RunCmd(cmd)
 	if err != nil {
 		// Login failed for both attempts
@@ -0,0 +1,122 @@
+package container
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Copies images, including all the platforms of multi-platform images, between Docker repositories.
+// The source and target repositories may reside on different Artifactory instances.
+// All the transferred blobs and manifests are verified against their digests.
+type ImageCopier struct {
+	source *registryClient
+	target *registryClient
+}
+
+func NewImageCopier(sourceServiceManager artifactory.ArtifactoryServicesManager, sourceRepo string, targetServiceManager artifactory.ArtifactoryServicesManager, targetRepo string) *ImageCopier {
+	return &ImageCopier{
+		source: &registryClient{serviceManager: sourceServiceManager, repo: sourceRepo},
+		target: &registryClient{serviceManager: targetServiceManager, repo: targetRepo},
+	}
+}
+
+// Copy the source image to the target image. The target image may have a different name and tag.
+// Returns the digest of the copied manifest (or manifest list).
+func (ic *ImageCopier) Copy(sourceImage, targetImage *Image) (string, error) {
+	sourceName, sourceReference, err := ic.source.nameAndReference(sourceImage)
+	if err != nil {
+		return "", err
+	}
+	targetName, targetReference, err := ic.target.nameAndReference(targetImage)
+	if err != nil {
+		return "", err
+	}
+	log.Info(fmt.Sprintf("Copying %s/%s:%s to %s/%s:%s...", ic.source.repo, sourceName, sourceReference, ic.target.repo, targetName, targetReference))
+	return ic.copyManifest(sourceName, sourceReference, targetName, targetReference)
+}
+
+// Copy a manifest and everything it references. Manifests referenced by a manifest list are copied by their digests.
+func (ic *ImageCopier) copyManifest(sourceName, sourceReference, targetName, targetReference string) (string, error) {
+	imageManifest, content, mediaType, err := ic.source.getManifest(sourceName, sourceReference)
+	if err != nil {
+		return "", err
+	}
+	digest := calcDigest(content)
+	if isDigest(sourceReference) && sourceReference != digest {
+		return "", errorutils.CheckError(fmt.Errorf("digest mismatch for manifest %s@%s: got %s", sourceName, sourceReference, digest))
+	}
+	if isManifestList(mediaType) {
+		for _, descriptor := range imageManifest.Manifests {
+			if descriptor.Platform != nil {
+				log.Info("Copying platform " + descriptor.Platform.String() + "...")
+			}
+			if _, err = ic.copyManifest(sourceName, descriptor.Digest, targetName, descriptor.Digest); err != nil {
+				return "", err
+			}
+		}
+	} else {
+		for _, blob := range imageManifest.blobs() {
+			if err = ic.copyBlob(sourceName, targetName, blob.Digest); err != nil {
+				return "", err
+			}
+		}
+	}
+	return digest, ic.target.putManifest(targetName, targetReference, content, mediaType)
+}
+
+// Copy a blob through a temporary file, unless it already exists in the target repository.
+func (ic *ImageCopier) copyBlob(sourceName, targetName, digest string) (err error) {
+	exists, err := ic.target.blobExists(targetName, digest)
+	if err != nil || exists {
+		return
+	}
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if deferErr := fileutils.RemoveTempDir(tempDir); err == nil {
+			err = deferErr
+		}
+	}()
+	tempFile, err := ioutil.TempFile(tempDir, "blob")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer tempFile.Close()
+	log.Info("Downloading blob " + digest + "...")
+	reader, resp, err := ic.source.serviceManager.Client().ReadRemoteFile(ic.source.blobUrl(sourceName, digest), ic.source.httpClientDetails(nil))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errorutils.CheckError(errors.New("Artifactory response: " + resp.Status + " for blob " + digest))
+	}
+	hash := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(tempFile, hash), reader); err != nil {
+		return errorutils.CheckError(err)
+	}
+	if actual := "sha256:" + hex.EncodeToString(hash.Sum(nil)); actual != digest {
+		return errorutils.CheckError(fmt.Errorf("digest mismatch for blob %s: got %s", digest, actual))
+	}
+	if err = tempFile.Close(); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return ic.target.uploadBlob(targetName, digest, tempFile.Name())
+}
+
+func isDigest(reference string) bool {
+	return strings.HasPrefix(reference, "sha256:")
+}
@@ -0,0 +1,44 @@
+package container
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageCopierCopyManifestList(t *testing.T) {
+	sourceRegistry, sourceServer, sourceServiceManager := startRegistryStandIn(t)
+	defer sourceServer.Close()
+	targetRegistry, targetServer, targetServiceManager := startRegistryStandIn(t)
+	defer targetServer.Close()
+
+	// Populate the source registry with a manifest list of two platforms.
+	var descriptors []registryDescriptor
+	for _, platform := range []Platform{{Os: "linux", Architecture: "amd64"}, {Os: "linux", Architecture: "arm64", Variant: "v8"}} {
+		config := []byte(`{"os":"` + platform.Os + `","architecture":"` + platform.Architecture + `"}`)
+		sourceRegistry.blobs[calcDigest(config)] = config
+		manifestContent, err := json.Marshal(registryManifest{SchemaVersion: 2, MediaType: DockerManifestMediaType, Config: &registryDescriptor{Digest: calcDigest(config)}})
+		assert.NoError(t, err)
+		sourceRegistry.manifests["hello-world/manifests/"+calcDigest(manifestContent)] = manifestContent
+		platform := platform
+		descriptors = append(descriptors, registryDescriptor{MediaType: DockerManifestMediaType, Digest: calcDigest(manifestContent), Platform: &platform})
+	}
+	listContent, err := json.Marshal(registryManifest{SchemaVersion: 2, MediaType: DockerManifestListMediaType, Manifests: descriptors})
+	assert.NoError(t, err)
+	sourceRegistry.manifests["hello-world/manifests/1.0"] = listContent
+
+	copier := NewImageCopier(sourceServiceManager, "docker-local", targetServiceManager, "docker-prod")
+	digest, err := copier.Copy(NewImage("domain/hello-world:1.0"), NewImage("domain/hello-world-prod:2.0"))
+	assert.NoError(t, err)
+	assert.Equal(t, calcDigest(listContent), digest)
+	assert.Equal(t, listContent, targetRegistry.manifests["hello-world-prod/manifests/2.0"])
+	for _, descriptor := range descriptors {
+		assert.Contains(t, targetRegistry.manifests, "hello-world-prod/manifests/"+descriptor.Digest)
+	}
+	assert.Equal(t, sourceRegistry.blobs, targetRegistry.blobs)
+
+	// A missing source image should fail the copy.
+	_, err = copier.Copy(NewImage("domain/missing:1.0"), NewImage("domain/missing:1.0"))
+	assert.Error(t, err)
+}
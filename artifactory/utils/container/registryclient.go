@@ -0,0 +1,485 @@
+package container
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	DockerManifestMediaType     = "application/vnd.docker.distribution.manifest.v2+json"
+	DockerManifestListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+	OciManifestMediaType        = "application/vnd.oci.image.manifest.v1+json"
+	OciIndexMediaType           = "application/vnd.oci.image.index.v1+json"
+
+	ociLayoutFileName    = "oci-layout"
+	ociIndexFileName     = "index.json"
+	ociBlobsDirName      = "blobs"
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+)
+
+// A container manager which works directly against the Docker Registry HTTP API V2 of an Artifactory repository, without a container daemon or CLI.
+// Images are read from (when pushing) and written to (when pulling) an OCI image layout directory.
+type registryClient struct {
+	serviceManager artifactory.ArtifactoryServicesManager
+	repo           string
+	layoutDir      string
+}
+
+// Create a registry client container manager.
+// repo - the Artifactory Docker repository the images are pushed to and pulled from.
+// layoutDir - the OCI image layout directory. If empty, the working directory is used.
+func NewRegistryClientManager(serviceManager artifactory.ArtifactoryServicesManager, repo, layoutDir string) ContainerManager {
+	if layoutDir == "" {
+		layoutDir = "."
+	}
+	return &registryClient{serviceManager: serviceManager, repo: repo, layoutDir: layoutDir}
+}
+
+func (rc *registryClient) GetContainerManagerType() ContainerManagerType {
+	return RegistryClient
+}
+
+// Upload the image's blobs and manifest from the OCI layout directory.
+// If the manifest is a manifest list, the manifests it references and their blobs are uploaded first.
+func (rc *registryClient) Push(image *Image) error {
+	name, reference, err := rc.nameAndReference(image)
+	if err != nil {
+		return err
+	}
+	descriptor, err := rc.findLayoutManifest(image.Tag(), reference)
+	if err != nil {
+		return err
+	}
+	log.Info("Pushing manifest of " + image.Tag() + "...")
+	return rc.pushManifest(name, reference, descriptor)
+}
+
+// Upload a manifest from the layout directory under the reference, after the blobs and the manifests it references.
+func (rc *registryClient) pushManifest(name, reference string, descriptor *registryDescriptor) error {
+	manifestContent, err := ioutil.ReadFile(rc.blobPath(descriptor.Digest))
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	var imageManifest registryManifest
+	if err = json.Unmarshal(manifestContent, &imageManifest); err != nil {
+		return errorutils.CheckError(err)
+	}
+	mediaType := descriptor.MediaType
+	if mediaType == "" {
+		mediaType = imageManifest.MediaType
+	}
+	if isManifestList(mediaType) {
+		for i := range imageManifest.Manifests {
+			child := &imageManifest.Manifests[i]
+			if err = rc.pushManifest(name, child.Digest, child); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, blob := range imageManifest.blobs() {
+			if err = rc.pushBlob(name, blob.Digest); err != nil {
+				return err
+			}
+		}
+	}
+	return rc.putManifest(name, reference, manifestContent, mediaType)
+}
+
+// Download the image's manifest and blobs into the OCI layout directory.
+// If the tag references a manifest list, only the manifest of the current platform is downloaded.
+func (rc *registryClient) Pull(image *Image) error {
+	name, reference, err := rc.nameAndReference(image)
+	if err != nil {
+		return err
+	}
+	imageManifest, manifestContent, mediaType, err := rc.getPlatformManifest(name, reference)
+	if err != nil {
+		return err
+	}
+	for _, blob := range imageManifest.blobs() {
+		if err = rc.pullBlob(name, blob.Digest); err != nil {
+			return err
+		}
+	}
+	manifestDigest := calcDigest(manifestContent)
+	if err = rc.writeBlob(manifestDigest, manifestContent); err != nil {
+		return err
+	}
+	return rc.addToLayoutIndex(registryDescriptor{
+		MediaType:   mediaType,
+		Digest:      manifestDigest,
+		Size:        int64(len(manifestContent)),
+		Annotations: map[string]string{ociRefNameAnnotation: image.Tag()},
+	})
+}
+
+// Return the image config digest, as stored in the registry.
+func (rc *registryClient) Id(image *Image) (string, error) {
+	name, reference, err := rc.nameAndReference(image)
+	if err != nil {
+		return "", err
+	}
+	imageManifest, _, _, err := rc.getPlatformManifest(name, reference)
+	if err != nil {
+		return "", err
+	}
+	if imageManifest.Config == nil {
+		return "", errorutils.CheckError(errors.New("couldn't find the config of image: " + image.tag))
+	}
+	return imageManifest.Config.Digest, nil
+}
+
+// Return the OS and architecture of the image, as written in its config blob.
+func (rc *registryClient) OsCompatibility(image *Image) (string, string, error) {
+	name, reference, err := rc.nameAndReference(image)
+	if err != nil {
+		return "", "", err
+	}
+	imageManifest, _, _, err := rc.getPlatformManifest(name, reference)
+	if err != nil {
+		return "", "", err
+	}
+	if imageManifest.Config == nil {
+		return "", "", errorutils.CheckError(errors.New("couldn't find the config of image: " + image.tag))
+	}
+	resp, body, err := rc.send(http.MethodGet, rc.blobUrl(name, imageManifest.Config.Digest), nil, nil)
+	if err != nil {
+		return "", "", err
+	}
+	if err = checkRegistryResponse(resp, body, http.StatusOK); err != nil {
+		return "", "", err
+	}
+	var config Platform
+	if err = json.Unmarshal(body, &config); err != nil {
+		return "", "", errorutils.CheckError(err)
+	}
+	if config.Os == "" || config.Architecture == "" {
+		return "", "", errorutils.CheckError(errors.New("couldn't find OS and architecture of image:" + image.tag))
+	}
+	return config.Os, config.Architecture, nil
+}
+
+// Return the image name in the registry and the tag, e.g. 'domain/docker-local/hello-world:1.0' -> 'hello-world', '1.0'.
+// A leading path segment matching the repository is removed, to support proxy-less image tags.
+func (rc *registryClient) nameAndReference(image *Image) (name, reference string, err error) {
+	imagePath, err := image.Path()
+	if err != nil {
+		return
+	}
+	name, reference = path.Split(strings.TrimPrefix(imagePath, "/"))
+	name = strings.TrimSuffix(name, "/")
+	name = strings.TrimPrefix(name, rc.repo+"/")
+	if name == "" {
+		err = errorutils.CheckError(fmt.Errorf("The image '%s' is missing the image name", image.Tag()))
+	}
+	return
+}
+
+func (rc *registryClient) registryUrl(name string) string {
+	return clientUrlJoin(rc.serviceManager.GetConfig().GetServiceDetails().GetUrl(), "api/docker", rc.repo, "v2", name)
+}
+
+func (rc *registryClient) manifestUrl(name, reference string) string {
+	return rc.registryUrl(name) + "/manifests/" + reference
+}
+
+func (rc *registryClient) blobUrl(name, digest string) string {
+	return rc.registryUrl(name) + "/blobs/" + digest
+}
+
+func clientUrlJoin(baseUrl string, paths ...string) string {
+	return strings.TrimSuffix(baseUrl, "/") + "/" + path.Join(paths...)
+}
+
+func (rc *registryClient) httpClientDetails(headers map[string]string) *httputils.HttpClientDetails {
+	details := rc.serviceManager.GetConfig().GetServiceDetails().CreateHttpClientDetails()
+	if details.Headers == nil {
+		details.Headers = map[string]string{}
+	}
+	for key, value := range headers {
+		details.Headers[key] = value
+	}
+	return &details
+}
+
+func (rc *registryClient) send(method, requestUrl string, content []byte, headers map[string]string) (*http.Response, []byte, error) {
+	resp, body, _, err := rc.serviceManager.Client().Send(method, requestUrl, content, true, true, rc.httpClientDetails(headers), "")
+	return resp, body, err
+}
+
+// Get the manifest stored under the reference, as is.
+func (rc *registryClient) getManifest(name, reference string) (imageManifest *registryManifest, content []byte, mediaType string, err error) {
+	accept := strings.Join([]string{DockerManifestMediaType, DockerManifestListMediaType, OciManifestMediaType, OciIndexMediaType}, ",")
+	resp, content, err := rc.send(http.MethodGet, rc.manifestUrl(name, reference), nil, map[string]string{"Accept": accept})
+	if err != nil {
+		return
+	}
+	if err = checkRegistryResponse(resp, content, http.StatusOK); err != nil {
+		return
+	}
+	if err = errorutils.CheckError(json.Unmarshal(content, &imageManifest)); err != nil {
+		return
+	}
+	mediaType = strings.Split(resp.Header.Get("Content-Type"), ";")[0]
+	if mediaType == "" {
+		mediaType = imageManifest.MediaType
+	}
+	return
+}
+
+// Get the image manifest. If the reference points to a manifest list, the manifest matching the current platform is returned.
+func (rc *registryClient) getPlatformManifest(name, reference string) (imageManifest *registryManifest, content []byte, mediaType string, err error) {
+	imageManifest, content, mediaType, err = rc.getManifest(name, reference)
+	if err != nil || !isManifestList(mediaType) {
+		return
+	}
+	digest := imageManifest.platformDigest(runtime.GOOS, runtime.GOARCH)
+	if digest == "" {
+		err = errorutils.CheckError(fmt.Errorf("couldn't find a manifest for platform %s/%s in the manifest list of %s:%s", runtime.GOOS, runtime.GOARCH, name, reference))
+		return
+	}
+	return rc.getPlatformManifest(name, digest)
+}
+
+// Upload a manifest under the reference and verify the digest calculated by the registry, if returned.
+func (rc *registryClient) putManifest(name, reference string, content []byte, mediaType string) error {
+	resp, body, err := rc.send(http.MethodPut, rc.manifestUrl(name, reference), content, map[string]string{"Content-Type": mediaType})
+	if err != nil {
+		return err
+	}
+	if err = checkRegistryResponse(resp, body, http.StatusCreated, http.StatusOK); err != nil {
+		return err
+	}
+	if registryDigest := resp.Header.Get("Docker-Content-Digest"); registryDigest != "" && registryDigest != calcDigest(content) {
+		return errorutils.CheckError(fmt.Errorf("digest mismatch for manifest %s:%s: expected %s, registry calculated %s", name, reference, calcDigest(content), registryDigest))
+	}
+	return nil
+}
+
+func (rc *registryClient) blobExists(name, digest string) (bool, error) {
+	resp, _, err := rc.send(http.MethodHead, rc.blobUrl(name, digest), nil, nil)
+	if err != nil {
+		return false, err
+	}
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+func isManifestList(mediaType string) bool {
+	return mediaType == DockerManifestListMediaType || mediaType == OciIndexMediaType
+}
+
+// Upload a blob from the layout directory, unless it already exists in the registry.
+func (rc *registryClient) pushBlob(name, digest string) error {
+	exists, err := rc.blobExists(name, digest)
+	if err != nil || exists {
+		if exists {
+			log.Debug("Blob " + digest + " already exists in the registry.")
+		}
+		return err
+	}
+	return rc.uploadBlob(name, digest, rc.blobPath(digest))
+}
+
+// Upload a local file as a blob, using a monolithic upload.
+func (rc *registryClient) uploadBlob(name, digest, localPath string) error {
+	resp, body, err := rc.send(http.MethodPost, rc.registryUrl(name)+"/blobs/uploads/", nil, nil)
+	if err != nil {
+		return err
+	}
+	if err = checkRegistryResponse(resp, body, http.StatusAccepted); err != nil {
+		return err
+	}
+	uploadUrl, err := rc.resolveLocation(resp)
+	if err != nil {
+		return err
+	}
+	query := uploadUrl.Query()
+	query.Set("digest", digest)
+	uploadUrl.RawQuery = query.Encode()
+	log.Info("Uploading blob " + digest + "...")
+	resp, body, err = rc.serviceManager.Client().UploadFile(localPath, uploadUrl.String(), "", rc.httpClientDetails(map[string]string{"Content-Type": "application/octet-stream"}), nil)
+	if err != nil {
+		return err
+	}
+	return checkRegistryResponse(resp, body, http.StatusCreated)
+}
+
+// The upload location returned by the registry may be relative to the request URL.
+func (rc *registryClient) resolveLocation(resp *http.Response) (*url.URL, error) {
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return resp.Request.URL.ResolveReference(location), nil
+}
+
+// Download a blob into the layout directory and verify its digest.
+// The blob is streamed to a temporary file, which is renamed once its digest is verified.
+func (rc *registryClient) pullBlob(name, digest string) error {
+	blobPath := rc.blobPath(digest)
+	if exists, err := fileutils.IsFileExists(blobPath, false); err != nil || exists {
+		return err
+	}
+	log.Info("Downloading blob " + digest + "...")
+	reader, resp, err := rc.serviceManager.Client().ReadRemoteFile(rc.blobUrl(name, digest), rc.httpClientDetails(nil))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errorutils.CheckError(errors.New("Artifactory response: " + resp.Status + " for blob " + digest))
+	}
+	if err = os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	tempFile, err := ioutil.TempFile(filepath.Dir(blobPath), filepath.Base(blobPath)+".*.tmp")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer os.Remove(tempFile.Name())
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tempFile, hash), reader)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if actual := "sha256:" + hex.EncodeToString(hash.Sum(nil)); actual != digest {
+		return errorutils.CheckError(fmt.Errorf("digest mismatch for blob %s: got %s", digest, actual))
+	}
+	return errorutils.CheckError(os.Rename(tempFile.Name(), blobPath))
+}
+
+func (rc *registryClient) blobPath(digest string) string {
+	return filepath.Join(rc.layoutDir, ociBlobsDirName, strings.Replace(digest, ":", string(filepath.Separator), 1))
+}
+
+func (rc *registryClient) writeBlob(digest string, content []byte) error {
+	blobPath := rc.blobPath(digest)
+	if err := os.MkdirAll(filepath.Dir(blobPath), 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(ioutil.WriteFile(blobPath, content, 0644))
+}
+
+// Find the manifest descriptor of the image in the layout's index.json.
+// The descriptor is matched by its ref name annotation. If the index contains a single manifest without a ref name, it is used for any tag.
+func (rc *registryClient) findLayoutManifest(imageTag, reference string) (*registryDescriptor, error) {
+	index, err := rc.readLayoutIndex()
+	if err != nil {
+		return nil, err
+	}
+	for i, descriptor := range index.Manifests {
+		refName := descriptor.Annotations[ociRefNameAnnotation]
+		if refName == imageTag || refName == reference {
+			return &index.Manifests[i], nil
+		}
+	}
+	if len(index.Manifests) == 1 && index.Manifests[0].Annotations[ociRefNameAnnotation] == "" {
+		return &index.Manifests[0], nil
+	}
+	return nil, errorutils.CheckError(fmt.Errorf("couldn't find image %s in the OCI layout directory %s", imageTag, rc.layoutDir))
+}
+
+func (rc *registryClient) readLayoutIndex() (*registryManifest, error) {
+	index := &registryManifest{SchemaVersion: 2, MediaType: OciIndexMediaType}
+	content, err := ioutil.ReadFile(filepath.Join(rc.layoutDir, ociIndexFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	return index, errorutils.CheckError(json.Unmarshal(content, index))
+}
+
+// Add a manifest descriptor to the layout's index.json, replacing any descriptor with the same ref name.
+func (rc *registryClient) addToLayoutIndex(descriptor registryDescriptor) error {
+	index, err := rc.readLayoutIndex()
+	if err != nil {
+		return err
+	}
+	manifests := index.Manifests[:0]
+	for _, existing := range index.Manifests {
+		if existing.Annotations[ociRefNameAnnotation] != descriptor.Annotations[ociRefNameAnnotation] {
+			manifests = append(manifests, existing)
+		}
+	}
+	index.Manifests = append(manifests, descriptor)
+	content, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = errorutils.CheckError(ioutil.WriteFile(filepath.Join(rc.layoutDir, ociIndexFileName), content, 0644)); err != nil {
+		return err
+	}
+	return errorutils.CheckError(ioutil.WriteFile(filepath.Join(rc.layoutDir, ociLayoutFileName), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644))
+}
+
+func calcDigest(content []byte) string {
+	checksum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(checksum[:])
+}
+
+func checkRegistryResponse(resp *http.Response, body []byte, expectedStatusCodes ...int) error {
+	for _, statusCode := range expectedStatusCodes {
+		if resp.StatusCode == statusCode {
+			return nil
+		}
+	}
+	return errorutils.CheckError(errors.New("Artifactory response: " + resp.Status + "\n" + string(body)))
+}
+
+// Represents an image manifest, a manifest list or an OCI index.
+type registryManifest struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	MediaType     string               `json:"mediaType,omitempty"`
+	Config        *registryDescriptor  `json:"config,omitempty"`
+	Layers        []registryDescriptor `json:"layers,omitempty"`
+	Manifests     []registryDescriptor `json:"manifests,omitempty"`
+}
+
+// Return the blobs referenced by an image manifest - the config and the layers.
+func (manifest *registryManifest) blobs() []registryDescriptor {
+	if manifest.Config == nil {
+		return manifest.Layers
+	}
+	return append([]registryDescriptor{*manifest.Config}, manifest.Layers...)
+}
+
+// Search for the digest of the manifest matching the given platform, in a manifest list.
+func (manifest *registryManifest) platformDigest(imageOs, imageArch string) string {
+	for _, descriptor := range manifest.Manifests {
+		if descriptor.Platform != nil && descriptor.Platform.Os == imageOs && descriptor.Platform.Architecture == imageArch {
+			return descriptor.Digest
+		}
+	}
+	return ""
+}
+
+type registryDescriptor struct {
+	MediaType   string            `json:"mediaType,omitempty"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Platform    *Platform         `json:"platform,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
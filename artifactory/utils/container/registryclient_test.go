@@ -0,0 +1,183 @@
+package container
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	artutils "github.com/jfrog/jfrog-cli-core/v2/artifactory/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/stretchr/testify/assert"
+)
+
+// A minimal in-memory Docker registry, serving the Artifactory registry API.
+type registryStandIn struct {
+	mutex     sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+}
+
+func newRegistryStandIn() *registryStandIn {
+	return &registryStandIn{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+}
+
+func (registry *registryStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	body, _ := ioutil.ReadAll(r.Body)
+	requestPath := r.URL.Path[strings.Index(r.URL.Path, "/v2/")+len("/v2/"):]
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/upload/") && r.Method == http.MethodPut:
+		if calcDigest(body) != r.URL.Query().Get("digest") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		registry.blobs[r.URL.Query().Get("digest")] = body
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasSuffix(requestPath, "/blobs/uploads/") && r.Method == http.MethodPost:
+		w.Header().Set("Location", "/upload/1")
+		w.WriteHeader(http.StatusAccepted)
+	case strings.Contains(requestPath, "/blobs/"):
+		content, exists := registry.blobs[requestPath[strings.LastIndex(requestPath, "/")+1:]]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(content)
+	case strings.Contains(requestPath, "/manifests/"):
+		if r.Method == http.MethodPut {
+			registry.manifests[requestPath] = body
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		content, exists := registry.manifests[requestPath]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		manifest := &registryManifest{}
+		_ = json.Unmarshal(content, manifest)
+		w.Header().Set("Content-Type", manifest.MediaType)
+		_, _ = w.Write(content)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func createTestLayout(t *testing.T, layoutDir string) (configDigest string) {
+	rc := &registryClient{layoutDir: layoutDir}
+	configContent := []byte(`{"os":"linux","architecture":"arm64"}`)
+	layerContent := []byte("layer")
+	configDigest, layerDigest := calcDigest(configContent), calcDigest(layerContent)
+	assert.NoError(t, rc.writeBlob(configDigest, configContent))
+	assert.NoError(t, rc.writeBlob(layerDigest, layerContent))
+	manifestContent, err := json.Marshal(registryManifest{
+		SchemaVersion: 2,
+		MediaType:     DockerManifestMediaType,
+		Config:        &registryDescriptor{Digest: configDigest, Size: int64(len(configContent))},
+		Layers:        []registryDescriptor{{Digest: layerDigest, Size: int64(len(layerContent))}},
+	})
+	assert.NoError(t, err)
+	manifestDigest := calcDigest(manifestContent)
+	assert.NoError(t, rc.writeBlob(manifestDigest, manifestContent))
+	assert.NoError(t, rc.addToLayoutIndex(registryDescriptor{MediaType: DockerManifestMediaType, Digest: manifestDigest, Size: int64(len(manifestContent)), Annotations: map[string]string{ociRefNameAnnotation: "1.0"}}))
+	return
+}
+
+func startRegistryStandIn(t *testing.T) (*registryStandIn, *httptest.Server, artifactory.ArtifactoryServicesManager) {
+	registry := newRegistryStandIn()
+	server := httptest.NewServer(registry)
+	serviceManager, err := artutils.CreateServiceManager(&config.ServerDetails{ArtifactoryUrl: server.URL + "/artifactory/", User: "admin", Password: "password"}, -1, false)
+	assert.NoError(t, err)
+	return registry, server, serviceManager
+}
+
+func TestRegistryClientPushAndPull(t *testing.T) {
+	registry, server, serviceManager := startRegistryStandIn(t)
+	defer server.Close()
+
+	pushDir, err := ioutil.TempDir("", "push")
+	assert.NoError(t, err)
+	defer os.RemoveAll(pushDir)
+	configDigest := createTestLayout(t, pushDir)
+	image := NewImage("domain/docker-local/hello-world:1.0")
+
+	// Push
+	pushManager := NewRegistryClientManager(serviceManager, "docker-local", pushDir)
+	assert.NoError(t, pushManager.Push(image))
+	assert.Len(t, registry.blobs, 2)
+	assert.Contains(t, registry.manifests, "hello-world/manifests/1.0")
+
+	// Inspect
+	id, err := pushManager.Id(image)
+	assert.NoError(t, err)
+	assert.Equal(t, configDigest, id)
+	imageOs, imageArch, err := pushManager.OsCompatibility(image)
+	assert.NoError(t, err)
+	assert.Equal(t, "linux", imageOs)
+	assert.Equal(t, "arm64", imageArch)
+
+	// Pull
+	pullDir, err := ioutil.TempDir("", "pull")
+	assert.NoError(t, err)
+	defer os.RemoveAll(pullDir)
+	pullManager := NewRegistryClientManager(serviceManager, "docker-local", pullDir)
+	assert.NoError(t, pullManager.Pull(image))
+	for digest := range registry.blobs {
+		assert.FileExists(t, filepath.Join(pullDir, "blobs", strings.Replace(digest, ":", string(filepath.Separator), 1)))
+	}
+	descriptor, err := pullManager.(*registryClient).findLayoutManifest(image.Tag(), "1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, calcDigest(registry.manifests["hello-world/manifests/1.0"]), descriptor.Digest)
+	// The layout holds a single manifest of another tag.
+	_, err = pullManager.(*registryClient).findLayoutManifest("domain/docker-local/hello-world:2.0", "2.0")
+	assert.Error(t, err)
+
+	// Missing image
+	assert.Error(t, pullManager.Pull(NewImage("domain/docker-local/missing:1.0")))
+}
+
+func TestRegistryClientPushManifestList(t *testing.T) {
+	registry, server, serviceManager := startRegistryStandIn(t)
+	defer server.Close()
+	layoutDir, err := ioutil.TempDir("", "push")
+	assert.NoError(t, err)
+	defer os.RemoveAll(layoutDir)
+
+	rc := &registryClient{layoutDir: layoutDir}
+	var children []registryDescriptor
+	for _, arch := range []string{"amd64", "arm64"} {
+		configContent := []byte(`{"os":"linux","architecture":"` + arch + `"}`)
+		layerContent := []byte("layer-" + arch)
+		assert.NoError(t, rc.writeBlob(calcDigest(configContent), configContent))
+		assert.NoError(t, rc.writeBlob(calcDigest(layerContent), layerContent))
+		manifestContent, err := json.Marshal(registryManifest{
+			SchemaVersion: 2,
+			MediaType:     OciManifestMediaType,
+			Config:        &registryDescriptor{Digest: calcDigest(configContent), Size: int64(len(configContent))},
+			Layers:        []registryDescriptor{{Digest: calcDigest(layerContent), Size: int64(len(layerContent))}},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, rc.writeBlob(calcDigest(manifestContent), manifestContent))
+		children = append(children, registryDescriptor{MediaType: OciManifestMediaType, Digest: calcDigest(manifestContent), Size: int64(len(manifestContent)), Platform: &Platform{Os: "linux", Architecture: arch}})
+	}
+	indexContent, err := json.Marshal(registryManifest{SchemaVersion: 2, MediaType: OciIndexMediaType, Manifests: children})
+	assert.NoError(t, err)
+	assert.NoError(t, rc.writeBlob(calcDigest(indexContent), indexContent))
+	assert.NoError(t, rc.addToLayoutIndex(registryDescriptor{MediaType: OciIndexMediaType, Digest: calcDigest(indexContent), Size: int64(len(indexContent)), Annotations: map[string]string{ociRefNameAnnotation: "1.0"}}))
+
+	assert.NoError(t, NewRegistryClientManager(serviceManager, "docker-local", layoutDir).Push(NewImage("domain/docker-local/hello-world:1.0")))
+	// The configs and layers of both platforms.
+	assert.Len(t, registry.blobs, 4)
+	assert.Contains(t, registry.manifests, "hello-world/manifests/1.0")
+	for _, child := range children {
+		assert.Contains(t, registry.manifests, "hello-world/manifests/"+child.Digest)
+	}
+}
@@ -0,0 +1,410 @@
+package helm
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ChartFileName     = "Chart.yaml"
+	ChartLockFileName = "Chart.lock"
+	helmIgnoreFile    = ".helmignore"
+	chartsDirName     = "charts"
+	templatesDirName  = "templates"
+
+	// Build-info module and dependency type of Helm charts.
+	ModuleType buildinfo.ModuleType = "helm"
+	// Build-info dependency type of container images referenced by a chart.
+	ImageDependencyType = "docker"
+)
+
+// Matches image references pinned by digest, e.g. 'acme.jfrog.io/docker-local/hello-world@sha256:30daa5c...'.
+var imageDigestRegex = regexp.MustCompile(`([\w.\-]+(?::\d+)?(?:/[\w.\-]+)*)(?::[\w.\-]+)?@(sha256:[a-f0-9]{64})`)
+
+// The fields of Chart.yaml relevant for packaging and build-info.
+type Chart struct {
+	ApiVersion   string            `yaml:"apiVersion"`
+	Name         string            `yaml:"name"`
+	Version      string            `yaml:"version"`
+	AppVersion   string            `yaml:"appVersion,omitempty"`
+	Dependencies []ChartDependency `yaml:"dependencies,omitempty"`
+}
+
+// Chart.lock, which pins the chart dependencies to exact versions.
+type ChartLock struct {
+	Dependencies []ChartDependency `yaml:"dependencies"`
+	Digest       string            `yaml:"digest"`
+}
+
+type ChartDependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+}
+
+// A container image referenced by its digest from the chart's values or templates.
+type ImageReference struct {
+	Name   string
+	Digest string
+}
+
+// Return the chart archive name, e.g. 'hello-world-1.0.0.tgz'.
+func (chart *Chart) ArchiveName() string {
+	return chart.Name + "-" + chart.Version + ".tgz"
+}
+
+// Return the build-info module ID of the chart, e.g. 'hello-world:1.0.0'.
+func (chart *Chart) BuildInfoModuleId() string {
+	return chart.Name + ":" + chart.Version
+}
+
+// Read Chart.yaml from the chart directory.
+func LoadChart(chartDir string) (*Chart, error) {
+	content, err := ioutil.ReadFile(filepath.Join(chartDir, ChartFileName))
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	chart := &Chart{}
+	if err = yaml.Unmarshal(content, chart); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if chart.Name == "" || chart.Version == "" {
+		return nil, errorutils.CheckError(errors.New("the name and version fields are mandatory in " + filepath.Join(chartDir, ChartFileName)))
+	}
+	return chart, nil
+}
+
+// Read Chart.lock from the chart directory. Returns nil if the chart has no lock file.
+func LoadChartLock(chartDir string) (*ChartLock, error) {
+	content, err := ioutil.ReadFile(filepath.Join(chartDir, ChartLockFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	chartLock := &ChartLock{}
+	return chartLock, errorutils.CheckError(yaml.Unmarshal(content, chartLock))
+}
+
+// Package the chart directory into '<destination>/<name>-<version>.tgz', the same layout 'helm package' creates.
+// Files matching the patterns in .helmignore are excluded, as are archives in the chart's root directory, which are
+// previous packaging outputs. Symlinks are packaged as the files they point to.
+// The archive is written to a temporary file outside the chart directory, and moved to the destination once complete,
+// so that packaging into the chart directory doesn't package the archive itself.
+// Returns the path to the created archive.
+func Package(chartDir, destination string) (string, error) {
+	chart, err := LoadChart(chartDir)
+	if err != nil {
+		return "", err
+	}
+	ignorePatterns, err := readHelmIgnore(chartDir)
+	if err != nil {
+		return "", err
+	}
+	if err = os.MkdirAll(destination, 0755); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	archivePath := filepath.Join(destination, chart.ArchiveName())
+	log.Info("Packaging chart " + chart.BuildInfoModuleId() + " to " + archivePath)
+	archive, err := ioutil.TempFile("", "helm-chart-*.tgz")
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	defer os.Remove(archive.Name())
+	if err = writeChartArchive(archive, chart, chartDir, ignorePatterns); err != nil {
+		archive.Close()
+		return "", err
+	}
+	if err = archive.Close(); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return archivePath, fileutils.MoveFile(archive.Name(), archivePath)
+}
+
+func writeChartArchive(archive io.Writer, chart *Chart, chartDir string, ignorePatterns []string) error {
+	gzipWriter := gzip.NewWriter(archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	err := filepath.Walk(chartDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(chartDir, path)
+		if err != nil || relativePath == "." {
+			return err
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		if isIgnored(relativePath, info.IsDir(), ignorePatterns) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || isPackagingOutput(relativePath) {
+			return nil
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if info, err = os.Stat(path); err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return errors.New("symlinks to directories are not supported: " + path)
+			}
+		}
+		return addToArchive(tarWriter, path, chart.Name+"/"+relativePath, info)
+	})
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	if err = tarWriter.Close(); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(gzipWriter.Close())
+}
+
+// Chart archives in the root of the chart directory are outputs of previous packaging, unlike the archives in the 'charts' directory.
+func isPackagingOutput(relativePath string) bool {
+	return !strings.Contains(relativePath, "/") && strings.HasSuffix(relativePath, ".tgz")
+}
+
+// Add a regular file to the archive. The file info is of the file itself, after resolving symlinks.
+func addToArchive(tarWriter *tar.Writer, path, name string, info os.FileInfo) error {
+	if !info.Mode().IsRegular() {
+		return errors.New("unsupported file type: " + path)
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err = tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(tarWriter, file)
+	return err
+}
+
+// Read the patterns of .helmignore. Empty lines and comments are skipped.
+func readHelmIgnore(chartDir string) ([]string, error) {
+	file, err := os.Open(filepath.Join(chartDir, helmIgnoreFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	defer file.Close()
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns, errorutils.CheckError(scanner.Err())
+}
+
+// Check whether a path relative to the chart directory matches one of the .helmignore patterns.
+// Patterns ending with '/' match directories only. Patterns without '/' are matched against the base name.
+func isIgnored(relativePath string, isDir bool, patterns []string) bool {
+	if relativePath == helmIgnoreFile {
+		return true
+	}
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "/") {
+			if !isDir {
+				continue
+			}
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+		target := relativePath
+		if !strings.Contains(pattern, "/") {
+			target = filepath.Base(relativePath)
+		}
+		if matched, _ := filepath.Match(strings.TrimPrefix(pattern, "/"), target); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Create build-info dependencies of the chart dependencies pinned in Chart.lock.
+// Checksums are added for dependencies that exist as archives in the chart's 'charts' directory.
+func CreateChartDependencies(chartDir string) ([]buildinfo.Dependency, error) {
+	chartLock, err := LoadChartLock(chartDir)
+	if err != nil || chartLock == nil {
+		return nil, err
+	}
+	var dependencies []buildinfo.Dependency
+	for _, chartDependency := range chartLock.Dependencies {
+		dependency := buildinfo.Dependency{Id: chartDependency.Name + ":" + chartDependency.Version, Type: string(ModuleType)}
+		archivePath := filepath.Join(chartDir, chartsDirName, chartDependency.Name+"-"+chartDependency.Version+".tgz")
+		exists, err := fileutils.IsFileExists(archivePath, false)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			details, err := fileutils.GetFileDetails(archivePath)
+			if err != nil {
+				return nil, err
+			}
+			dependency.Checksum = &buildinfo.Checksum{Sha1: details.Checksum.Sha1, Md5: details.Checksum.Md5}
+		} else {
+			log.Debug("The archive of chart dependency " + dependency.Id + " was not found in " + filepath.Join(chartDir, chartsDirName) + ". Its checksums will not be recorded.")
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies, nil
+}
+
+// Search the chart's values files and templates for container images referenced by digest.
+// Images are detected either as 'name@sha256:...' strings, or as values maps holding 'repository' and 'digest' keys (and optionally 'registry').
+func FindImageReferences(chartDir string) ([]ImageReference, error) {
+	var files []string
+	valuesFiles, err := filepath.Glob(filepath.Join(chartDir, "values*.yaml"))
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	files = append(files, valuesFiles...)
+	err = filepath.Walk(filepath.Join(chartDir, templatesDirName), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	found := map[string]ImageReference{}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		for _, match := range imageDigestRegex.FindAllStringSubmatch(string(content), -1) {
+			found[match[1]+"@"+match[2]] = ImageReference{Name: match[1], Digest: match[2]}
+		}
+	}
+	for _, valuesFile := range valuesFiles {
+		content, err := ioutil.ReadFile(valuesFile)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		var values interface{}
+		if err = yaml.Unmarshal(content, &values); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		collectImageValues(values, found)
+	}
+	var references []ImageReference
+	for _, reference := range found {
+		references = append(references, reference)
+	}
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].Name+references[i].Digest < references[j].Name+references[j].Digest
+	})
+	return references, nil
+}
+
+// Walk the values tree and collect maps describing an image by 'repository' and 'digest'.
+func collectImageValues(values interface{}, found map[string]ImageReference) {
+	switch node := values.(type) {
+	case map[interface{}]interface{}:
+		repository, _ := node["repository"].(string)
+		digest, _ := node["digest"].(string)
+		if repository != "" && strings.HasPrefix(digest, "sha256:") {
+			if registry, _ := node["registry"].(string); registry != "" {
+				repository = registry + "/" + repository
+			}
+			found[repository+"@"+digest] = ImageReference{Name: repository, Digest: digest}
+		}
+		for _, child := range node {
+			collectImageValues(child, found)
+		}
+	case []interface{}:
+		for _, child := range node {
+			collectImageValues(child, found)
+		}
+	}
+}
+
+// Extract a chart archive into the destination directory and return the chart directory.
+func ExtractChart(archivePath, destination string) (string, error) {
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	defer archive.Close()
+	gzipReader, err := gzip.NewReader(archive)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+	chartDir := ""
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errorutils.CheckError(err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		targetPath := filepath.Join(destination, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(targetPath, filepath.Clean(destination)+string(os.PathSeparator)) {
+			return "", errorutils.CheckError(errors.New("illegal file path in chart archive: " + header.Name))
+		}
+		if chartDir == "" {
+			chartDir = filepath.Join(destination, strings.SplitN(header.Name, "/", 2)[0])
+		}
+		if err = extractFile(tarReader, targetPath); err != nil {
+			return "", err
+		}
+	}
+	if chartDir == "" {
+		return "", errorutils.CheckError(errors.New("the chart archive is empty: " + archivePath))
+	}
+	return chartDir, nil
+}
+
+func extractFile(reader io.Reader, targetPath string) error {
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	file, err := os.Create(targetPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer file.Close()
+	_, err = io.Copy(file, reader)
+	return errorutils.CheckError(err)
+}
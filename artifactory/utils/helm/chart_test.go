@@ -0,0 +1,105 @@
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	log.SetDefaultLogger()
+}
+
+var testChartDir = filepath.Join("testdata", "hello-chart")
+
+func TestLoadChart(t *testing.T) {
+	chart, err := LoadChart(testChartDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello-chart", chart.Name)
+	assert.Equal(t, "1.2.3", chart.Version)
+	assert.Equal(t, "hello-chart-1.2.3.tgz", chart.ArchiveName())
+	assert.Equal(t, "hello-chart:1.2.3", chart.BuildInfoModuleId())
+}
+
+func TestPackageAndExtract(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "helm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	archivePath, err := Package(testChartDir, tempDir)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tempDir, "hello-chart-1.2.3.tgz"), archivePath)
+
+	chartDir, err := ExtractChart(archivePath, filepath.Join(tempDir, "extracted"))
+	assert.NoError(t, err)
+	var files []string
+	assert.NoError(t, filepath.Walk(chartDir, func(path string, info os.FileInfo, err error) error {
+		if !info.IsDir() {
+			relativePath, _ := filepath.Rel(chartDir, path)
+			files = append(files, filepath.ToSlash(relativePath))
+		}
+		return err
+	}))
+	sort.Strings(files)
+	// The .helmignore file, the ignored 'ci' directory and the '*.bak' files should not be packaged.
+	assert.Equal(t, []string{"Chart.lock", "Chart.yaml", "charts/redis-14.8.8.tgz", "templates/deployment.yaml", "values.yaml"}, files)
+}
+
+// Package into the chart directory itself, which already includes a previous output.
+func TestPackageIntoChartDir(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "helm")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	chartDir := filepath.Join(tempDir, "hello-chart")
+	assert.NoError(t, fileutils.CopyDir(testChartDir, chartDir, true, nil))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(chartDir, "hello-chart-1.0.0.tgz"), []byte("previous output"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, "notes.txt"), []byte("linked notes"), 0644))
+	assert.NoError(t, os.Symlink(filepath.Join(tempDir, "notes.txt"), filepath.Join(chartDir, "templates", "NOTES.txt")))
+
+	for i := 0; i < 2; i++ {
+		archivePath, err := Package(chartDir, chartDir)
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(chartDir, "hello-chart-1.2.3.tgz"), archivePath)
+	}
+
+	extractedDir, err := ExtractChart(filepath.Join(chartDir, "hello-chart-1.2.3.tgz"), filepath.Join(tempDir, "extracted"))
+	assert.NoError(t, err)
+	var files []string
+	assert.NoError(t, filepath.Walk(extractedDir, func(path string, info os.FileInfo, err error) error {
+		if !info.IsDir() {
+			relativePath, _ := filepath.Rel(extractedDir, path)
+			files = append(files, filepath.ToSlash(relativePath))
+		}
+		return err
+	}))
+	sort.Strings(files)
+	assert.Equal(t, []string{"Chart.lock", "Chart.yaml", "charts/redis-14.8.8.tgz", "templates/NOTES.txt", "templates/deployment.yaml", "values.yaml"}, files)
+	content, err := ioutil.ReadFile(filepath.Join(extractedDir, "templates", "NOTES.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "linked notes", string(content))
+}
+
+func TestCreateChartDependencies(t *testing.T) {
+	dependencies, err := CreateChartDependencies(testChartDir)
+	assert.NoError(t, err)
+	assert.Len(t, dependencies, 1)
+	assert.Equal(t, "redis:14.8.8", dependencies[0].Id)
+	assert.NotNil(t, dependencies[0].Checksum)
+	assert.NotEmpty(t, dependencies[0].Sha1)
+}
+
+func TestFindImageReferences(t *testing.T) {
+	references, err := FindImageReferences(testChartDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []ImageReference{
+		{Name: "acme.jfrog.io/docker-local/hello-world", Digest: "sha256:30daa5c11544632449b01f450bebfef6b89644e9e683258ed05797abe7c32a6e"},
+		{Name: "acme.jfrog.io/docker-local/init", Digest: "sha256:2222222222222222222222222222222222222222222222222222222222222222"},
+		{Name: "acme.jfrog.io/docker-local/sidecar", Digest: "sha256:1111111111111111111111111111111111111111111111111111111111111111"},
+	}, references)
+}
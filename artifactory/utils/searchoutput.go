@@ -0,0 +1,331 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type SearchOutputFormat string
+
+const (
+	JsonSearchOutput     SearchOutputFormat = "json"
+	TableSearchOutput    SearchOutputFormat = "table"
+	CsvSearchOutput      SearchOutputFormat = "csv"
+	TemplateSearchOutput SearchOutputFormat = "template"
+)
+
+const (
+	AggregateByRepo   = "repo"
+	AggregateByFolder = "folder"
+
+	// Columns of a search result. A specific property is selected by 'props.<key>'.
+	PathColumn       = "path"
+	TypeColumn       = "type"
+	SizeColumn       = "size"
+	CreatedColumn    = "created"
+	ModifiedColumn   = "modified"
+	Sha1Column       = "sha1"
+	Md5Column        = "md5"
+	propColumnPrefix = "props."
+
+	// Columns of an aggregated search result.
+	GroupColumn     = "group"
+	CountColumn     = "count"
+	TotalSizeColumn = "totalSize"
+)
+
+var defaultSearchColumns = []string{PathColumn, TypeColumn, SizeColumn, CreatedColumn, Sha1Column}
+
+// Determines how search results are printed.
+// The zero value prints all results as JSON, like PrintSearchResults.
+type SearchOutputConfiguration struct {
+	Format SearchOutputFormat
+	// The columns to print in table and CSV formats. If empty, a default set of columns is printed.
+	Columns []string
+	// A Go text/template, executed once per result in the template format.
+	Template string
+	// Aggregate the results by repository or by folder, printing the count and total size of each group instead of the results.
+	AggregateBy string
+	// When aggregating by folder, the number of folders below the repository root that determine the group.
+	FolderDepth int
+}
+
+// The count and total size of the search results in a repository or folder.
+type AggregatedSearchResult struct {
+	Group     string `json:"group"`
+	Count     int    `json:"count"`
+	TotalSize int64  `json:"totalSize"`
+}
+
+func GetSearchOutputFormat(format string) (SearchOutputFormat, error) {
+	switch outputFormat := SearchOutputFormat(strings.ToLower(format)); outputFormat {
+	case "", JsonSearchOutput:
+		return JsonSearchOutput, nil
+	case TableSearchOutput, CsvSearchOutput, TemplateSearchOutput:
+		return outputFormat, nil
+	}
+	return "", errorutils.CheckError(fmt.Errorf("unsupported output format '%s'. Possible values are: json, table, csv and template", format))
+}
+
+// Parse a comma-separated list of columns, such as 'path,size,props.build.name'.
+func ParseSearchColumns(columns string) ([]string, error) {
+	if columns == "" {
+		return nil, nil
+	}
+	var parsed []string
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.TrimSpace(column)
+		if !isValidSearchColumn(column) {
+			return nil, errorutils.CheckError(fmt.Errorf("unsupported column '%s'. Possible values are: path, type, size, created, modified, sha1, md5 and props.<key>", column))
+		}
+		parsed = append(parsed, column)
+	}
+	return parsed, nil
+}
+
+func isValidSearchColumn(column string) bool {
+	switch column {
+	case PathColumn, TypeColumn, SizeColumn, CreatedColumn, ModifiedColumn, Sha1Column, Md5Column:
+		return true
+	}
+	return strings.HasPrefix(column, propColumnPrefix) && len(column) > len(propColumnPrefix)
+}
+
+func (conf *SearchOutputConfiguration) validate() error {
+	if _, err := GetSearchOutputFormat(string(conf.Format)); err != nil {
+		return err
+	}
+	if conf.Format == TemplateSearchOutput && conf.Template == "" {
+		return errorutils.CheckError(errors.New("a template must be provided for the template output format"))
+	}
+	switch conf.AggregateBy {
+	case "", AggregateByRepo:
+	case AggregateByFolder:
+		if conf.FolderDepth < 1 {
+			return errorutils.CheckError(errors.New("the folder depth must be a positive number when aggregating by folder"))
+		}
+	default:
+		return errorutils.CheckError(fmt.Errorf("unsupported aggregation '%s'. Possible values are: repo and folder", conf.AggregateBy))
+	}
+	return nil
+}
+
+// Print the search results according to the output configuration.
+func PrintSearchResultsWithConfiguration(reader *content.ContentReader, conf *SearchOutputConfiguration) error {
+	if conf == nil {
+		return PrintSearchResults(reader)
+	}
+	if err := conf.validate(); err != nil {
+		return err
+	}
+	if conf.AggregateBy != "" {
+		return printAggregatedSearchResults(reader, conf)
+	}
+	switch conf.Format {
+	case TableSearchOutput, CsvSearchOutput:
+		return printSearchResultsAsRows(reader, conf)
+	case TemplateSearchOutput:
+		return printSearchResultsWithTemplate(reader, conf.Template)
+	default:
+		return PrintSearchResults(reader)
+	}
+}
+
+func printSearchResultsAsRows(reader *content.ContentReader, conf *SearchOutputConfiguration) error {
+	columns := conf.Columns
+	if len(columns) == 0 {
+		columns = defaultSearchColumns
+	}
+	rows := [][]string{columns}
+	for searchResult := new(SearchResult); reader.NextRecord(searchResult) == nil; searchResult = new(SearchResult) {
+		rows = append(rows, searchResult.columnValues(columns))
+	}
+	if err := reader.GetError(); err != nil {
+		return err
+	}
+	reader.Reset()
+	return printRows(rows, conf.Format)
+}
+
+// Return the values of the result in the given columns. Multiple values of a property are separated by commas.
+func (sr *SearchResult) columnValues(columns []string) []string {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		switch column {
+		case PathColumn:
+			values[i] = sr.Path
+		case TypeColumn:
+			values[i] = sr.Type
+		case SizeColumn:
+			values[i] = strconv.FormatInt(sr.Size, 10)
+		case CreatedColumn:
+			values[i] = sr.Created
+		case ModifiedColumn:
+			values[i] = sr.Modified
+		case Sha1Column:
+			values[i] = sr.Sha1
+		case Md5Column:
+			values[i] = sr.Md5
+		default:
+			values[i] = strings.Join(sr.Props[strings.TrimPrefix(column, propColumnPrefix)], ",")
+		}
+	}
+	return values
+}
+
+// Print rows as an aligned table or as CSV. The first row is the header.
+func printRows(rows [][]string, format SearchOutputFormat) error {
+	buffer := &bytes.Buffer{}
+	if format == CsvSearchOutput {
+		writer := csv.NewWriter(buffer)
+		if err := writer.WriteAll(rows); err != nil {
+			return errorutils.CheckError(err)
+		}
+	} else {
+		writer := tabwriter.NewWriter(buffer, 0, 0, 2, ' ', 0)
+		for i, row := range rows {
+			if i == 0 {
+				row = toUpper(row)
+			}
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		if err := writer.Flush(); err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	log.Output(strings.TrimSuffix(buffer.String(), "\n"))
+	return nil
+}
+
+func toUpper(values []string) []string {
+	upper := make([]string, len(values))
+	for i, value := range values {
+		upper[i] = strings.ToUpper(value)
+	}
+	return upper
+}
+
+// Functions available in output templates, in addition to the built-in ones.
+var searchTemplateFuncs = template.FuncMap{
+	// Return the values of a property separated by commas, e.g. '{{prop . "build.name"}}'.
+	"prop": func(result SearchResult, key string) string {
+		return strings.Join(result.Props[key], ",")
+	},
+	"join": strings.Join,
+}
+
+func printSearchResultsWithTemplate(reader *content.ContentReader, text string) error {
+	tmpl, err := template.New("search").Funcs(searchTemplateFuncs).Parse(text)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	buffer := &bytes.Buffer{}
+	for searchResult := new(SearchResult); reader.NextRecord(searchResult) == nil; searchResult = new(SearchResult) {
+		buffer.Reset()
+		if err = tmpl.Execute(buffer, *searchResult); err != nil {
+			return errorutils.CheckError(err)
+		}
+		log.Output(buffer.String())
+	}
+	if err = reader.GetError(); err != nil {
+		return err
+	}
+	reader.Reset()
+	return nil
+}
+
+func printAggregatedSearchResults(reader *content.ContentReader, conf *SearchOutputConfiguration) error {
+	aggregated, err := AggregateSearchResults(reader, conf.AggregateBy, conf.FolderDepth)
+	if err != nil {
+		return err
+	}
+	switch conf.Format {
+	case TableSearchOutput, CsvSearchOutput:
+		rows := [][]string{{GroupColumn, CountColumn, TotalSizeColumn}}
+		for _, result := range aggregated {
+			rows = append(rows, []string{result.Group, strconv.Itoa(result.Count), strconv.FormatInt(result.TotalSize, 10)})
+		}
+		return printRows(rows, conf.Format)
+	case TemplateSearchOutput:
+		tmpl, err := template.New("aggregated").Funcs(searchTemplateFuncs).Parse(conf.Template)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		buffer := &bytes.Buffer{}
+		for _, result := range aggregated {
+			buffer.Reset()
+			if err = tmpl.Execute(buffer, result); err != nil {
+				return errorutils.CheckError(err)
+			}
+			log.Output(buffer.String())
+		}
+		return nil
+	default:
+		data, err := json.Marshal(aggregated)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		log.Output(clientutils.IndentJson(data))
+		return nil
+	}
+}
+
+// Sum the count and size of the search results per repository, or per folder at the given depth below the repository root.
+// Results located above the folder depth are grouped under their parent folder. The groups are sorted by name.
+func AggregateSearchResults(reader *content.ContentReader, aggregateBy string, folderDepth int) ([]AggregatedSearchResult, error) {
+	groups := make(map[string]*AggregatedSearchResult)
+	for searchResult := new(SearchResult); reader.NextRecord(searchResult) == nil; searchResult = new(SearchResult) {
+		group := searchResultGroup(searchResult.Path, aggregateBy, folderDepth)
+		aggregated, ok := groups[group]
+		if !ok {
+			aggregated = &AggregatedSearchResult{Group: group}
+			groups[group] = aggregated
+		}
+		aggregated.Count++
+		aggregated.TotalSize += searchResult.Size
+	}
+	if err := reader.GetError(); err != nil {
+		return nil, err
+	}
+	reader.Reset()
+	results := make([]AggregatedSearchResult, 0, len(groups))
+	for _, aggregated := range groups {
+		results = append(results, *aggregated)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Group < results[j].Group
+	})
+	return results, nil
+}
+
+func searchResultGroup(path, aggregateBy string, folderDepth int) string {
+	if aggregateBy == AggregateByRepo {
+		return strings.Split(path, "/")[0]
+	}
+	return GetFolderAtDepth(path, folderDepth)
+}
+
+// Return the folder of an item at the given depth below the repository root, in the form of 'repo/folder1/.../folderN'.
+// The parent folder is returned for items located above that depth.
+func GetFolderAtDepth(itemPath string, depth int) string {
+	parts := strings.Split(itemPath, "/")
+	// Exclude the file name, and keep the repository and up to depth folders.
+	folders := parts[:len(parts)-1]
+	if len(folders) > depth+1 {
+		folders = folders[:depth+1]
+	}
+	return strings.Join(folders, "/")
+}
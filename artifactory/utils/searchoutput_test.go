@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corelog "github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/jfrog/jfrog-client-go/utils/io/content"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func printSearchResultsToBuffer(t *testing.T, conf *SearchOutputConfiguration) (string, error) {
+	testdataPath, err := getTestDataPath()
+	assert.NoError(t, err)
+	reader := content.NewContentReader(filepath.Join(testdataPath, "search_results.json"), content.DefaultKey)
+
+	previousLog := log.Logger
+	newLog := log.NewLogger(corelog.GetCliLogLevel(), nil)
+	defer log.SetLogger(previousLog)
+	buffer := &bytes.Buffer{}
+	newLog.SetOutputWriter(buffer)
+	log.SetLogger(newLog)
+
+	err = PrintSearchResultsWithConfiguration(reader, conf)
+	return buffer.String(), err
+}
+
+func TestPrintSearchResultsAsCsv(t *testing.T) {
+	output, err := printSearchResultsToBuffer(t, &SearchOutputConfiguration{Format: CsvSearchOutput, Columns: []string{PathColumn, SizeColumn, "props.c"}})
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if assert.Len(t, lines, 6) {
+		assert.Equal(t, "path,size,props.c", lines[0])
+		assert.Equal(t, "jfrog-cli-tests-repo1-1595270324/a/b/c/c2.in,11,3", lines[1])
+	}
+}
+
+func TestPrintSearchResultsAsTable(t *testing.T) {
+	output, err := printSearchResultsToBuffer(t, &SearchOutputConfiguration{Format: TableSearchOutput, Columns: []string{SizeColumn, PathColumn}})
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if assert.Len(t, lines, 6) {
+		assert.Equal(t, "SIZE  PATH", lines[0])
+		assert.Equal(t, "11    jfrog-cli-tests-repo1-1595270324/a/b/c/c2.in", lines[1])
+	}
+}
+
+func TestPrintSearchResultsWithTemplate(t *testing.T) {
+	output, err := printSearchResultsToBuffer(t, &SearchOutputConfiguration{Format: TemplateSearchOutput, Template: `{{.Sha1}} {{prop . "c"}}`})
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if assert.Len(t, lines, 5) {
+		assert.Equal(t, "a4f912be11e7d1d346e34c300e6d4b90e136896e 3", lines[0])
+	}
+}
+
+func TestPrintAggregatedSearchResults(t *testing.T) {
+	output, err := printSearchResultsToBuffer(t, &SearchOutputConfiguration{Format: CsvSearchOutput, AggregateBy: AggregateByFolder, FolderDepth: 2})
+	assert.NoError(t, err)
+	// Files in the nested c folder are counted in a/b.
+	assert.Equal(t, "group,count,totalSize\n"+
+		"jfrog-cli-tests-repo1-1595270324/a,1,7\n"+
+		"jfrog-cli-tests-repo1-1595270324/a/b,4,40\n", output)
+}
+
+func TestSearchResultGroup(t *testing.T) {
+	tests := []struct {
+		path        string
+		aggregateBy string
+		depth       int
+		expected    string
+	}{
+		{"repo/a/b/file", AggregateByRepo, 0, "repo"},
+		{"repo/a/b/file", AggregateByFolder, 1, "repo/a"},
+		{"repo/a/b/file", AggregateByFolder, 2, "repo/a/b"},
+		{"repo/a/b/file", AggregateByFolder, 5, "repo/a/b"},
+		{"repo/file", AggregateByFolder, 1, "repo"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, searchResultGroup(test.path, test.aggregateBy, test.depth), test.path)
+	}
+}
+
+func TestInvalidSearchOutputConfiguration(t *testing.T) {
+	_, err := GetSearchOutputFormat("yaml")
+	assert.Error(t, err)
+	_, err = ParseSearchColumns("path,owner")
+	assert.Error(t, err)
+	columns, err := ParseSearchColumns("path, props.build.name")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{PathColumn, "props.build.name"}, columns)
+	assert.Error(t, (&SearchOutputConfiguration{Format: TemplateSearchOutput}).validate())
+	assert.Error(t, (&SearchOutputConfiguration{AggregateBy: AggregateByFolder}).validate())
+}
@@ -0,0 +1,145 @@
+package terraform
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+const (
+	LockFileName = ".terraform.lock.hcl"
+
+	// Build-info module type of Terraform modules.
+	ModuleType buildinfo.ModuleType = "terraform"
+	// Build-info dependency types.
+	ProviderDependencyType = "terraform-provider"
+	ModuleDependencyType   = "terraform-module"
+)
+
+var (
+	// Matches the beginning of a provider block, e.g. 'provider "registry.terraform.io/hashicorp/aws" {'.
+	providerBlockRegex = regexp.MustCompile(`^provider\s+"([^"]+)"\s*\{`)
+	// Matches a string attribute, e.g. 'version = "3.0.0"'.
+	stringAttributeRegex = regexp.MustCompile(`^(\w+)\s*=\s*"([^"]*)"`)
+)
+
+// A provider selection recorded in .terraform.lock.hcl.
+type LockedProvider struct {
+	Source      string
+	Version     string
+	Constraints string
+}
+
+// Parse the provider selections in the dependency lock file of the module directory.
+// Returns nil if the module has no lock file.
+func ReadLockedProviders(moduleDir string) ([]LockedProvider, error) {
+	file, err := os.Open(filepath.Join(moduleDir, LockFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	defer file.Close()
+	var providers []LockedProvider
+	var current *LockedProvider
+	// Nesting depth of braces and brackets, to detect the end of the provider block.
+	depth := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if current == nil {
+			if match := providerBlockRegex.FindStringSubmatch(line); match != nil {
+				current = &LockedProvider{Source: match[1]}
+				depth = 1
+			}
+			continue
+		}
+		if depth == 1 {
+			if match := stringAttributeRegex.FindStringSubmatch(line); match != nil {
+				switch match[1] {
+				case "version":
+					current.Version = match[2]
+				case "constraints":
+					current.Constraints = match[2]
+				}
+			}
+		}
+		depth += strings.Count(line, "{") + strings.Count(line, "[") - strings.Count(line, "}") - strings.Count(line, "]")
+		if depth == 0 {
+			providers = append(providers, *current)
+			current = nil
+		}
+	}
+	return providers, errorutils.CheckError(scanner.Err())
+}
+
+// The modules manifest written by 'terraform init' to .terraform/modules/modules.json.
+type modulesManifest struct {
+	Modules []installedModule `json:"Modules"`
+}
+
+type installedModule struct {
+	Key     string `json:"Key"`
+	Source  string `json:"Source"`
+	Version string `json:"Version"`
+	Dir     string `json:"Dir"`
+}
+
+// Read the remote modules installed for the module directory by 'terraform init'.
+// The root module and modules referenced by a local path are excluded.
+func readInstalledModules(moduleDir string) ([]installedModule, error) {
+	content, err := ioutil.ReadFile(filepath.Join(moduleDir, terraformDirName, "modules", "modules.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	manifest := &modulesManifest{}
+	if err = json.Unmarshal(content, manifest); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var modules []installedModule
+	for _, module := range manifest.Modules {
+		if module.Key == "" || strings.HasPrefix(module.Source, "./") || strings.HasPrefix(module.Source, "../") {
+			continue
+		}
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
+
+// Create the build-info dependencies of the module - the providers locked in .terraform.lock.hcl and the installed remote modules.
+func CreateDependencies(moduleDir string) ([]buildinfo.Dependency, error) {
+	providers, err := ReadLockedProviders(moduleDir)
+	if err != nil {
+		return nil, err
+	}
+	var dependencies []buildinfo.Dependency
+	for _, provider := range providers {
+		dependencies = append(dependencies, buildinfo.Dependency{Id: provider.Source + ":" + provider.Version, Type: ProviderDependencyType})
+	}
+	modules, err := readInstalledModules(moduleDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, module := range modules {
+		id := module.Source
+		if module.Version != "" {
+			id += ":" + module.Version
+		}
+		dependencies = append(dependencies, buildinfo.Dependency{Id: id, Type: ModuleDependencyType})
+	}
+	return dependencies, nil
+}
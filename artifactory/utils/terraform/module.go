@@ -0,0 +1,135 @@
+package terraform
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const terraformDirName = ".terraform"
+
+// A Terraform module to be published.
+type Module struct {
+	// The module name, which is the name of its directory.
+	Name string
+	// The path to the module directory.
+	Dir string
+}
+
+// Return the module path in a Terraform repository, following the registry layout - '<namespace>/<name>/<provider>/<version>.zip'.
+func (module *Module) DeployPath(namespace, provider, version string) string {
+	return path.Join(namespace, module.Name, provider, version+".zip")
+}
+
+// Return the build-info module ID, e.g. 'acme/vpc/aws:1.0.0'.
+func (module *Module) BuildInfoModuleId(namespace, provider, version string) string {
+	return path.Join(namespace, module.Name, provider) + ":" + version
+}
+
+// Find the modules under the root directory.
+// A directory containing .tf files is a module. Its sub-directories (e.g. nested 'modules' directories) are packaged with it, rather than published separately.
+// Hidden directories, such as .terraform and .git, are skipped.
+// Since modules are published by their names, an error is returned if two modules have the same name, e.g. 'aws/network' and 'gcp/network'.
+func FindModules(rootDir string) ([]*Module, error) {
+	var modules []*Module
+	moduleDirs := make(map[string]string)
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return err
+		}
+		if path != rootDir && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		isModule, err := containsTfFiles(path)
+		if err != nil || !isModule {
+			return err
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		name := filepath.Base(absPath)
+		if otherDir, exists := moduleDirs[name]; exists {
+			return fmt.Errorf("the modules in %s and %s have the same name '%s', publish them separately", otherDir, path, name)
+		}
+		moduleDirs[name] = path
+		modules = append(modules, &Module{Name: name, Dir: path})
+		return filepath.SkipDir
+	})
+	return modules, errorutils.CheckError(err)
+}
+
+func containsTfFiles(dir string) (bool, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".tf") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Zip the module directory into the target file. Hidden files and directories are excluded.
+func (module *Module) Zip(targetPath string) error {
+	log.Debug("Zipping module " + module.Name + " to " + targetPath)
+	zipFile, err := os.Create(targetPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer zipFile.Close()
+	zipWriter := zip.NewWriter(zipFile)
+	err = filepath.Walk(module.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == module.Dir {
+			return err
+		}
+		if strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(module.Dir, path)
+		if err != nil {
+			return err
+		}
+		return addToZip(zipWriter, path, filepath.ToSlash(relativePath), info)
+	})
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(zipWriter.Close())
+}
+
+func addToZip(zipWriter *zip.Writer, path, name string, info os.FileInfo) error {
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	writer, err := zipWriter.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(writer, file)
+	return err
+}
@@ -0,0 +1,96 @@
+package terraform
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	log.SetDefaultLogger()
+}
+
+var testInfraDir = filepath.Join("testdata", "infra")
+
+func TestFindModules(t *testing.T) {
+	modules, err := FindModules(testInfraDir)
+	assert.NoError(t, err)
+	var names []string
+	for _, module := range modules {
+		names = append(names, module.Name)
+	}
+	sort.Strings(names)
+	// The hidden directory is skipped, and the nested subnets module is packaged with vpc.
+	assert.Equal(t, []string{"dns", "vpc"}, names)
+}
+
+func TestFindModulesDuplicateNames(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "terraform")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	for _, dir := range []string{"aws/network", "gcp/network"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, dir), 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, dir, "main.tf"), nil, 0644))
+	}
+	_, err = FindModules(tempDir)
+	assert.EqualError(t, err, fmt.Sprintf("the modules in %s and %s have the same name 'network', publish them separately",
+		filepath.Join(tempDir, "aws", "network"), filepath.Join(tempDir, "gcp", "network")))
+}
+
+func TestModulePaths(t *testing.T) {
+	module := &Module{Name: "vpc", Dir: filepath.Join(testInfraDir, "vpc")}
+	assert.Equal(t, "acme/vpc/aws/1.0.0.zip", module.DeployPath("acme", "aws", "1.0.0"))
+	assert.Equal(t, "acme/vpc/aws:1.0.0", module.BuildInfoModuleId("acme", "aws", "1.0.0"))
+}
+
+func TestZip(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "terraform")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	module := &Module{Name: "vpc", Dir: filepath.Join(testInfraDir, "vpc")}
+	zipPath := filepath.Join(tempDir, "vpc.zip")
+	assert.NoError(t, module.Zip(zipPath))
+
+	reader, err := zip.OpenReader(zipPath)
+	assert.NoError(t, err)
+	defer reader.Close()
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"main.tf", "modules/subnets/main.tf"}, names)
+}
+
+func TestCreateDependencies(t *testing.T) {
+	dependencies, err := CreateDependencies(filepath.Join(testInfraDir, "vpc"))
+	assert.NoError(t, err)
+	expected := []buildinfo.Dependency{
+		{Id: "registry.terraform.io/hashicorp/aws:3.74.0", Type: ProviderDependencyType},
+		{Id: "registry.terraform.io/hashicorp/random:3.1.0", Type: ProviderDependencyType},
+		{Id: "registry.terraform.io/cloudposse/label/null:0.25.0", Type: ModuleDependencyType},
+	}
+	assert.Equal(t, expected, dependencies)
+
+	// A module without a lock file or installed modules has no dependencies.
+	dependencies, err = CreateDependencies(filepath.Join(testInfraDir, "dns"))
+	assert.NoError(t, err)
+	assert.Empty(t, dependencies)
+}
+
+func TestReadLockedProviders(t *testing.T) {
+	providers, err := ReadLockedProviders(filepath.Join(testInfraDir, "vpc"))
+	assert.NoError(t, err)
+	if assert.Len(t, providers, 2) {
+		assert.Equal(t, LockedProvider{Source: "registry.terraform.io/hashicorp/aws", Version: "3.74.0", Constraints: ">= 3.0.0"}, providers[0])
+		assert.Equal(t, "3.1.0", providers[1].Version)
+	}
+}
@@ -0,0 +1,8 @@
+module "subnets" {
+  source = "./modules/subnets"
+}
+
+module "labels" {
+  source  = "cloudposse/label/null"
+  version = "0.25.0"
+}
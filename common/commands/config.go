@@ -409,6 +409,111 @@ func Export(serverName string) error {
 	return nil
 }
 
+// Export servers to a bundle file, encrypted with the passphrase. If no server IDs are provided, all the servers are exported.
+func ExportBundle(bundlePath, passphrase string, serverIds []string) error {
+	configurations, err := config.GetAllServersConfigs()
+	if err != nil {
+		return err
+	}
+	servers := configurations
+	if len(serverIds) > 0 {
+		servers = nil
+		for _, serverId := range serverIds {
+			server := findServer(serverId, configurations)
+			if server == nil {
+				return errorutils.CheckError(fmt.Errorf("Server ID '%s' does not exist.", serverId))
+			}
+			servers = append(servers, server)
+		}
+	}
+	if len(servers) == 0 {
+		return errorutils.CheckError(errors.New("no servers were found to export"))
+	}
+	content, err := config.ExportBundle(servers, passphrase)
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(bundlePath, content, 0600); err != nil {
+		return errorutils.CheckError(err)
+	}
+	log.Info(fmt.Sprintf("Exported %d servers to %s.", len(servers), bundlePath))
+	return nil
+}
+
+func findServer(serverId string, configurations []*config.ServerDetails) *config.ServerDetails {
+	for _, server := range configurations {
+		if server.ServerId == serverId {
+			return server
+		}
+	}
+	return nil
+}
+
+// Import the servers of a bundle file created by ExportBundle.
+// Servers whose IDs already exist are handled according to the policy. If defaultServerId is not empty, it is set as the default server.
+func ImportBundle(bundlePath, passphrase string, policy config.BundleImportPolicy, defaultServerId string) error {
+	content, err := ioutil.ReadFile(bundlePath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	imported, err := config.ImportBundle(content, passphrase)
+	if err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	lockFile, err := lock.CreateLock()
+	defer mutex.Unlock()
+	defer lockFile.Unlock()
+	if err != nil {
+		return err
+	}
+	configurations, err := config.GetAllServersConfigs()
+	if err != nil {
+		return err
+	}
+	configurations, importedIds, err := config.MergeBundleServers(configurations, imported, policy, defaultServerId)
+	if err != nil {
+		return err
+	}
+	if err = config.SaveServersConf(configurations); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Imported %d of the %d servers of the bundle: %s", len(importedIds), len(imported), strings.Join(importedIds, ", ")))
+	return nil
+}
+
+// Print how the configuration would be migrated to the current version, without migrating it.
+func MigrateConfigDryRun() error {
+	result, err := config.DryRunConfigMigration()
+	if err != nil {
+		return err
+	}
+	if len(result.Steps) == 0 {
+		log.Info("The configuration is already in version " + strconv.Itoa(result.ToVersion) + ", no migration is needed.")
+		return nil
+	}
+	log.Output("Migrating the configuration from version " + strconv.Itoa(result.FromVersion) + " to version " + strconv.Itoa(result.ToVersion) + ":")
+	for _, step := range result.Steps {
+		log.Output("- " + step)
+	}
+	log.Output()
+	for _, line := range result.Diff {
+		log.Output(line)
+	}
+	return nil
+}
+
+// Restore the configuration from the latest backup, which is created before the configuration is migrated.
+func Rollback() error {
+	backupPath, err := config.RollbackConfig()
+	if err != nil {
+		return err
+	}
+	log.Info("The configuration was restored from the backup at", backupPath)
+	return nil
+}
+
 func printConfigs(configuration []*config.ServerDetails) {
 	for _, details := range configuration {
 		logIfNotEmpty(details.ServerId, "Server ID:\t\t\t", false)
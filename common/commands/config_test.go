@@ -2,11 +2,15 @@ package commands
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -129,6 +133,37 @@ func TestBasicAuthOnlyOption(t *testing.T) {
 	assert.NoError(t, DeleteConfig("test"))
 }
 
+func TestExportImportBundle(t *testing.T) {
+	oldHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer os.Setenv(coreutils.HomeDir, oldHome)
+	defer tests.CleanUnitTestsJfrogHome()
+	tempDir, err := ioutil.TempDir("", "config-bundle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	bundlePath := filepath.Join(tempDir, "servers.bundle")
+
+	assert.NoError(t, config.SaveServersConf([]*config.ServerDetails{
+		{ServerId: "prod", ArtifactoryUrl: "http://prod/artifactory/", User: "admin", Password: "password", IsDefault: true},
+		{ServerId: "dev", ArtifactoryUrl: "http://dev/artifactory/", AccessToken: "token"},
+		{ServerId: "local", ArtifactoryUrl: "http://localhost:8080/artifactory/"},
+	}))
+	assert.Error(t, ExportBundle(bundlePath, "passphrase", []string{"prod", "missing"}))
+	assert.NoError(t, ExportBundle(bundlePath, "passphrase", []string{"prod", "dev"}))
+
+	// Import to a configuration which already includes one of the servers.
+	assert.NoError(t, config.SaveServersConf([]*config.ServerDetails{{ServerId: "dev", ArtifactoryUrl: "http://old-dev/artifactory/", User: "dev-user", IsDefault: true}}))
+	assert.Error(t, ImportBundle(bundlePath, "wrong", config.MergeServers, ""))
+	assert.NoError(t, ImportBundle(bundlePath, "passphrase", config.MergeServers, "prod"))
+
+	servers, err := config.GetAllServersConfigs()
+	assert.NoError(t, err)
+	assert.Equal(t, []*config.ServerDetails{
+		{ServerId: "dev", ArtifactoryUrl: "http://dev/artifactory/", User: "dev-user", AccessToken: "token"},
+		{ServerId: "prod", ArtifactoryUrl: "http://prod/artifactory/", User: "admin", Password: "password", IsDefault: true},
+	}, servers)
+}
+
 func testExportImport(t *testing.T, inputDetails *config.ServerDetails) {
 	serverToken, err := config.Export(inputDetails)
 	assert.NoError(t, err)
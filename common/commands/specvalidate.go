@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"github.com/jfrog/jfrog-cli-core/v2/common/spec"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Validate a JSON or YAML file spec for a command, without running the command.
+// Reports unknown fields and values of the wrong type with their position, and fields which are meaningless for the command.
+type SpecValidateCommand struct {
+	specFilePath string
+	specVars     map[string]string
+	command      spec.SpecCommand
+}
+
+func NewSpecValidateCommand() *SpecValidateCommand {
+	return &SpecValidateCommand{}
+}
+
+func (svc *SpecValidateCommand) SetSpecFilePath(specFilePath string) *SpecValidateCommand {
+	svc.specFilePath = specFilePath
+	return svc
+}
+
+func (svc *SpecValidateCommand) SetSpecVars(specVars map[string]string) *SpecValidateCommand {
+	svc.specVars = specVars
+	return svc
+}
+
+// The command the spec is validated for.
+func (svc *SpecValidateCommand) SetCommand(command spec.SpecCommand) *SpecValidateCommand {
+	svc.command = command
+	return svc
+}
+
+func (svc *SpecValidateCommand) Run() error {
+	if err := spec.ValidateSpecFile(svc.specFilePath, svc.specVars, svc.command); err != nil {
+		return err
+	}
+	log.Info("The spec file " + svc.specFilePath + " is valid for the " + string(svc.command) + " command.")
+	return nil
+}
+
+func (svc *SpecValidateCommand) ServerDetails() (*config.ServerDetails, error) {
+	return nil, nil
+}
+
+func (svc *SpecValidateCommand) CommandName() string {
+	return "spec_validate"
+}
@@ -1,6 +1,7 @@
 package spec
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -23,22 +24,63 @@ func (spec *SpecFiles) Get(index int) *File {
 	return new(File)
 }
 
+// Read a JSON or YAML spec file, which may be a template (see specTemplateRenderer). Unknown fields and values of the wrong type are reported with their line and column.
 func CreateSpecFromFile(specFilePath string, specVars map[string]string) (spec *SpecFiles, err error) {
-	spec = new(SpecFiles)
+	spec, _, err = readSpecFile(specFilePath, specVars)
+	return
+}
+
+// Validate a spec file, including the semantic rules of ValidateSpec and the applicability of its fields to the command.
+// All the problems found are returned in a SpecValidationError.
+func ValidateSpecFile(specFilePath string, specVars map[string]string, command SpecCommand) error {
+	spec, normalized, err := readSpecFile(specFilePath, specVars)
+	if err != nil {
+		return err
+	}
+	validator := &specValidator{}
+	validator.validateApplicability(normalized, command)
+	isTargetMandatory, isSearchBasedSpec, isUpload := command.validationFlags()
+	if err = ValidateSpec(spec.Files, isTargetMandatory, isSearchBasedSpec, isUpload); err != nil {
+		validator.problems = append(validator.problems, SpecProblem{Message: err.Error()})
+	}
+	if len(validator.problems) > 0 {
+		return errorutils.CheckError(&SpecValidationError{SpecFilePath: specFilePath, Problems: validator.problems})
+	}
+	return nil
+}
+
+// Return the spec and its normalized parsed form.
+func readSpecFile(specFilePath string, specVars map[string]string) (*SpecFiles, *specNode, error) {
 	content, err := fileutils.ReadFile(specFilePath)
 	if errorutils.CheckError(err) != nil {
-		return
+		return nil, nil, err
 	}
 
-	if len(specVars) > 0 {
+	// Spec templates reference the spec variables through the template syntax, so they are not replaced in advance.
+	if isSpecTemplate(specFilePath) {
+		if content, err = renderSpecTemplate(specFilePath, content, specVars); err != nil {
+			return nil, nil, fmt.Errorf("failed to render the spec template %s: %s", specFilePath, err.Error())
+		}
+	} else if len(specVars) > 0 {
 		content = coreutils.ReplaceVars(content, specVars)
 	}
 
-	err = json.Unmarshal(content, spec)
-	if errorutils.CheckError(err) != nil {
-		return
+	root, err := parseSpecContent(specFilePath, content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the spec file %s: %s", specFilePath, err.Error())
 	}
-	return
+	validator := &specValidator{}
+	normalized := validator.validateRoot(root)
+	if len(validator.problems) > 0 {
+		return nil, nil, errorutils.CheckError(&SpecValidationError{SpecFilePath: specFilePath, Problems: validator.problems})
+	}
+	buffer := &bytes.Buffer{}
+	if err = normalized.writeJson(buffer); err != nil {
+		return nil, nil, err
+	}
+	spec := new(SpecFiles)
+	err = json.Unmarshal(buffer.Bytes(), spec)
+	return spec, normalized, errorutils.CheckError(err)
 }
 
 type File struct {
@@ -0,0 +1,113 @@
+package spec
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSpecFromYamlFile(t *testing.T) {
+	spec, err := CreateSpecFromFile(filepath.Join("testdata", "upload.yaml"), nil)
+	assert.NoError(t, err)
+	if assert.Len(t, spec.Files, 2) {
+		assert.Equal(t, "build/*.jar", spec.Files[0].Pattern)
+		assert.Equal(t, "true", spec.Files[0].Flat)
+		assert.Equal(t, []string{"*-sources.jar"}, spec.Files[0].Exclusions)
+		isRegexp, err := spec.Files[1].IsRegexp(false)
+		assert.NoError(t, err)
+		assert.True(t, isRegexp)
+	}
+}
+
+func TestCreateSpecFromJsonFile(t *testing.T) {
+	spec, err := CreateSpecFromFile(filepath.Join("testdata", "search.json"), nil)
+	assert.NoError(t, err)
+	if assert.Len(t, spec.Files, 1) {
+		assert.Equal(t, `{"repo":"libs/release","$or":[{"name":"a.zip"},{"name":"b.zip"}]}`, spec.Files[0].Aql.ItemsFind)
+		assert.Equal(t, "true", spec.Files[0].Explode)
+		assert.Equal(t, 10, spec.Files[0].Limit)
+	}
+}
+
+func TestUnknownFieldsAndTypes(t *testing.T) {
+	_, err := CreateSpecFromFile(filepath.Join("testdata", "typo.yaml"), nil)
+	validationErr, ok := err.(*SpecValidationError)
+	if assert.True(t, ok, "unexpected error: %v", err) && assert.Len(t, validationErr.Problems, 2) {
+		assert.Equal(t, SpecProblem{Line: 4, Column: 5, Message: "unknown field 'flatt' (did you mean 'flat'?)"}, validationErr.Problems[0])
+		assert.Equal(t, SpecProblem{Line: 5, Column: 12, Message: "'limit' must be a non-negative integer, but is 'many'"}, validationErr.Problems[1])
+	}
+}
+
+func TestValidateSpecFile(t *testing.T) {
+	assert.NoError(t, ValidateSpecFile(filepath.Join("testdata", "upload.yaml"), nil, UploadSpecCommand))
+
+	// 'explode' is meaningless for search.
+	err := ValidateSpecFile(filepath.Join("testdata", "search.json"), nil, SearchSpecCommand)
+	validationErr, ok := err.(*SpecValidationError)
+	if assert.True(t, ok, "unexpected error: %v", err) && assert.Len(t, validationErr.Problems, 1) {
+		assert.Equal(t, SpecProblem{Line: 10, Column: 4, Message: "'explode' is not applicable to the search command"}, validationErr.Problems[0])
+	}
+	assert.NoError(t, ValidateSpecFile(filepath.Join("testdata", "search.json"), nil, DownloadSpecCommand))
+
+	// The semantic rules of ValidateSpec are checked as well - upload requires a target.
+	err = ValidateSpecFile(filepath.Join("testdata", "search.json"), nil, UploadSpecCommand)
+	assert.Error(t, err)
+}
+
+func TestJsonSyntaxErrorPosition(t *testing.T) {
+	_, err := parseJsonSpec([]byte("{\n  \"files\": [\n    {\"pattern\": \"a\",}\n  ]\n}"))
+	assert.EqualError(t, err, "line 3, column 21: invalid character ',' looking for beginning of value")
+}
+
+func TestJsonTrailingContent(t *testing.T) {
+	_, err := parseJsonSpec([]byte("{\"files\": []}\n{\"files\": []}"))
+	assert.EqualError(t, err, "line 2, column 1: invalid content after the top-level value")
+	_, err = parseJsonSpec([]byte("{\"files\": []} x"))
+	assert.EqualError(t, err, "line 1, column 16: invalid character 'x' looking for beginning of value")
+	_, err = parseJsonSpec([]byte("{\"files\": []}\n\n"))
+	assert.NoError(t, err)
+}
+
+func TestGetSpecSchema(t *testing.T) {
+	content, err := GetSpecSchema()
+	assert.NoError(t, err)
+	schema := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal(content, &schema))
+	files := schema["properties"].(map[string]interface{})["files"].(map[string]interface{})
+	properties := files["items"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Len(t, properties, len(specFields))
+	assert.Contains(t, properties, "validateSymlinks")
+}
+
+func TestSuggestion(t *testing.T) {
+	assert.Equal(t, " (did you mean 'recursive'?)", suggestion("recursiv", specFieldNames()))
+	assert.Equal(t, " (did you mean 'files'?)", suggestion("Fils", []string{filesKey}))
+	assert.Empty(t, suggestion("somethingElse", specFieldNames()))
+}
+
+func TestBoolFieldValues(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "bool-spec")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	createSpec := func(flat string) (*SpecFiles, error) {
+		specFile := filepath.Join(tempDir, "spec.json")
+		assert.NoError(t, ioutil.WriteFile(specFile, []byte(`{"files": [{"pattern": "a/*", "target": "b/", "flat": `+flat+`}]}`), 0600))
+		return CreateSpecFromFile(specFile, nil)
+	}
+
+	// The values accepted by strconv.ParseBool are valid. An empty value is unset, so the default is used.
+	for value, expected := range map[string]bool{`"True"`: true, `"1"`: true, `"f"`: false, `""`: true} {
+		spec, err := createSpec(value)
+		if assert.NoError(t, err, value) && assert.Len(t, spec.Files, 1) {
+			flat, err := spec.Files[0].IsFlat(true)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, flat, value)
+		}
+	}
+	_, err = createSpec(`"yes"`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,324 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"gopkg.in/yaml.v3"
+)
+
+type specNodeKind int
+
+const (
+	mappingNode specNodeKind = iota
+	sequenceNode
+	stringNode
+	boolNode
+	numberNode
+	nullNode
+)
+
+func (kind specNodeKind) String() string {
+	switch kind {
+	case mappingNode:
+		return "an object"
+	case sequenceNode:
+		return "a list"
+	case boolNode:
+		return "a boolean"
+	case numberNode:
+		return "a number"
+	case nullNode:
+		return "null"
+	default:
+		return "a string"
+	}
+}
+
+// A node of a parsed spec, keeping its position in the spec file to report validation errors.
+// JSON and YAML specs are parsed into the same structure.
+type specNode struct {
+	kind specNodeKind
+	// The value of a scalar. Numbers and booleans are normalized to their JSON representation.
+	value string
+	// The keys of a mapping. keys[i] is the key of children[i].
+	keys []*specNode
+	// The values of a mapping or the items of a sequence.
+	children     []*specNode
+	line, column int
+}
+
+func (node *specNode) isScalar() bool {
+	return node.kind != mappingNode && node.kind != sequenceNode
+}
+
+// Write the node as JSON, preserving the order of mapping keys.
+func (node *specNode) writeJson(buffer *bytes.Buffer) error {
+	switch node.kind {
+	case mappingNode:
+		buffer.WriteByte('{')
+		for i, key := range node.keys {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := writeJsonString(buffer, key.value); err != nil {
+				return err
+			}
+			buffer.WriteByte(':')
+			if err := node.children[i].writeJson(buffer); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+	case sequenceNode:
+		buffer.WriteByte('[')
+		for i, child := range node.children {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := child.writeJson(buffer); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+	case stringNode:
+		return writeJsonString(buffer, node.value)
+	case nullNode:
+		buffer.WriteString("null")
+	default:
+		buffer.WriteString(node.value)
+	}
+	return nil
+}
+
+func writeJsonString(buffer *bytes.Buffer, value string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	buffer.Write(data)
+	return nil
+}
+
+// Return true if the spec file should be parsed as YAML - by its extension, or if its content is not a JSON object.
+func isYamlSpec(specFilePath string, content []byte) bool {
+	if isSpecTemplate(specFilePath) {
+		specFilePath = strings.TrimSuffix(specFilePath, filepath.Ext(specFilePath))
+	}
+	switch strings.ToLower(filepath.Ext(specFilePath)) {
+	case ".yaml", ".yml":
+		return true
+	case ".json":
+		return false
+	}
+	return !bytes.HasPrefix(bytes.TrimSpace(content), []byte("{"))
+}
+
+func parseSpecContent(specFilePath string, content []byte) (*specNode, error) {
+	if isYamlSpec(specFilePath, content) {
+		return parseYamlSpec(content)
+	}
+	return parseJsonSpec(content)
+}
+
+func parseYamlSpec(content []byte) (*specNode, error) {
+	document := new(yaml.Node)
+	if err := yaml.Unmarshal(content, document); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if len(document.Content) == 0 {
+		return nil, errorutils.CheckError(fmt.Errorf("the spec is empty"))
+	}
+	return convertYamlNode(document.Content[0])
+}
+
+func convertYamlNode(yamlNode *yaml.Node) (*specNode, error) {
+	for yamlNode.Kind == yaml.AliasNode {
+		yamlNode = yamlNode.Alias
+	}
+	node := &specNode{line: yamlNode.Line, column: yamlNode.Column}
+	switch yamlNode.Kind {
+	case yaml.MappingNode:
+		node.kind = mappingNode
+		for i := 0; i+1 < len(yamlNode.Content); i += 2 {
+			key, err := convertYamlNode(yamlNode.Content[i])
+			if err != nil {
+				return nil, err
+			}
+			value, err := convertYamlNode(yamlNode.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			node.keys = append(node.keys, key)
+			node.children = append(node.children, value)
+		}
+	case yaml.SequenceNode:
+		node.kind = sequenceNode
+		for _, item := range yamlNode.Content {
+			child, err := convertYamlNode(item)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+	case yaml.ScalarNode:
+		return convertYamlScalar(yamlNode, node)
+	default:
+		return nil, errorutils.CheckError(fmt.Errorf("line %d: unexpected YAML node", yamlNode.Line))
+	}
+	return node, nil
+}
+
+func convertYamlScalar(yamlNode *yaml.Node, node *specNode) (*specNode, error) {
+	var err error
+	switch yamlNode.ShortTag() {
+	case "!!bool":
+		var value bool
+		err = yamlNode.Decode(&value)
+		node.kind, node.value = boolNode, strconv.FormatBool(value)
+	case "!!int":
+		var value int64
+		err = yamlNode.Decode(&value)
+		node.kind, node.value = numberNode, strconv.FormatInt(value, 10)
+	case "!!float":
+		var value float64
+		err = yamlNode.Decode(&value)
+		node.kind, node.value = numberNode, strconv.FormatFloat(value, 'f', -1, 64)
+	case "!!null":
+		node.kind = nullNode
+	default:
+		node.kind, node.value = stringNode, yamlNode.Value
+	}
+	return node, errorutils.CheckError(err)
+}
+
+// Parses JSON into specNodes, tracking the line and column of each token.
+type jsonSpecParser struct {
+	content []byte
+	decoder *json.Decoder
+	// The position of offset in the content.
+	offset, line, column int
+}
+
+func parseJsonSpec(content []byte) (*specNode, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	parser := &jsonSpecParser{content: content, decoder: decoder, line: 1, column: 1}
+	node, err := parser.parseValue()
+	if err == nil {
+		err = parser.checkEnd()
+	}
+	if err != nil {
+		if syntaxErr, ok := err.(*json.SyntaxError); ok {
+			line, column := parser.positionOf(int(syntaxErr.Offset))
+			err = fmt.Errorf("line %d, column %d: %s", line, column, syntaxErr.Error())
+		}
+		return nil, errorutils.CheckError(err)
+	}
+	return node, nil
+}
+
+// Verify that nothing but whitespace follows the top-level value, as json.Unmarshal does.
+func (parser *jsonSpecParser) checkEnd() error {
+	line, column := parser.nextTokenPosition()
+	_, err := parser.decoder.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("line %d, column %d: invalid content after the top-level value", line, column)
+}
+
+// Return the line and column of the offset. Offsets must not decrease between calls.
+func (parser *jsonSpecParser) positionOf(offset int) (int, int) {
+	if offset > len(parser.content) {
+		offset = len(parser.content)
+	}
+	for ; parser.offset < offset; parser.offset++ {
+		if parser.content[parser.offset] == '\n' {
+			parser.line++
+			parser.column = 1
+		} else {
+			parser.column++
+		}
+	}
+	return parser.line, parser.column
+}
+
+// Return the position of the next token, skipping whitespace and separators.
+func (parser *jsonSpecParser) nextTokenPosition() (int, int) {
+	offset := int(parser.decoder.InputOffset())
+	for offset < len(parser.content) && strings.IndexByte(" \t\r\n,:", parser.content[offset]) >= 0 {
+		offset++
+	}
+	return parser.positionOf(offset)
+}
+
+func (parser *jsonSpecParser) parseValue() (*specNode, error) {
+	line, column := parser.nextTokenPosition()
+	token, err := parser.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	node := &specNode{line: line, column: column}
+	switch value := token.(type) {
+	case json.Delim:
+		if value == '{' {
+			node.kind = mappingNode
+			err = parser.parseMapping(node)
+		} else {
+			node.kind = sequenceNode
+			err = parser.parseSequence(node)
+		}
+	case string:
+		node.kind, node.value = stringNode, value
+	case bool:
+		node.kind, node.value = boolNode, strconv.FormatBool(value)
+	case json.Number:
+		node.kind, node.value = numberNode, value.String()
+	default:
+		node.kind = nullNode
+	}
+	return node, err
+}
+
+func (parser *jsonSpecParser) parseMapping(node *specNode) error {
+	for parser.decoder.More() {
+		line, column := parser.nextTokenPosition()
+		token, err := parser.decoder.Token()
+		if err != nil {
+			return err
+		}
+		// Keys of JSON objects are always strings.
+		key := &specNode{kind: stringNode, value: token.(string), line: line, column: column}
+		value, err := parser.parseValue()
+		if err != nil {
+			return err
+		}
+		node.keys = append(node.keys, key)
+		node.children = append(node.children, value)
+	}
+	// Consume the closing brace.
+	_, err := parser.decoder.Token()
+	return err
+}
+
+func (parser *jsonSpecParser) parseSequence(node *specNode) error {
+	for parser.decoder.More() {
+		child, err := parser.parseValue()
+		if err != nil {
+			return err
+		}
+		node.children = append(node.children, child)
+	}
+	_, err := parser.decoder.Token()
+	return err
+}
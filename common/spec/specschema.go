@@ -0,0 +1,415 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// The commands that accept a file spec.
+type SpecCommand string
+
+const (
+	UploadSpecCommand               SpecCommand = "upload"
+	DownloadSpecCommand             SpecCommand = "download"
+	SearchSpecCommand               SpecCommand = "search"
+	CopySpecCommand                 SpecCommand = "copy"
+	MoveSpecCommand                 SpecCommand = "move"
+	DeleteSpecCommand               SpecCommand = "delete"
+	SetPropsSpecCommand             SpecCommand = "set-props"
+	DeletePropsSpecCommand          SpecCommand = "delete-props"
+	BuildAddDependenciesSpecCommand SpecCommand = "build-add-dependencies"
+)
+
+var specCommands = []SpecCommand{UploadSpecCommand, DownloadSpecCommand, SearchSpecCommand, CopySpecCommand, MoveSpecCommand,
+	DeleteSpecCommand, SetPropsSpecCommand, DeletePropsSpecCommand, BuildAddDependenciesSpecCommand}
+
+// Commands which search for the files in Artifactory.
+var searchBasedCommands = []SpecCommand{DownloadSpecCommand, SearchSpecCommand, CopySpecCommand, MoveSpecCommand,
+	DeleteSpecCommand, SetPropsSpecCommand, DeletePropsSpecCommand}
+
+func GetSpecCommand(command string) (SpecCommand, error) {
+	for _, specCommand := range specCommands {
+		if string(specCommand) == command {
+			return specCommand, nil
+		}
+	}
+	var names []string
+	for _, specCommand := range specCommands {
+		names = append(names, string(specCommand))
+	}
+	return "", errorutils.CheckError(fmt.Errorf("unsupported command '%s'. Possible values are: %s", command, strings.Join(names, ", ")))
+}
+
+// Return the arguments of ValidateSpec for the command.
+func (command SpecCommand) validationFlags() (isTargetMandatory, isSearchBasedSpec, isUpload bool) {
+	switch command {
+	case UploadSpecCommand:
+		return true, false, true
+	case CopySpecCommand, MoveSpecCommand:
+		return true, true, false
+	case BuildAddDependenciesSpecCommand:
+		return false, false, false
+	default:
+		return false, true, false
+	}
+}
+
+type specFieldKind int
+
+const (
+	stringField specFieldKind = iota
+	boolField
+	intField
+	stringListField
+	aqlField
+)
+
+// A field of a file spec, and the commands it applies to.
+type specField struct {
+	name        string
+	kind        specFieldKind
+	description string
+	enum        []string
+	commands    []SpecCommand
+}
+
+func (field *specField) appliesTo(command SpecCommand) bool {
+	for _, fieldCommand := range field.commands {
+		if fieldCommand == command {
+			return true
+		}
+	}
+	return false
+}
+
+// The fields of spec.File. Keep in sync with the File struct.
+var specFields = []specField{
+	{name: "aql", kind: aqlField, description: "An AQL query matching the files.", commands: searchBasedCommands},
+	{name: "pattern", kind: stringField, description: "The path of the files, which may include wildcards.", commands: specCommands},
+	{name: "exclusions", kind: stringListField, description: "Patterns of files to exclude.", commands: specCommands},
+	{name: "target", kind: stringField, description: "The target path.", commands: []SpecCommand{UploadSpecCommand, DownloadSpecCommand, CopySpecCommand, MoveSpecCommand}},
+	{name: "explode", kind: boolField, description: "Extract the archives after the transfer.", commands: []SpecCommand{UploadSpecCommand, DownloadSpecCommand}},
+	{name: "props", kind: stringField, description: "Properties in the form of 'key1=value1;key2=value2'.", commands: specCommands},
+	{name: "targetProps", kind: stringField, description: "Properties to set on the target files.", commands: []SpecCommand{UploadSpecCommand, DownloadSpecCommand, CopySpecCommand, MoveSpecCommand}},
+	{name: "excludeProps", kind: stringField, description: "Exclude files with these properties.", commands: searchBasedCommands},
+	{name: "sortOrder", kind: stringField, description: "The order of the results, sorted by sortBy.", enum: []string{"asc", "desc"}, commands: searchBasedCommands},
+	{name: "sortBy", kind: stringListField, description: "The fields to sort the results by.", commands: searchBasedCommands},
+	{name: "offset", kind: intField, description: "The number of results to skip.", commands: searchBasedCommands},
+	{name: "limit", kind: intField, description: "The maximum number of results.", commands: searchBasedCommands},
+	{name: "build", kind: stringField, description: "Match the artifacts of a build, in the form of 'name/number'.", commands: searchBasedCommands},
+	{name: "excludeArtifacts", kind: boolField, description: "Exclude the artifacts of the build.", commands: searchBasedCommands},
+	{name: "includeDeps", kind: boolField, description: "Include the dependencies of the build.", commands: searchBasedCommands},
+	{name: "bundle", kind: stringField, description: "Match the files of a release bundle, in the form of 'name/version'.", commands: searchBasedCommands},
+	{name: "recursive", kind: boolField, description: "Match files in sub-directories.", commands: specCommands},
+	{name: "flat", kind: boolField, description: "Do not preserve the directory structure in the target path.", commands: []SpecCommand{UploadSpecCommand, DownloadSpecCommand, CopySpecCommand, MoveSpecCommand}},
+	{name: "regexp", kind: boolField, description: "Interpret the pattern as a regular expression.", commands: []SpecCommand{UploadSpecCommand, BuildAddDependenciesSpecCommand}},
+	{name: "ant", kind: boolField, description: "Interpret the pattern as an Ant pattern.", commands: []SpecCommand{UploadSpecCommand, BuildAddDependenciesSpecCommand}},
+	{name: "includeDirs", kind: boolField, description: "Include directories in the results.", commands: []SpecCommand{UploadSpecCommand, DownloadSpecCommand, SearchSpecCommand, SetPropsSpecCommand, DeletePropsSpecCommand}},
+	{name: "archiveEntries", kind: stringField, description: "Match archives containing entries matching this pattern.", commands: searchBasedCommands},
+	{name: "validateSymlinks", kind: boolField, description: "Validate the checksums of downloaded symlinks.", commands: []SpecCommand{DownloadSpecCommand}},
+	{name: "archive", kind: stringField, description: "Upload the files as an archive of this type.", enum: []string{"zip"}, commands: []SpecCommand{UploadSpecCommand}},
+	{name: "symlinks", kind: boolField, description: "Preserve symlinks.", commands: []SpecCommand{UploadSpecCommand}},
+	{name: "transitive", kind: boolField, description: "Search in remote repositories as well.", commands: searchBasedCommands},
+}
+
+const filesKey = "files"
+
+// The strings accepted by strconv.ParseBool, or an empty string for the default.
+const boolSchemaPattern = "^(1|t|T|TRUE|true|True|0|f|F|FALSE|false|False)?$"
+
+func findSpecField(name string) *specField {
+	for i := range specFields {
+		// Keys are case-insensitive, like JSON unmarshalling.
+		if strings.EqualFold(specFields[i].name, name) {
+			return &specFields[i]
+		}
+	}
+	return nil
+}
+
+// A problem found in a spec, at a line and column of the spec file. Line is 0 for problems without a position.
+type SpecProblem struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (problem SpecProblem) String() string {
+	if problem.Line == 0 {
+		return problem.Message
+	}
+	return fmt.Sprintf("line %d, column %d: %s", problem.Line, problem.Column, problem.Message)
+}
+
+// Returned when a spec is invalid. Includes all the problems found.
+type SpecValidationError struct {
+	SpecFilePath string
+	Problems     []SpecProblem
+}
+
+func (err *SpecValidationError) Error() string {
+	messages := []string{fmt.Sprintf("the spec file %s is invalid:", err.SpecFilePath)}
+	for _, problem := range err.Problems {
+		messages = append(messages, "  "+problem.String())
+	}
+	return strings.Join(messages, "\n")
+}
+
+type specValidator struct {
+	problems []SpecProblem
+}
+
+func (validator *specValidator) addProblem(node *specNode, format string, args ...interface{}) {
+	validator.problems = append(validator.problems, SpecProblem{Line: node.line, Column: node.column, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate the structure of the spec, and return it normalized for unmarshalling into SpecFiles -
+// with canonical keys, booleans as strings and null values removed.
+func (validator *specValidator) validateRoot(root *specNode) *specNode {
+	if root.kind != mappingNode {
+		validator.addProblem(root, "the spec must be an object with a '%s' list", filesKey)
+		return nil
+	}
+	normalized := &specNode{kind: mappingNode}
+	for i, key := range root.keys {
+		if !strings.EqualFold(key.value, filesKey) {
+			validator.addProblem(key, "unknown field '%s'%s", key.value, suggestion(key.value, []string{filesKey}))
+			continue
+		}
+		files := root.children[i]
+		if files.kind != sequenceNode {
+			validator.addProblem(files, "'%s' must be a list, but is %s", filesKey, files.kind)
+			continue
+		}
+		normalizedFiles := &specNode{kind: sequenceNode}
+		for _, file := range files.children {
+			normalizedFiles.children = append(normalizedFiles.children, validator.validateFile(file))
+		}
+		normalized.keys = append(normalized.keys, &specNode{kind: stringNode, value: filesKey})
+		normalized.children = append(normalized.children, normalizedFiles)
+	}
+	return normalized
+}
+
+func (validator *specValidator) validateFile(file *specNode) *specNode {
+	normalized := &specNode{kind: mappingNode}
+	if file.kind != mappingNode {
+		validator.addProblem(file, "a file spec must be an object, but is %s", file.kind)
+		return normalized
+	}
+	seen := make(map[string]bool)
+	for i, key := range file.keys {
+		field := findSpecField(key.value)
+		if field == nil {
+			validator.addProblem(key, "unknown field '%s'%s", key.value, suggestion(key.value, specFieldNames()))
+			continue
+		}
+		if seen[field.name] {
+			validator.addProblem(key, "duplicate field '%s'", field.name)
+			continue
+		}
+		seen[field.name] = true
+		value := file.children[i]
+		if value.kind == nullNode {
+			continue
+		}
+		if normalizedValue := validator.validateFieldValue(field, value); normalizedValue != nil {
+			normalized.keys = append(normalized.keys, &specNode{kind: stringNode, value: field.name, line: key.line, column: key.column})
+			normalized.children = append(normalized.children, normalizedValue)
+		}
+	}
+	return normalized
+}
+
+// Validate the type of the field value, and return it normalized. Returns nil if the value is invalid.
+func (validator *specValidator) validateFieldValue(field *specField, value *specNode) *specNode {
+	switch field.kind {
+	case boolField:
+		// Strings are parsed by the IsXxx getters using strconv.ParseBool, where an empty string means the default.
+		if value.kind == stringNode && value.value == "" {
+			return nil
+		}
+		if _, err := strconv.ParseBool(value.value); err == nil && value.isScalar() {
+			return &specNode{kind: stringNode, value: value.value, line: value.line, column: value.column}
+		}
+		validator.addProblem(value, "'%s' must be true or false, but is '%s'", field.name, value.value)
+	case intField:
+		if value.kind == numberNode && !strings.ContainsAny(value.value, ".eE-") {
+			return value
+		}
+		validator.addProblem(value, "'%s' must be a non-negative integer, but is '%s'", field.name, value.value)
+	case stringListField:
+		if value.kind != sequenceNode {
+			validator.addProblem(value, "'%s' must be a list of strings, but is %s", field.name, value.kind)
+			return nil
+		}
+		normalized := &specNode{kind: sequenceNode, line: value.line, column: value.column}
+		for _, item := range value.children {
+			if !item.isScalar() {
+				validator.addProblem(item, "'%s' must be a list of strings, but contains %s", field.name, item.kind)
+				return nil
+			}
+			normalized.children = append(normalized.children, &specNode{kind: stringNode, value: item.value})
+		}
+		return normalized
+	case aqlField:
+		if value.kind == mappingNode && len(value.keys) == 1 && value.keys[0].value == "items.find" && value.children[0].kind == mappingNode {
+			return value
+		}
+		validator.addProblem(value, "'%s' must be an object with a single 'items.find' query object", field.name)
+	default:
+		if !value.isScalar() {
+			validator.addProblem(value, "'%s' must be a string, but is %s", field.name, value.kind)
+			return nil
+		}
+		if len(field.enum) > 0 && !contains(field.enum, value.value) {
+			validator.addProblem(value, "'%s' must be one of: %s, but is '%s'", field.name, strings.Join(field.enum, ", "), value.value)
+			return nil
+		}
+		return &specNode{kind: stringNode, value: value.value, line: value.line, column: value.column}
+	}
+	return nil
+}
+
+// Report the fields of the normalized spec that are meaningless for the command.
+func (validator *specValidator) validateApplicability(normalized *specNode, command SpecCommand) {
+	for i, key := range normalized.keys {
+		if key.value != filesKey {
+			continue
+		}
+		for _, file := range normalized.children[i].children {
+			for _, fieldKey := range file.keys {
+				if field := findSpecField(fieldKey.value); field != nil && !field.appliesTo(command) {
+					validator.addProblem(fieldKey, "'%s' is not applicable to the %s command", field.name, command)
+				}
+			}
+		}
+	}
+}
+
+func specFieldNames() []string {
+	names := make([]string, len(specFields))
+	for i, field := range specFields {
+		names[i] = field.name
+	}
+	return names
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Return a " (did you mean 'x'?)" suggestion for a misspelled field name, or an empty string if no name is close enough.
+func suggestion(name string, candidates []string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if distance := editDistance(strings.ToLower(name), strings.ToLower(candidate)); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean '%s'?)", best)
+}
+
+// The Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
+
+// Return the JSON Schema of file specs.
+func GetSpecSchema() ([]byte, error) {
+	properties := make(map[string]interface{}, len(specFields))
+	for _, field := range specFields {
+		properties[field.name] = field.schema()
+	}
+	schema := map[string]interface{}{
+		"$schema":  "http://json-schema.org/draft-07/schema#",
+		"title":    "JFrog CLI file spec",
+		"type":     "object",
+		"required": []string{filesKey},
+		"properties": map[string]interface{}{
+			filesKey: map[string]interface{}{
+				"type":     "array",
+				"minItems": 1,
+				"items": map[string]interface{}{
+					"type":                 "object",
+					"properties":           properties,
+					"additionalProperties": false,
+				},
+			},
+		},
+		"additionalProperties": false,
+	}
+	content, err := json.MarshalIndent(schema, "", "  ")
+	return content, errorutils.CheckError(err)
+}
+
+func (field *specField) schema() map[string]interface{} {
+	var schema map[string]interface{}
+	switch field.kind {
+	case boolField:
+		schema = map[string]interface{}{"type": []string{"boolean", "string"}, "pattern": boolSchemaPattern}
+	case intField:
+		schema = map[string]interface{}{"type": "integer", "minimum": 0}
+	case stringListField:
+		schema = map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+	case aqlField:
+		schema = map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{"items.find": map[string]interface{}{"type": "object"}},
+			"required":             []string{"items.find"},
+			"additionalProperties": false,
+		}
+	default:
+		schema = map[string]interface{}{"type": "string"}
+		if len(field.enum) > 0 {
+			schema["enum"] = field.enum
+		}
+	}
+	schema["description"] = field.description
+	var commands []string
+	for _, command := range field.commands {
+		commands = append(commands, string(command))
+	}
+	sort.Strings(commands)
+	schema["x-commands"] = commands
+	return schema
+}
@@ -0,0 +1,134 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+)
+
+// Spec files with this extension are rendered as templates, e.g. 'upload.yaml.tmpl'.
+// The format of the rendered spec is determined by the extension which precedes it.
+const specTemplateExtension = ".tmpl"
+
+// The data available to spec templates.
+type specTemplateData struct {
+	// The spec variables, e.g. '{{ .Vars.version }}'.
+	Vars map[string]string
+}
+
+// Renders spec templates, written in Go text/template syntax, with the following functions:
+//
+//	env "NAME" ["default"]     - the value of an environment variable, or the default if it is not set.
+//	var "key" ["default"]      - the value of a spec variable, or the default if it is not provided.
+//	split "a,b,c" ","          - a list to iterate over with 'range', e.g. the modules of a project.
+//	list "a" "b" "c"           - a list of the arguments.
+//	include "path"             - the rendered content of another spec file, relative to the including file.
+//	                             The included file is rendered as a template as well, regardless of its extension.
+//	indent 4 "text"            - the text with each line indented, to embed included YAML fragments.
+//	quote "text"               - the text as a JSON string, which is also a valid YAML string.
+type specTemplateRenderer struct {
+	specVars map[string]string
+	// The absolute paths of the files being rendered, to detect include cycles.
+	includeStack []string
+}
+
+// Return true if the spec file is a template. Other specs are used as is, even if they contain '{{'.
+func isSpecTemplate(specFilePath string) bool {
+	return strings.EqualFold(filepath.Ext(specFilePath), specTemplateExtension)
+}
+
+func renderSpecTemplate(specFilePath string, content []byte, specVars map[string]string) ([]byte, error) {
+	renderer := &specTemplateRenderer{specVars: specVars}
+	rendered, err := renderer.render(specFilePath, content)
+	return []byte(rendered), err
+}
+
+func (renderer *specTemplateRenderer) render(specFilePath string, content []byte) (string, error) {
+	absPath, err := filepath.Abs(specFilePath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	for _, included := range renderer.includeStack {
+		if included == absPath {
+			return "", errorutils.CheckError(fmt.Errorf("the spec file %s includes itself: %s", specFilePath, strings.Join(append(renderer.includeStack, absPath), " -> ")))
+		}
+	}
+	renderer.includeStack = append(renderer.includeStack, absPath)
+	defer func() {
+		renderer.includeStack = renderer.includeStack[:len(renderer.includeStack)-1]
+	}()
+
+	tmpl, err := template.New(filepath.Base(specFilePath)).Option("missingkey=error").Funcs(renderer.funcs(filepath.Dir(absPath))).Parse(string(content))
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	vars := renderer.specVars
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+	buffer := &bytes.Buffer{}
+	if err = tmpl.Execute(buffer, specTemplateData{Vars: vars}); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return buffer.String(), nil
+}
+
+func (renderer *specTemplateRenderer) funcs(dir string) template.FuncMap {
+	return template.FuncMap{
+		"env": func(name string, defaultValue ...string) string {
+			if value, exists := os.LookupEnv(name); exists {
+				return value
+			}
+			return strings.Join(defaultValue, "")
+		},
+		"var": func(key string, defaultValue ...string) string {
+			if value, exists := renderer.specVars[key]; exists {
+				return value
+			}
+			return strings.Join(defaultValue, "")
+		},
+		"split": func(value, separator string) []string {
+			var values []string
+			for _, item := range strings.Split(value, separator) {
+				if item = strings.TrimSpace(item); item != "" {
+					values = append(values, item)
+				}
+			}
+			return values
+		},
+		"list": func(values ...string) []string {
+			return values
+		},
+		"include": func(path string) (string, error) {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			content, err := fileutils.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+			return renderer.render(path, content)
+		},
+		"indent": func(spaces int, text string) string {
+			padding := strings.Repeat(" ", spaces)
+			lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+			for i, line := range lines {
+				if line != "" {
+					lines[i] = padding + line
+				}
+			}
+			return strings.Join(lines, "\n")
+		},
+		"quote": func(value string) (string, error) {
+			data, err := json.Marshal(value)
+			return string(data), errorutils.CheckError(err)
+		},
+	}
+}
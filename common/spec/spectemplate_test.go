@@ -0,0 +1,64 @@
+package spec
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	log.SetDefaultLogger()
+}
+
+func TestTemplatedSpec(t *testing.T) {
+	assert.NoError(t, os.Setenv("SPEC_TEST_REPO", "libs-release"))
+	defer os.Unsetenv("SPEC_TEST_REPO")
+	spec, err := CreateSpecFromFile(filepath.Join("testdata", "services.yaml.tmpl"), map[string]string{"services": "auth, billing, gateway", "version": "1.2.0"})
+	assert.NoError(t, err)
+	if assert.Len(t, spec.Files, 3) {
+		assert.Equal(t, "auth/build/*.jar", spec.Files[0].Pattern)
+		assert.Equal(t, "libs-release/billing/1.2.0/", spec.Files[1].Target)
+		assert.Equal(t, "true", spec.Files[2].Flat)
+		// Spec variables are replaced in included fragments as well.
+		assert.Equal(t, "version=1.2.0", spec.Files[2].Props)
+	}
+}
+
+func TestTemplatedSpecDefaults(t *testing.T) {
+	spec, err := CreateSpecFromFile(filepath.Join("testdata", "services.yaml.tmpl"), map[string]string{"version": "1.0.0"})
+	assert.NoError(t, err)
+	if assert.Len(t, spec.Files, 2) {
+		assert.Equal(t, "libs-snapshot/api/1.0.0/", spec.Files[0].Target)
+		assert.Equal(t, "web/build/*.jar", spec.Files[1].Pattern)
+	}
+
+	// A missing variable referenced through .Vars is an error.
+	_, err = CreateSpecFromFile(filepath.Join("testdata", "services.yaml.tmpl"), nil)
+	assert.Error(t, err)
+}
+
+func TestTemplateIncludeCycle(t *testing.T) {
+	_, err := CreateSpecFromFile(filepath.Join("testdata", "cycle.yaml.tmpl"), nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "includes itself")
+	}
+}
+
+func TestNonTemplateSpecWithBraces(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "spec")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	// Only '.tmpl' specs are rendered, so braces in other specs, and in the values of spec variables, are used as is.
+	specPath := filepath.Join(tempDir, "upload.json")
+	assert.NoError(t, ioutil.WriteFile(specPath, []byte(`{"files": [{"pattern": "dist/{{a,b}}.zip", "target": "repo/${dir}/"}]}`), 0644))
+	spec, err := CreateSpecFromFile(specPath, map[string]string{"dir": "{{ .Vars.dir }}"})
+	assert.NoError(t, err)
+	if assert.Len(t, spec.Files, 1) {
+		assert.Equal(t, "dist/{{a,b}}.zip", spec.Files[0].Pattern)
+		assert.Equal(t, "repo/{{ .Vars.dir }}/", spec.Files[0].Target)
+	}
+}
@@ -0,0 +1,242 @@
+package completion
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/codegangsta/cli"
+	"github.com/jfrog/jfrog-cli-core/v2/common/commands"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type Shell string
+
+const (
+	Zsh        Shell = "zsh"
+	Fish       Shell = "fish"
+	PowerShell Shell = "powershell"
+
+	serverIdFlag = "server-id"
+
+	ServerIdsCommandName = "hidden-completion-server-ids"
+)
+
+var Shells = []Shell{Zsh, Fish, PowerShell}
+
+// A command, as needed for completion. Core commands and plugin commands are both converted to this structure,
+// so that the completion script of the CLI includes both.
+type Command struct {
+	Name        string
+	Aliases     []string
+	Description string
+	Flags       []Flag
+	Subcommands []Command
+}
+
+type Flag struct {
+	// The flag names, without the leading dashes.
+	Names       []string
+	Description string
+	// Bool flags take no value.
+	IsBool bool
+	// The values suggested for the flag, if any.
+	Values []string
+	// The values of server ID flags are the IDs of the servers configured when completing,
+	// which the script gets by running the program's hidden server IDs command.
+	IsServerId bool
+}
+
+// Generate the completion script of the given shell, for a program whose top-level commands are given.
+// To complete the values of server ID flags, the program must include the command returned by CreateServerIdsCommand.
+func Generate(shell Shell, program string, commands []Command) (string, error) {
+	root := &Command{Name: program, Subcommands: commands}
+	switch shell {
+	case Zsh:
+		return createZshScript(root), nil
+	case Fish:
+		return createFishScript(root), nil
+	case PowerShell:
+		return createPowerShellScript(root), nil
+	}
+	return "", errorutils.CheckError(errors.New(fmt.Sprintf("unsupported shell '%s', the supported shells are: %s", shell, joinShells())))
+}
+
+func joinShells() string {
+	var shells []string
+	for _, shell := range Shells {
+		shells = append(shells, string(shell))
+	}
+	return strings.Join(shells, ", ")
+}
+
+// Convert the commands of a cli.App, such as the core commands of the CLI. Hidden commands are skipped.
+func CreateCommandsFromCli(cliCommands []cli.Command) []Command {
+	var converted []Command
+	for _, cliCommand := range cliCommands {
+		if cliCommand.Hidden {
+			continue
+		}
+		command := Command{
+			Name:        cliCommand.Name,
+			Aliases:     cliCommand.Aliases,
+			Description: cliCommand.Usage,
+			Subcommands: CreateCommandsFromCli(cliCommand.Subcommands),
+		}
+		if command.Description == "" {
+			command.Description = cliCommand.Description
+		}
+		for _, cliFlag := range cliCommand.Flags {
+			command.Flags = append(command.Flags, createFlagFromCli(cliFlag))
+		}
+		converted = append(converted, command)
+	}
+	return converted
+}
+
+func createFlagFromCli(cliFlag cli.Flag) Flag {
+	flag := Flag{}
+	for _, name := range strings.Split(cliFlag.GetName(), ",") {
+		flag.Names = append(flag.Names, strings.TrimSpace(name))
+	}
+	switch cliFlag.(type) {
+	case cli.BoolFlag, cli.BoolTFlag:
+		flag.IsBool = true
+	}
+	// The description follows the flag's names and placeholder.
+	if parts := strings.SplitN(cliFlag.String(), "\t", 2); len(parts) == 2 {
+		flag.Description = strings.TrimSpace(parts[1])
+	}
+	flag.IsServerId = isServerIdFlag(flag)
+	return flag
+}
+
+// Convert the commands of a plugin, which are invoked as 'jfrog <plugin> <command>', to a single top-level command.
+// The values of enum flags are suggested.
+func CreatePluginCommand(signature *components.PluginSignature) Command {
+	return Command{
+		Name:        signature.Name,
+		Description: signature.Usage,
+		Subcommands: createCommandsFromSignatures(signature.Commands),
+	}
+}
+
+func createCommandsFromSignatures(signatures []components.CommandSignature) []Command {
+	var converted []Command
+	for _, signature := range signatures {
+		command := Command{
+			Name:        signature.Name,
+			Aliases:     signature.Aliases,
+			Description: signature.Description,
+			Subcommands: createCommandsFromSignatures(signature.Subcommands),
+		}
+		for _, flagSignature := range signature.Flags {
+			flag := Flag{
+				Names:       []string{flagSignature.Name},
+				Description: flagSignature.Description,
+				IsBool:      flagSignature.Type == "bool",
+				Values:      flagSignature.AllowedValues,
+			}
+			flag.IsServerId = isServerIdFlag(flag)
+			command.Flags = append(command.Flags, flag)
+		}
+		converted = append(converted, command)
+	}
+	return converted
+}
+
+func isServerIdFlag(flag Flag) bool {
+	return len(flag.Values) == 0 && flag.Names[0] == serverIdFlag
+}
+
+// A hidden command, which prints the IDs of the configured servers, one per line.
+// The completion scripts run it to complete the values of server ID flags.
+func CreateServerIdsCommand() cli.Command {
+	return cli.Command{
+		Name:     ServerIdsCommandName,
+		Hidden:   true,
+		HideHelp: true,
+		Action: func(c *cli.Context) error {
+			for _, serverId := range commands.GetAllServerIds() {
+				log.Output(serverId)
+			}
+			return nil
+		},
+	}
+}
+
+// The command path is the program name followed by the names of the commands. For example: 'jfrog rt upload'.
+type pathCommand struct {
+	path    string
+	command *Command
+}
+
+// Return the commands of the tree with their paths, sorted by path.
+func walkCommands(root *Command) []pathCommand {
+	pathCommands := []pathCommand{{path: root.Name, command: root}}
+	for i := 0; i < len(pathCommands); i++ {
+		current := pathCommands[i]
+		for j := range current.command.Subcommands {
+			subcommand := &current.command.Subcommands[j]
+			pathCommands = append(pathCommands, pathCommand{path: current.path + " " + subcommand.Name, command: subcommand})
+		}
+	}
+	sort.Slice(pathCommands, func(i, j int) bool {
+		return pathCommands[i].path < pathCommands[j].path
+	})
+	return pathCommands
+}
+
+// Map each command name and alias, following the path of its parent, to the command's path.
+// For example: 'jfrog rt u' -> 'jfrog rt upload'.
+func createPathsMap(pathCommands []pathCommand) map[string]string {
+	paths := make(map[string]string)
+	for _, parent := range pathCommands {
+		for _, subcommand := range parent.command.Subcommands {
+			for _, name := range append([]string{subcommand.Name}, subcommand.Aliases...) {
+				paths[parent.path+" "+name] = parent.path + " " + subcommand.Name
+			}
+		}
+	}
+	return paths
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// The flag's names with their leading dashes. For example: --server-id.
+func dashedNames(flag Flag) []string {
+	var names []string
+	for _, name := range flag.Names {
+		if len(name) == 1 {
+			names = append(names, "-"+name)
+		} else {
+			names = append(names, "--"+name)
+		}
+	}
+	return names
+}
+
+// Shell function names cannot include all the characters a program name may include.
+func functionName(program string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, program)
+}
+
+// Descriptions are shown on a single line.
+func singleLine(description string) string {
+	return strings.Join(strings.Fields(description), " ")
+}
@@ -0,0 +1,155 @@
+package completion
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/codegangsta/cli"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCommands() []Command {
+	cliCommands := []cli.Command{
+		{
+			Name:    "upload",
+			Aliases: []string{"u"},
+			Usage:   "Upload files.",
+			Flags: []cli.Flag{
+				cli.StringFlag{Name: "server-id", Usage: "Server ID."},
+				cli.BoolFlag{Name: "dry-run", Usage: "Don't upload."},
+			},
+		},
+		{Name: "secret", Usage: "A hidden command.", Hidden: true},
+	}
+	signature := &components.PluginSignature{
+		Name:  "repo-plugin",
+		Usage: "Manage repositories.",
+		Commands: []components.CommandSignature{{
+			Name:        "create",
+			Description: "Create a repository.",
+			Flags: []components.FlagSignature{
+				{Name: "type", Description: "Repository type.", Type: "enum", AllowedValues: []string{"local", "remote"}},
+			},
+		}},
+	}
+	commands := CreateCommandsFromCli(cliCommands)
+	return append(commands, CreatePluginCommand(signature))
+}
+
+func TestCreateCommands(t *testing.T) {
+	commands := createTestCommands()
+	if !assert.Len(t, commands, 2) {
+		return
+	}
+	upload := commands[0]
+	assert.Equal(t, "upload", upload.Name)
+	assert.Equal(t, []string{"u"}, upload.Aliases)
+	assert.Equal(t, "Upload files.", upload.Description)
+	assert.Equal(t, []Flag{
+		{Names: []string{"server-id"}, Description: "Server ID.", IsServerId: true},
+		{Names: []string{"dry-run"}, Description: "Don't upload.", IsBool: true},
+	}, upload.Flags)
+
+	plugin := commands[1]
+	assert.Equal(t, "repo-plugin", plugin.Name)
+	if assert.Len(t, plugin.Subcommands, 1) {
+		assert.Equal(t, []Flag{{Names: []string{"type"}, Description: "Repository type.", Values: []string{"local", "remote"}}}, plugin.Subcommands[0].Flags)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	commands := []Command{
+		{
+			Name:        "upload",
+			Aliases:     []string{"u"},
+			Description: "Upload files.",
+			Flags:       []Flag{{Names: []string{"server-id"}, IsServerId: true}, {Names: []string{"dry-run"}, Description: "Don't upload.", IsBool: true}},
+		},
+		{
+			Name:        "repo-plugin",
+			Subcommands: []Command{{Name: "create", Flags: []Flag{{Names: []string{"type"}, Values: []string{"local", "remote"}}}}},
+		},
+	}
+	tests := []struct {
+		shell    Shell
+		expected []string
+	}{
+		{Zsh, []string{
+			"#compdef jfrog",
+			"  $'jfrog u' $'jfrog upload'\n",
+			"  $'jfrog' $'upload:Upload files.\\nu:Upload files.\\nrepo-plugin'\n",
+			"  $'jfrog upload' $'--server-id\\n--dry-run:Don\\'t upload.'\n",
+			"  $'jfrog repo-plugin create --type' $'local\\nremote'\n",
+			"_jfrog_server_id_flags=(\n  $'jfrog upload --server-id' $''\n)\n",
+			"candidates=(${(f)\"$(jfrog hidden-completion-server-ids 2>/dev/null)\"})\n",
+			"  if (( ! ${+_jfrog_commands[$path_key]} )); then\n    _files\n",
+			"compdef _jfrog jfrog",
+		}},
+		{Fish, []string{
+			"        case 'jfrog u'\n            echo 'jfrog upload'\n",
+			"complete -c 'jfrog' -n '__jfrog_path_is \\'jfrog\\'' -f\n",
+			"complete -c 'jfrog' -n '__jfrog_path_is \\'jfrog repo-plugin\\'' -f\n",
+			"complete -c 'jfrog' -n '__jfrog_path_is \\'jfrog\\'' -a 'u' -d 'Upload files.'\n",
+			"complete -c 'jfrog' -n '__jfrog_path_is \\'jfrog upload\\'' -l 'server-id' -x -a '(\\'jfrog\\' hidden-completion-server-ids 2>/dev/null)'\n",
+			"complete -c 'jfrog' -n '__jfrog_path_is \\'jfrog upload\\'' -l 'dry-run' -d 'Don\\'t upload.'\n",
+			"complete -c 'jfrog' -n '__jfrog_path_is \\'jfrog repo-plugin create\\'' -l 'type' -x -a 'local remote'\n",
+		}},
+		{PowerShell, []string{
+			"    'jfrog u' = 'jfrog upload'\n",
+			"        @{ Name = 'u'; Description = 'Upload files.' }\n",
+			"        @{ Name = '--dry-run'; Description = 'Don''t upload.' }\n",
+			"        @{ Name = '--server-id'; Description = '--server-id' }\n",
+			"    'jfrog repo-plugin create --type' = @('local', 'remote')\n",
+			"$script:jfrogServerIdFlags = @(\n    'jfrog upload --server-id'\n)\n",
+			"$candidates = & 'jfrog' hidden-completion-server-ids 2>$null",
+			"Register-ArgumentCompleter -Native -CommandName 'jfrog'",
+		}},
+	}
+	for _, test := range tests {
+		t.Run(string(test.shell), func(t *testing.T) {
+			script, err := Generate(test.shell, "jfrog", commands)
+			assert.NoError(t, err)
+			for _, expected := range test.expected {
+				assert.True(t, strings.Contains(script, expected), "Expected the script to include:\n%s\nScript:\n%s", expected, script)
+			}
+		})
+	}
+
+	// File completion is disabled only for commands with subcommands, so that file arguments can still be completed.
+	script, err := Generate(Fish, "jfrog", commands)
+	assert.NoError(t, err)
+	assert.NotContains(t, script, "complete -c 'jfrog' -f\n")
+	assert.NotContains(t, script, "complete -c 'jfrog' -n '__jfrog_path_is \\'jfrog upload\\'' -f\n")
+}
+
+func TestServerIdsCommand(t *testing.T) {
+	oldHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer os.Setenv(coreutils.HomeDir, oldHome)
+	defer tests.CleanUnitTestsJfrogHome()
+	assert.NoError(t, config.SaveServersConf([]*config.ServerDetails{{ServerId: "prod", IsDefault: true}, {ServerId: "dev"}}))
+
+	previousLog := log.Logger
+	defer log.SetLogger(previousLog)
+	newLog := log.NewLogger(log.INFO, nil)
+	buffer := &bytes.Buffer{}
+	newLog.SetOutputWriter(buffer)
+	log.SetLogger(newLog)
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{CreateServerIdsCommand()}
+	assert.NoError(t, app.Run([]string{"jfrog", ServerIdsCommandName}))
+	assert.Equal(t, "prod\ndev\n", buffer.String())
+}
+
+func TestGenerateUnsupportedShell(t *testing.T) {
+	_, err := Generate("tcsh", "jfrog", nil)
+	assert.EqualError(t, err, "unsupported shell 'tcsh', the supported shells are: zsh, fish, powershell")
+}
@@ -0,0 +1,83 @@
+package completion
+
+import (
+	"strings"
+)
+
+const fishFunctions = `# Print the path of the command typed so far, skipping flags and arguments.
+function __{{name}}_path
+    set -l path_key {{program}}
+    for token in (commandline -opc)[2..-1]
+        string match -q -- '-*' $token; and continue
+        set -l next (__{{name}}_resolve "$path_key $token"); and set path_key $next
+    end
+    echo $path_key
+end
+
+function __{{name}}_path_is
+    test (__{{name}}_path) = "$argv[1]"
+end
+
+`
+
+func createFishScript(root *Command) string {
+	name := functionName(root.Name)
+	program := fishQuote(root.Name)
+	pathCommands := walkCommands(root)
+
+	builder := new(strings.Builder)
+	builder.WriteString("# fish completion for " + root.Name + ".\n")
+	builder.WriteString("# Load it by placing it as " + root.Name + ".fish in ~/.config/fish/completions, or by sourcing it.\n\n")
+	// Resolve a command name or alias, following the path of its parent, to the command's path.
+	builder.WriteString("function __" + name + "_resolve\n    switch $argv[1]\n")
+	paths := createPathsMap(pathCommands)
+	for _, key := range sortedKeys(paths) {
+		builder.WriteString("        case " + fishQuote(key) + "\n            echo " + fishQuote(paths[key]) + "\n")
+	}
+	builder.WriteString("        case '*'\n            return 1\n    end\nend\n\n")
+	builder.WriteString(strings.NewReplacer("{{name}}", name, "{{program}}", program).Replace(fishFunctions))
+
+	for _, pathCommand := range pathCommands {
+		condition := "-n " + fishQuote("__"+name+"_path_is "+fishQuote(pathCommand.path))
+		// The arguments of a command with subcommands are not completed as files. Other commands may receive file paths.
+		if len(pathCommand.command.Subcommands) > 0 {
+			builder.WriteString("complete -c " + program + " " + condition + " -f\n")
+		}
+		for _, subcommand := range pathCommand.command.Subcommands {
+			for _, commandName := range append([]string{subcommand.Name}, subcommand.Aliases...) {
+				builder.WriteString("complete -c " + program + " " + condition + " -a " + fishQuote(commandName) + fishDescription(subcommand.Description) + "\n")
+			}
+		}
+		for _, flag := range pathCommand.command.Flags {
+			builder.WriteString("complete -c " + program + " " + condition)
+			for _, flagName := range flag.Names {
+				if len(flagName) == 1 {
+					builder.WriteString(" -s " + fishQuote(flagName))
+				} else {
+					builder.WriteString(" -l " + fishQuote(flagName))
+				}
+			}
+			switch {
+			case len(flag.Values) > 0:
+				builder.WriteString(" -x -a " + fishQuote(strings.Join(flag.Values, " ")))
+			case flag.IsServerId:
+				builder.WriteString(" -x -a " + fishQuote("("+program+" "+ServerIdsCommandName+" 2>/dev/null)"))
+			case !flag.IsBool:
+				builder.WriteString(" -r")
+			}
+			builder.WriteString(fishDescription(flag.Description) + "\n")
+		}
+	}
+	return builder.String()
+}
+
+func fishDescription(description string) string {
+	if description = singleLine(description); description == "" {
+		return ""
+	}
+	return " -d " + fishQuote(description)
+}
+
+func fishQuote(value string) string {
+	return "'" + strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(value) + "'"
+}
@@ -0,0 +1,110 @@
+package completion
+
+import (
+	"strings"
+)
+
+const powerShellCompleter = `
+Register-ArgumentCompleter -Native -CommandName {{program}} -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    # The words typed before the one being completed, without the program name.
+    $elements = @($commandAst.CommandElements | Select-Object -Skip 1 | Where-Object { $_.Extent.EndOffset -lt $cursorPosition } | ForEach-Object { $_.ToString() })
+    # Follow the commands typed so far, skipping flags and arguments.
+    $path = {{program}}
+    foreach ($element in $elements) {
+        if ($element -like '-*') { continue }
+        $key = "$path $element"
+        if ($script:{{name}}Paths.ContainsKey($key)) { $path = $script:{{name}}Paths[$key] }
+    }
+    $previous = if ($elements.Count -gt 0) { $elements[-1] } else { '' }
+    $candidates = @()
+    if ($previous -like '-*' -and $script:{{name}}ServerIdFlags -contains "$path $previous") {
+        $candidates = & {{program}} {{serverIdsCommand}} 2>$null | ForEach-Object { @{ Name = $_; Description = $_ } }
+        $resultType = 'ParameterValue'
+    } elseif ($previous -like '-*' -and $script:{{name}}FlagValues.ContainsKey("$path $previous")) {
+        $candidates = $script:{{name}}FlagValues["$path $previous"] | ForEach-Object { @{ Name = $_; Description = $_ } }
+        $resultType = 'ParameterValue'
+    } elseif ($wordToComplete -like '-*') {
+        $candidates = $script:{{name}}Flags[$path]
+        $resultType = 'ParameterName'
+    } else {
+        $candidates = $script:{{name}}Commands[$path]
+        $resultType = 'Command'
+    }
+    $candidates | Where-Object { $_ -and $_.Name -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, $resultType, $_.Description)
+    }
+}
+`
+
+func createPowerShellScript(root *Command) string {
+	name := functionName(root.Name)
+	pathCommands := walkCommands(root)
+
+	builder := new(strings.Builder)
+	builder.WriteString("# PowerShell completion for " + root.Name + ".\n")
+	builder.WriteString("# Load it by sourcing it from your PowerShell profile.\n\n")
+	builder.WriteString("$script:" + name + "Paths = @{\n")
+	paths := createPathsMap(pathCommands)
+	for _, key := range sortedKeys(paths) {
+		builder.WriteString("    " + powerShellQuote(key) + " = " + powerShellQuote(paths[key]) + "\n")
+	}
+	builder.WriteString("}\n\n$script:" + name + "Commands = @{\n")
+	for _, pathCommand := range pathCommands {
+		var items []string
+		for _, subcommand := range pathCommand.command.Subcommands {
+			for _, commandName := range append([]string{subcommand.Name}, subcommand.Aliases...) {
+				items = append(items, powerShellItem(commandName, subcommand.Description))
+			}
+		}
+		writePowerShellList(builder, pathCommand.path, items)
+	}
+	builder.WriteString("}\n\n$script:" + name + "Flags = @{\n")
+	var flagValues, serverIdFlags []string
+	for _, pathCommand := range pathCommands {
+		var items []string
+		for _, flag := range pathCommand.command.Flags {
+			for _, flagName := range dashedNames(flag) {
+				items = append(items, powerShellItem(flagName, flag.Description))
+				if len(flag.Values) > 0 {
+					var values []string
+					for _, value := range flag.Values {
+						values = append(values, powerShellQuote(value))
+					}
+					flagValues = append(flagValues, "    "+powerShellQuote(pathCommand.path+" "+flagName)+" = @("+strings.Join(values, ", ")+")\n")
+				}
+				if flag.IsServerId {
+					serverIdFlags = append(serverIdFlags, "    "+powerShellQuote(pathCommand.path+" "+flagName)+"\n")
+				}
+			}
+		}
+		writePowerShellList(builder, pathCommand.path, items)
+	}
+	builder.WriteString("}\n\n$script:" + name + "FlagValues = @{\n" + strings.Join(flagValues, "") + "}\n")
+	builder.WriteString("\n$script:" + name + "ServerIdFlags = @(\n" + strings.Join(serverIdFlags, "") + ")\n")
+	builder.WriteString(strings.NewReplacer("{{name}}", name, "{{program}}", powerShellQuote(root.Name), "{{serverIdsCommand}}", ServerIdsCommandName).Replace(powerShellCompleter))
+	return builder.String()
+}
+
+func writePowerShellList(builder *strings.Builder, key string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	builder.WriteString("    " + powerShellQuote(key) + " = @(\n")
+	for _, item := range items {
+		builder.WriteString("        " + item + "\n")
+	}
+	builder.WriteString("    )\n")
+}
+
+// The description is shown as a tooltip, which cannot be empty.
+func powerShellItem(name, description string) string {
+	if description = singleLine(description); description == "" {
+		description = name
+	}
+	return "@{ Name = " + powerShellQuote(name) + "; Description = " + powerShellQuote(description) + " }"
+}
+
+func powerShellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
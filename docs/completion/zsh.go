@@ -0,0 +1,121 @@
+package completion
+
+import (
+	"strings"
+)
+
+const zshFunction = `
+_{{name}}() {
+  local path_key="{{program}}" word flag i
+  local -a candidates
+  # Follow the commands typed so far, skipping flags and arguments.
+  for ((i = 2; i < CURRENT; i++)); do
+    word="${words[i]}"
+    [[ "$word" == -* ]] && continue
+    if (( ${+_{{name}}_paths[$path_key $word]} )); then
+      path_key="${_{{name}}_paths[$path_key $word]}"
+    fi
+  done
+  # Complete the value of the previous flag, or of a --flag=value word.
+  flag="${words[CURRENT-1]}"
+  if [[ "${words[CURRENT]}" == --*=* ]]; then
+    flag="${words[CURRENT]%%=*}"
+  fi
+  if [[ "$flag" == -* ]] && (( ${+_{{name}}_flag_values[$path_key $flag]} || ${+_{{name}}_server_id_flags[$path_key $flag]} )); then
+    if (( ${+_{{name}}_server_id_flags[$path_key $flag]} )); then
+      candidates=(${(f)"$({{program}} {{serverIdsCommand}} 2>/dev/null)"})
+    else
+      candidates=(${(f)_{{name}}_flag_values[$path_key $flag]})
+    fi
+    if [[ "${words[CURRENT]}" == --*=* ]]; then
+      compadd -P "$flag=" -a candidates
+    else
+      compadd -a candidates
+    fi
+    return
+  fi
+  if [[ "${words[CURRENT]}" == -* ]]; then
+    candidates=(${(f)_{{name}}_flags[$path_key]})
+    _describe 'option' candidates
+    return
+  fi
+  # A command without subcommands may receive file paths.
+  if (( ! ${+_{{name}}_commands[$path_key]} )); then
+    _files
+    return
+  fi
+  candidates=(${(f)_{{name}}_commands[$path_key]})
+  _describe 'command' candidates
+}
+
+compdef _{{name}} {{program}}
+`
+
+func createZshScript(root *Command) string {
+	name := functionName(root.Name)
+	pathCommands := walkCommands(root)
+	commands := make(map[string]string)
+	flags := make(map[string]string)
+	flagValues := make(map[string]string)
+	serverIdFlags := make(map[string]string)
+	for _, pathCommand := range pathCommands {
+		var commandLines []string
+		for _, subcommand := range pathCommand.command.Subcommands {
+			for _, commandName := range append([]string{subcommand.Name}, subcommand.Aliases...) {
+				commandLines = append(commandLines, zshDescribeItem(commandName, subcommand.Description))
+			}
+		}
+		if len(commandLines) > 0 {
+			commands[pathCommand.path] = strings.Join(commandLines, "\n")
+		}
+		var flagLines []string
+		for _, flag := range pathCommand.command.Flags {
+			for _, flagName := range dashedNames(flag) {
+				flagLines = append(flagLines, zshDescribeItem(flagName, flag.Description))
+				if len(flag.Values) > 0 {
+					flagValues[pathCommand.path+" "+flagName] = strings.Join(flag.Values, "\n")
+				}
+				if flag.IsServerId {
+					serverIdFlags[pathCommand.path+" "+flagName] = ""
+				}
+			}
+		}
+		if len(flagLines) > 0 {
+			flags[pathCommand.path] = strings.Join(flagLines, "\n")
+		}
+	}
+
+	builder := new(strings.Builder)
+	builder.WriteString("#compdef " + root.Name + "\n\n")
+	builder.WriteString("# zsh completion for " + root.Name + ".\n")
+	builder.WriteString("# Load it by placing it as _" + root.Name + " in a directory of your $fpath, or by sourcing it.\n\n")
+	writeZshMap(builder, "_"+name+"_paths", createPathsMap(pathCommands))
+	writeZshMap(builder, "_"+name+"_commands", commands)
+	writeZshMap(builder, "_"+name+"_flags", flags)
+	writeZshMap(builder, "_"+name+"_flag_values", flagValues)
+	writeZshMap(builder, "_"+name+"_server_id_flags", serverIdFlags)
+	builder.WriteString(strings.NewReplacer("{{name}}", name, "{{program}}", root.Name, "{{serverIdsCommand}}", ServerIdsCommandName).Replace(zshFunction))
+	return builder.String()
+}
+
+// An item for _describe, formatted as 'name:description'.
+func zshDescribeItem(name, description string) string {
+	name = strings.ReplaceAll(name, ":", "\\:")
+	if description = singleLine(description); description == "" {
+		return name
+	}
+	return name + ":" + description
+}
+
+func writeZshMap(builder *strings.Builder, variable string, values map[string]string) {
+	builder.WriteString("typeset -gA " + variable + "\n" + variable + "=(\n")
+	for _, key := range sortedKeys(values) {
+		builder.WriteString("  " + zshQuote(key) + " " + zshQuote(values[key]) + "\n")
+	}
+	builder.WriteString(")\n\n")
+}
+
+// Quote a string using the $'...' form, which supports escaped newlines.
+func zshQuote(value string) string {
+	return "$'" + strings.NewReplacer("\\", "\\\\", "'", "\\'", "\n", "\\n").Replace(value) + "'"
+}
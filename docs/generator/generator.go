@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// A page describes a single command.
+type page struct {
+	appName string
+	// The names of the command and its parent commands, starting from the top-level command.
+	path    []string
+	command components.CommandSignature
+}
+
+// The full command, as typed in the terminal.
+func (p *page) commandLine() string {
+	return "jfrog " + p.appName + " " + strings.Join(p.path, " ")
+}
+
+// The page name, without an extension. For example: my-plugin-repo-create.
+func (p *page) name() string {
+	return pageName(p.appName, p.path...)
+}
+
+func pageName(appName string, path ...string) string {
+	return strings.Join(append([]string{appName}, path...), "-")
+}
+
+func (p *page) usage() string {
+	return components.CreateCommandUsage(p.commandLine(), p.command)
+}
+
+// The marker shown before a flag's description, as in the commands' help.
+func flagMarker(flag components.FlagSignature) string {
+	switch {
+	case flag.Default != "":
+		return "[Default: " + flag.Default + "]"
+	case flag.Mandatory:
+		return "[Mandatory]"
+	}
+	return "[Optional]"
+}
+
+func flagDescription(flag components.FlagSignature) string {
+	description := flag.Description
+	if len(flag.AllowedValues) > 0 {
+		description = strings.TrimSpace(description + " Allowed values: " + strings.Join(flag.AllowedValues, ", ") + ".")
+	}
+	if flag.EnvVar != "" {
+		description = strings.TrimSpace(description + " Environment variable: " + flag.EnvVar + ".")
+	}
+	return description
+}
+
+// Return the pages of the app's commands, parents before their subcommands.
+func createPages(app components.App) []*page {
+	return appendPages(nil, app.Name, nil, components.CreateCommandSignatures(app))
+}
+
+func appendPages(pages []*page, appName string, parentPath []string, commands []components.CommandSignature) []*page {
+	for _, command := range commands {
+		path := append(append([]string{}, parentPath...), command.Name)
+		pages = append(pages, &page{appName: appName, path: path, command: command})
+		pages = appendPages(pages, appName, path, command.Subcommands)
+	}
+	return pages
+}
+
+// Generate a Markdown reference page for each of the app's commands, and an index page named after the app.
+func GenerateMarkdown(app components.App, outputDir string) error {
+	files := map[string]string{app.Name + ".md": createMarkdownIndex(app)}
+	for _, p := range createPages(app) {
+		files[p.name()+".md"] = createMarkdownPage(p)
+	}
+	return writeFiles(outputDir, files)
+}
+
+// Generate a man page (section 1) for each of the app's commands, and a man page for the app itself.
+func GenerateManPages(app components.App, outputDir string) error {
+	files := map[string]string{app.Name + ".1": createManIndex(app)}
+	for _, p := range createPages(app) {
+		files[p.name()+".1"] = createManPage(app, p)
+	}
+	return writeFiles(outputDir, files)
+}
+
+func writeFiles(outputDir string, files map[string]string) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	for fileName, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(outputDir, fileName), []byte(content), 0644); err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	log.Info("Generated", len(files), "documentation files in", outputDir)
+	return nil
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestApp() components.App {
+	return components.App{
+		Name:        "test-plugin",
+		Description: "A plugin for tests.",
+		Version:     "1.2.0",
+		Commands:    []components.Command{{Name: "hello", Description: "Say hello."}},
+		Namespaces: []components.Namespace{{
+			Name:        "repo",
+			Description: "Manage repositories.",
+			Commands: []components.Command{{
+				Name:        "create",
+				Description: "Create a repository.",
+				Aliases:     []string{"c"},
+				Arguments: []components.Argument{
+					{Name: "key", Description: "The repository key."},
+					{Name: "patterns", Description: "Include patterns.", Optional: true, Variadic: true},
+				},
+				Flags: []components.Flag{
+					components.StringFlag{Name: "server-id", Description: "Server ID."},
+					components.IntFlag{Name: "threads", Description: "Number of threads.", DefaultValue: 3, EnvVar: "REPO_THREADS"},
+					components.EnumFlag{Name: "type", Description: "Repository type.", AllowedValues: []string{"local", "remote"}, Mandatory: true},
+				},
+				EnvVars: []components.EnvVar{{Name: "REPO_DEBUG", Default: "false", Description: "Print | debug logs."}},
+			}},
+		}},
+	}
+}
+
+func getPage(app components.App, name string) *page {
+	for _, p := range createPages(app) {
+		if p.name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func TestCreateMarkdownPage(t *testing.T) {
+	app := createTestApp()
+	expected := "# jfrog test-plugin repo create\n\n" +
+		"Create a repository.\n\n" +
+		"## Usage\n\n```\njfrog test-plugin repo create [command options] <key> [patterns...]\n```\n\n" +
+		"Aliases: `c`\n\n" +
+		"## Arguments\n\n| Argument | Description |\n| --- | --- |\n" +
+		"| `<key>` | The repository key. |\n" +
+		"| `[patterns...]` | [Optional] Include patterns. |\n\n" +
+		"## Options\n\n| Flag | Type | Description |\n| --- | --- | --- |\n" +
+		"| `--server-id` | string | [Optional] Server ID. |\n" +
+		"| `--threads` | int | [Default: 3] Number of threads. Environment variable: REPO_THREADS. |\n" +
+		"| `--type` | enum | [Mandatory] Repository type. Allowed values: local, remote. |\n\n" +
+		"## Environment Variables\n\n| Variable | Default | Description |\n| --- | --- | --- |\n" +
+		"| `REPO_DEBUG` | false | Print \\| debug logs. |\n\n"
+	assert.Equal(t, expected, createMarkdownPage(getPage(app, "test-plugin-repo-create")))
+
+	expected = "# jfrog test-plugin repo\n\n" +
+		"Manage repositories.\n\n" +
+		"## Usage\n\n```\njfrog test-plugin repo <command>\n```\n\n" +
+		"## Commands\n\n| Command | Description |\n| --- | --- |\n" +
+		"| [create](test-plugin-repo-create.md) | Create a repository. |\n\n"
+	assert.Equal(t, expected, createMarkdownPage(getPage(app, "test-plugin-repo")))
+}
+
+func TestCreateManPage(t *testing.T) {
+	app := createTestApp()
+	expected := `.TH "TEST\-PLUGIN\-REPO\-CREATE" "1" "" "test\-plugin 1.2.0" "JFrog CLI Plugins"
+.SH NAME
+jfrog test\-plugin repo create \- Create a repository.
+.SH SYNOPSIS
+.B jfrog test\-plugin repo create
+[command options] <key> [patterns...]
+.SH ALIASES
+c
+.SH ARGUMENTS
+.TP
+.B <key>
+The repository key.
+.TP
+.B [patterns...]
+[Optional] Include patterns.
+.SH OPTIONS
+.TP
+.B \-\-server\-id (string)
+[Optional] Server ID.
+.TP
+.B \-\-threads (int)
+[Default: 3] Number of threads. Environment variable: REPO_THREADS.
+.TP
+.B \-\-type (enum)
+[Mandatory] Repository type. Allowed values: local, remote.
+.SH ENVIRONMENT
+.TP
+.B REPO_DEBUG
+[Default: false] Print | debug logs.
+.SH SEE ALSO
+.BR test\-plugin\-repo (1)
+`
+	assert.Equal(t, expected, createManPage(app, getPage(app, "test-plugin-repo-create")))
+	assert.Equal(t, "\\&.hidden \\e \\-", escapeRoff(".hidden \\ -"))
+}
+
+func TestGenerateDocs(t *testing.T) {
+	log.SetDefaultLogger()
+	app := createTestApp()
+	outputDir, err := ioutil.TempDir("", "docs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(outputDir)
+	assert.NoError(t, GenerateMarkdown(app, outputDir))
+	assert.NoError(t, GenerateManPages(app, outputDir))
+
+	files, err := ioutil.ReadDir(outputDir)
+	assert.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{
+		"test-plugin-hello.1", "test-plugin-hello.md",
+		"test-plugin-repo-create.1", "test-plugin-repo-create.md",
+		"test-plugin-repo.1", "test-plugin-repo.md",
+		"test-plugin.1", "test-plugin.md",
+	}, names)
+	index, err := ioutil.ReadFile(outputDir + "/test-plugin.md")
+	assert.NoError(t, err)
+	assert.Contains(t, string(index), "| [repo](test-plugin-repo.md) | Manage repositories. |\n")
+}
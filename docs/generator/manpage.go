@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+const manSection = "1"
+
+func createManIndex(app components.App) string {
+	builder := new(strings.Builder)
+	writeManHeader(builder, app, app.Name)
+	writeManSection(builder, "NAME")
+	builder.WriteString(escapeRoff("jfrog "+app.Name) + " \\- " + escapeRoff(app.Description) + "\n")
+	writeManSection(builder, "SYNOPSIS")
+	builder.WriteString(".B " + escapeRoff("jfrog "+app.Name) + "\n" + escapeRoff("<command> [command options] [arguments...]") + "\n")
+	writeManCommands(builder, app.Name, nil, components.CreateCommandSignatures(app))
+	return builder.String()
+}
+
+func createManPage(app components.App, p *page) string {
+	builder := new(strings.Builder)
+	writeManHeader(builder, app, p.name())
+	writeManSection(builder, "NAME")
+	builder.WriteString(escapeRoff(p.commandLine()) + " \\- " + escapeRoff(p.command.Description) + "\n")
+	writeManSection(builder, "SYNOPSIS")
+	usage := p.usage()
+	builder.WriteString(".B " + escapeRoff(p.commandLine()) + "\n")
+	if options := strings.TrimSpace(strings.TrimPrefix(usage, p.commandLine())); options != "" {
+		builder.WriteString(escapeRoff(options) + "\n")
+	}
+	if len(p.command.Aliases) > 0 {
+		writeManSection(builder, "ALIASES")
+		builder.WriteString(escapeRoff(strings.Join(p.command.Aliases, ", ")) + "\n")
+	}
+	writeManCommands(builder, p.appName, p.path, p.command.Subcommands)
+	if len(p.command.Arguments) > 0 {
+		writeManSection(builder, "ARGUMENTS")
+		for _, argument := range p.command.Arguments {
+			description := argument.Description
+			if argument.Optional {
+				description = "[Optional] " + description
+			}
+			writeManItem(builder, components.CreateArgumentUsage(argument), description)
+		}
+	}
+	if len(p.command.Flags) > 0 {
+		writeManSection(builder, "OPTIONS")
+		for _, flag := range p.command.Flags {
+			writeManItem(builder, "--"+flag.Name+" ("+flag.Type+")", flagMarker(flag)+" "+flagDescription(flag))
+		}
+	}
+	if len(p.command.EnvVars) > 0 {
+		writeManSection(builder, "ENVIRONMENT")
+		for _, envVar := range p.command.EnvVars {
+			description := envVar.Description
+			if envVar.Default != "" {
+				description = "[Default: " + envVar.Default + "] " + description
+			}
+			writeManItem(builder, envVar.Name, description)
+		}
+	}
+	writeManSection(builder, "SEE ALSO")
+	parent := app.Name
+	if len(p.path) > 1 {
+		parent = pageName(app.Name, p.path[:len(p.path)-1]...)
+	}
+	builder.WriteString(".BR " + escapeRoff(parent) + " (" + manSection + ")\n")
+	return builder.String()
+}
+
+func writeManHeader(builder *strings.Builder, app components.App, title string) {
+	source := app.Name
+	if app.Version != "" {
+		source += " " + app.Version
+	}
+	builder.WriteString(".TH \"" + escapeRoff(strings.ToUpper(title)) + "\" \"" + manSection + "\" \"\" \"" + escapeRoff(source) + "\" \"JFrog CLI Plugins\"\n")
+}
+
+func writeManSection(builder *strings.Builder, name string) {
+	builder.WriteString(".SH " + name + "\n")
+}
+
+func writeManItem(builder *strings.Builder, term, description string) {
+	builder.WriteString(".TP\n.B " + escapeRoff(term) + "\n" + escapeRoff(description) + "\n")
+}
+
+func writeManCommands(builder *strings.Builder, appName string, parentPath []string, commands []components.CommandSignature) {
+	if len(commands) == 0 {
+		return
+	}
+	writeManSection(builder, "COMMANDS")
+	for _, command := range commands {
+		description := command.Description
+		seeAlso := pageName(appName, append(append([]string{}, parentPath...), command.Name)...)
+		writeManItem(builder, command.Name, strings.TrimSpace(description+" See "+seeAlso+"("+manSection+")."))
+	}
+}
+
+// Escape text for roff: backslashes and dashes are escaped, and lines must not start with a control character.
+func escapeRoff(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\e")
+	text = strings.ReplaceAll(text, "-", "\\-")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = "\\&" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+)
+
+func createMarkdownIndex(app components.App) string {
+	builder := new(strings.Builder)
+	builder.WriteString("# jfrog " + app.Name + "\n\n")
+	if app.Description != "" {
+		builder.WriteString(app.Description + "\n\n")
+	}
+	if app.Version != "" {
+		builder.WriteString("Version: " + app.Version + "\n\n")
+	}
+	writeMarkdownCommands(builder, app.Name, nil, components.CreateCommandSignatures(app))
+	return builder.String()
+}
+
+func createMarkdownPage(p *page) string {
+	builder := new(strings.Builder)
+	builder.WriteString("# " + p.commandLine() + "\n\n")
+	if p.command.Description != "" {
+		builder.WriteString(p.command.Description + "\n\n")
+	}
+	builder.WriteString("## Usage\n\n```\n" + p.usage() + "\n```\n\n")
+	if len(p.command.Aliases) > 0 {
+		builder.WriteString("Aliases: `" + strings.Join(p.command.Aliases, "`, `") + "`\n\n")
+	}
+	writeMarkdownCommands(builder, p.appName, p.path, p.command.Subcommands)
+	if len(p.command.Arguments) > 0 {
+		builder.WriteString("## Arguments\n\n| Argument | Description |\n| --- | --- |\n")
+		for _, argument := range p.command.Arguments {
+			description := argument.Description
+			if argument.Optional {
+				description = "[Optional] " + description
+			}
+			writeMarkdownRow(builder, "`"+components.CreateArgumentUsage(argument)+"`", description)
+		}
+		builder.WriteString("\n")
+	}
+	if len(p.command.Flags) > 0 {
+		builder.WriteString("## Options\n\n| Flag | Type | Description |\n| --- | --- | --- |\n")
+		for _, flag := range p.command.Flags {
+			writeMarkdownRow(builder, "`--"+flag.Name+"`", flag.Type, flagMarker(flag)+" "+flagDescription(flag))
+		}
+		builder.WriteString("\n")
+	}
+	if len(p.command.EnvVars) > 0 {
+		builder.WriteString("## Environment Variables\n\n| Variable | Default | Description |\n| --- | --- | --- |\n")
+		for _, envVar := range p.command.EnvVars {
+			writeMarkdownRow(builder, "`"+envVar.Name+"`", envVar.Default, envVar.Description)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// Write a table of commands, each linking to its page.
+func writeMarkdownCommands(builder *strings.Builder, appName string, parentPath []string, commands []components.CommandSignature) {
+	if len(commands) == 0 {
+		return
+	}
+	builder.WriteString("## Commands\n\n| Command | Description |\n| --- | --- |\n")
+	for _, command := range commands {
+		link := "[" + command.Name + "](" + pageName(appName, append(append([]string{}, parentPath...), command.Name)...) + ".md)"
+		writeMarkdownRow(builder, link, command.Description)
+	}
+	builder.WriteString("\n")
+}
+
+func writeMarkdownRow(builder *strings.Builder, cells ...string) {
+	for i, cell := range cells {
+		cells[i] = strings.ReplaceAll(strings.ReplaceAll(cell, "|", "\\|"), "\n", " ")
+	}
+	builder.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+}
@@ -1,8 +1,16 @@
 package components
 
+import "time"
+
+// Arguments are required by default. The number of received arguments is validated before the command's action runs
+// if the command sets ValidateArguments, or declares an optional or a variadic argument.
 type Argument struct {
 	Name        string
 	Description string
+	// An optional argument may be omitted. Optional arguments must follow the required ones.
+	Optional bool
+	// A variadic argument receives all the remaining values. It must be the last argument.
+	Variadic bool
 }
 
 type EnvVar struct {
@@ -14,9 +22,27 @@ type EnvVar struct {
 type ActionFunc func(c *Context) error
 
 type Context struct {
-	Arguments   []string
-	stringFlags map[string]string
-	boolFlags   map[string]bool
+	Arguments        []string
+	argumentValues   map[string][]string
+	stringFlags      map[string]string
+	boolFlags        map[string]bool
+	intFlags         map[string]int
+	durationFlags    map[string]time.Duration
+	stringSliceFlags map[string][]string
+}
+
+// Return the value of a declared argument, or an empty string if an optional argument was omitted.
+// For a variadic argument, the first of its values is returned.
+func (c *Context) GetArgumentValue(argumentName string) string {
+	if values := c.argumentValues[argumentName]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Return the values of a declared variadic argument.
+func (c *Context) GetArgumentValues(argumentName string) []string {
+	return c.argumentValues[argumentName]
 }
 
 func (c *Context) GetStringFlagValue(flagName string) string {
@@ -27,6 +53,18 @@ func (c *Context) GetBoolFlagValue(flagName string) bool {
 	return c.boolFlags[flagName]
 }
 
+func (c *Context) GetIntFlagValue(flagName string) int {
+	return c.intFlags[flagName]
+}
+
+func (c *Context) GetDurationFlagValue(flagName string) time.Duration {
+	return c.durationFlags[flagName]
+}
+
+func (c *Context) GetStringSliceFlagValue(flagName string) []string {
+	return c.stringSliceFlags[flagName]
+}
+
 type Flag interface {
 	GetName() string
 	GetDescription() string
@@ -73,3 +111,95 @@ func (f BoolFlag) GetDescription() string {
 func (f BoolFlag) GetDefault() bool {
 	return f.DefaultValue
 }
+
+type IntFlag struct {
+	Name        string
+	Description string
+	// A flag with default value cannot be mandatory.
+	DefaultValue int
+	Mandatory    bool
+	// If set, the value is read from this environment variable when the flag is not passed.
+	EnvVar string
+}
+
+func (f IntFlag) GetName() string {
+	return f.Name
+}
+
+func (f IntFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f IntFlag) GetDefault() int {
+	return f.DefaultValue
+}
+
+// The value is a Go duration string, such as "90s" or "1h30m".
+type DurationFlag struct {
+	Name        string
+	Description string
+	// A flag with default value cannot be mandatory.
+	DefaultValue time.Duration
+	Mandatory    bool
+	// If set, the value is read from this environment variable when the flag is not passed.
+	EnvVar string
+}
+
+func (f DurationFlag) GetName() string {
+	return f.Name
+}
+
+func (f DurationFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f DurationFlag) GetDefault() time.Duration {
+	return f.DefaultValue
+}
+
+// The values are passed either comma-separated or by repeating the flag.
+type StringSliceFlag struct {
+	Name        string
+	Description string
+	// A flag with default value cannot be mandatory.
+	DefaultValue []string
+	Mandatory    bool
+	// If set, the comma-separated values are read from this environment variable when the flag is not passed.
+	EnvVar string
+}
+
+func (f StringSliceFlag) GetName() string {
+	return f.Name
+}
+
+func (f StringSliceFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f StringSliceFlag) GetDefault() []string {
+	return f.DefaultValue
+}
+
+// A string flag which accepts one of the allowed values only. The value is read using GetStringFlagValue.
+type EnumFlag struct {
+	Name          string
+	Description   string
+	AllowedValues []string
+	// A flag with default value cannot be mandatory. The default value must be one of the allowed values.
+	DefaultValue string
+	Mandatory    bool
+	// If set, the value is read from this environment variable when the flag is not passed.
+	EnvVar string
+}
+
+func (f EnumFlag) GetName() string {
+	return f.Name
+}
+
+func (f EnumFlag) GetDescription() string {
+	return f.Description
+}
+
+func (f EnumFlag) GetDefault() string {
+	return f.DefaultValue
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"github.com/codegangsta/cli"
 	"github.com/jfrog/jfrog-cli-core/v2/docs/common"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func ConvertApp(jfrogApp App) (*cli.App, error) {
@@ -24,9 +26,27 @@ func ConvertApp(jfrogApp App) (*cli.App, error) {
 }
 
 func convertCommands(jfrogApp App) ([]cli.Command, error) {
+	commands := jfrogApp.Commands
+	for _, namespace := range jfrogApp.Namespaces {
+		commands = append(commands, namespace.asCommand())
+	}
+	return convertSubcommands(commands, jfrogApp.Name)
+}
+
+// A namespace is converted as a command which has subcommands only.
+func (namespace Namespace) asCommand() Command {
+	return Command{
+		Name:        namespace.Name,
+		Description: namespace.Description,
+		Subcommands: namespace.Commands,
+	}
+}
+
+// Convert the commands under the given path, which is the plugin name followed by the names of the parent commands.
+func convertSubcommands(commands []Command, commandPath string) ([]cli.Command, error) {
 	var converted []cli.Command
-	for _, cmd := range jfrogApp.Commands {
-		cur, err := convertCommand(cmd, jfrogApp.Name)
+	for _, cmd := range commands {
+		cur, err := convertCommand(cmd, commandPath)
 		if err != nil {
 			return converted, err
 		}
@@ -35,35 +55,57 @@ func convertCommands(jfrogApp App) ([]cli.Command, error) {
 	return converted, nil
 }
 
-func convertCommand(cmd Command, appName string) (cli.Command, error) {
+func convertCommand(cmd Command, commandPath string) (cli.Command, error) {
+	if err := validateArgumentsDeclaration(cmd); err != nil {
+		return cli.Command{}, err
+	}
 	convertedFlags, err := convertFlags(cmd)
 	if err != nil {
 		return cli.Command{}, err
 	}
-	return cli.Command{
+	subcommands, err := convertSubcommands(cmd.Subcommands, commandPath+" "+cmd.Name)
+	if err != nil {
+		return cli.Command{}, err
+	}
+	var subcommandNames []string
+	for _, subcommand := range cmd.Subcommands {
+		subcommandNames = append(subcommandNames, subcommand.Name)
+	}
+	converted := cli.Command{
 		Name:            cmd.Name,
 		Flags:           convertedFlags,
 		Aliases:         cmd.Aliases,
 		Description:     cmd.Description,
-		HelpName:        common.CreateUsage(appName+" "+cmd.Name, cmd.Description, []string{createCommandUsage(cmd, appName)}),
+		HelpName:        common.CreateUsage(commandPath+" "+cmd.Name, cmd.Description, []string{createCommandUsage(cmd, commandPath)}),
 		UsageText:       createArgumentsSummary(cmd),
 		ArgsUsage:       createEnvVarsSummary(cmd),
-		BashComplete:    common.CreateBashCompletionFunc(),
+		BashComplete:    common.CreateBashCompletionFunc(subcommandNames...),
 		SkipFlagParsing: cmd.SkipFlagParsing,
+		Subcommands:     subcommands,
+	}
+	// A command with subcommands and no action of its own shows its help when no subcommand is given.
+	if cmd.Action != nil || len(cmd.Subcommands) == 0 {
 		// Passing any other interface than 'cli.ActionFunc' will fail the command.
-		Action: getActionFunc(cmd),
-	}, nil
+		converted.Action = getActionFunc(cmd)
+	}
+	return converted, nil
 }
 
-func createCommandUsage(cmd Command, appName string) string {
-	usage := fmt.Sprintf("jfrog %s %s", appName, cmd.Name)
-	if len(cmd.Flags) > 0 {
-		usage += " [command options]"
-	}
-	for _, argument := range cmd.Arguments {
-		usage += fmt.Sprintf(" <%s>", argument.Name)
+func createCommandUsage(cmd Command, commandPath string) string {
+	return CreateCommandUsage(fmt.Sprintf("jfrog %s %s", commandPath, cmd.Name), createCommandSignatures([]Command{cmd})[0])
+}
+
+// Optional arguments must follow the required ones, and only the last argument may be variadic.
+func validateArgumentsDeclaration(cmd Command) error {
+	for i, argument := range cmd.Arguments {
+		if argument.Variadic && i < len(cmd.Arguments)-1 {
+			return errors.New(fmt.Sprintf("Argument '%s' of command '%s' is variadic, but is not the last argument.", argument.Name, cmd.Name))
+		}
+		if !argument.Optional && i > 0 && cmd.Arguments[i-1].Optional {
+			return errors.New(fmt.Sprintf("Required argument '%s' of command '%s' follows an optional argument.", argument.Name, cmd.Name))
+		}
 	}
-	return usage
+	return nil
 }
 
 func createArgumentsSummary(cmd Command) string {
@@ -72,7 +114,11 @@ func createArgumentsSummary(cmd Command) string {
 		if i > 0 {
 			summary += "\n"
 		}
-		summary += "\t" + argument.Name + "\n\t\t" + argument.Description + "\n"
+		description := argument.Description
+		if argument.Optional {
+			description = "[Optional] " + description
+		}
+		summary += "\t" + argument.Name + "\n\t\t" + description + "\n"
 	}
 	return summary
 }
@@ -109,32 +155,89 @@ func convertFlags(cmd Command) ([]cli.Flag, error) {
 }
 
 func convertByType(flag Flag) (cli.Flag, error) {
-	if f, ok := flag.(StringFlag); ok {
+	switch f := flag.(type) {
+	case StringFlag:
 		return convertStringFlag(f), nil
-	}
-	if f, ok := flag.(BoolFlag); ok {
+	case BoolFlag:
 		return convertBoolFlag(f), nil
+	case IntFlag:
+		return convertIntFlag(f), nil
+	case DurationFlag:
+		return convertDurationFlag(f), nil
+	case StringSliceFlag:
+		return convertStringSliceFlag(f), nil
+	case EnumFlag:
+		return convertEnumFlag(f)
 	}
 	return nil, errors.New(fmt.Sprintf("Flag '%s' does not match any known flag type.", flag.GetName()))
 }
 
 func convertStringFlag(f StringFlag) cli.Flag {
-	stringFlag := cli.StringFlag{
+	return cli.StringFlag{
 		Name:  f.Name,
-		Usage: f.Description + "` `",
+		Usage: createFlagUsage(f.Description, f.DefaultValue, f.Mandatory),
+	}
+}
+
+// Typed flags are received as strings, and parsed when filling the context, so that invalid values fail with the flag's name.
+func convertIntFlag(f IntFlag) cli.Flag {
+	defaultValue := ""
+	if f.DefaultValue != 0 {
+		defaultValue = strconv.Itoa(f.DefaultValue)
+	}
+	return cli.StringFlag{
+		Name:   f.Name,
+		Usage:  createFlagUsage(f.Description, defaultValue, f.Mandatory),
+		EnvVar: f.EnvVar,
+	}
+}
+
+func convertDurationFlag(f DurationFlag) cli.Flag {
+	defaultValue := ""
+	if f.DefaultValue != 0 {
+		defaultValue = f.DefaultValue.String()
+	}
+	return cli.StringFlag{
+		Name:   f.Name,
+		Usage:  createFlagUsage(f.Description, defaultValue, f.Mandatory),
+		EnvVar: f.EnvVar,
+	}
+}
+
+func convertStringSliceFlag(f StringSliceFlag) cli.Flag {
+	return cli.StringSliceFlag{
+		Name:   f.Name,
+		Usage:  createFlagUsage(f.Description, strings.Join(f.DefaultValue, ","), f.Mandatory),
+		EnvVar: f.EnvVar,
 	}
+}
+
+func convertEnumFlag(f EnumFlag) (cli.Flag, error) {
+	if len(f.AllowedValues) == 0 {
+		return nil, errors.New(fmt.Sprintf("Flag '%s' has no allowed values.", f.Name))
+	}
+	if f.DefaultValue != "" && !isAllowedValue(f, f.DefaultValue) {
+		return nil, errors.New(fmt.Sprintf("The default value of flag '%s' is not one of its allowed values.", f.Name))
+	}
+	description := fmt.Sprintf("%s Allowed values: %s.", f.Description, strings.Join(f.AllowedValues, ", "))
+	return cli.StringFlag{
+		Name:   f.Name,
+		Usage:  createFlagUsage(description, f.DefaultValue, f.Mandatory),
+		EnvVar: f.EnvVar,
+	}, nil
+}
+
+func createFlagUsage(description, defaultValue string, mandatory bool) string {
+	usage := description + "` `"
 	// If default is set, add its value and return.
-	if f.DefaultValue != "" {
-		stringFlag.Usage = fmt.Sprintf("[Default: %s] %s", f.DefaultValue, stringFlag.Usage)
-		return stringFlag
+	if defaultValue != "" {
+		return fmt.Sprintf("[Default: %s] %s", defaultValue, usage)
 	}
 	// Otherwise, mark as mandatory/optional accordingly.
-	if f.Mandatory {
-		stringFlag.Usage = "[Mandatory] " + stringFlag.Usage
-	} else {
-		stringFlag.Usage = "[Optional] " + stringFlag.Usage
+	if mandatory {
+		return "[Mandatory] " + usage
 	}
-	return stringFlag
+	return "[Optional] " + usage
 }
 
 func convertBoolFlag(f BoolFlag) cli.Flag {
@@ -155,6 +258,13 @@ func getActionFunc(cmd Command) cli.ActionFunc {
 	return func(baseContext *cli.Context) error {
 		pluginContext := &Context{}
 		pluginContext.Arguments = baseContext.Args()
+		// When flag parsing is skipped, the flags are received as arguments, so they cannot be counted.
+		if !cmd.SkipFlagParsing {
+			if err := fillArgumentValues(pluginContext, cmd.Arguments, cmd.shouldValidateArguments()); err != nil {
+				showCommandHelp(baseContext, cmd)
+				return err
+			}
+		}
 		err := fillFlagMaps(pluginContext, baseContext, cmd.Flags)
 		if err != nil {
 			return err
@@ -163,23 +273,79 @@ func getActionFunc(cmd Command) cli.ActionFunc {
 	}
 }
 
+// Map the received arguments to the declared arguments, validating their number if validate is true.
+// Commands which declare no arguments accept any arguments.
+func fillArgumentValues(c *Context, arguments []Argument, validate bool) error {
+	c.argumentValues = make(map[string][]string)
+	if len(arguments) == 0 {
+		return nil
+	}
+	minimum, variadic := 0, false
+	for _, argument := range arguments {
+		if !argument.Optional {
+			minimum++
+		}
+		variadic = variadic || argument.Variadic
+	}
+	received := len(c.Arguments)
+	if validate && (received < minimum || (!variadic && received > len(arguments))) {
+		expected := strconv.Itoa(minimum)
+		switch {
+		case variadic:
+			expected = "at least " + expected
+		case minimum < len(arguments):
+			expected += " to " + strconv.Itoa(len(arguments))
+		}
+		return errors.New(fmt.Sprintf("Wrong number of arguments: expected %s, but received %d.", expected, received))
+	}
+	for i, argument := range arguments {
+		if i >= received {
+			break
+		}
+		if argument.Variadic {
+			c.argumentValues[argument.Name] = c.Arguments[i:]
+		} else {
+			c.argumentValues[argument.Name] = c.Arguments[i : i+1]
+		}
+	}
+	return nil
+}
+
+// The help of a command with subcommands is shown by the app created for its subcommands.
+func showCommandHelp(baseContext *cli.Context, cmd Command) {
+	helpTopic := cmd.Name
+	if len(cmd.Subcommands) > 0 {
+		helpTopic = ""
+	}
+	_ = cli.ShowCommandHelp(baseContext, helpTopic)
+}
+
 func fillFlagMaps(c *Context, baseContext *cli.Context, originalFlags []Flag) error {
 	c.stringFlags = make(map[string]string)
 	c.boolFlags = make(map[string]bool)
+	c.intFlags = make(map[string]int)
+	c.durationFlags = make(map[string]time.Duration)
+	c.stringSliceFlags = make(map[string][]string)
 
 	// Loop over all plugin's known flags.
 	for _, flag := range originalFlags {
-		if stringFlag, ok := flag.(StringFlag); ok {
-			finalValue, err := getValueForStringFlag(stringFlag, baseContext.String(stringFlag.Name))
-			if err != nil {
-				return err
-			}
-			c.stringFlags[stringFlag.Name] = finalValue
-			continue
+		var err error
+		switch f := flag.(type) {
+		case StringFlag:
+			c.stringFlags[f.Name], err = getValueForStringFlag(f, baseContext.String(f.Name))
+		case BoolFlag:
+			c.boolFlags[f.Name] = getValueForBoolFlag(f, baseContext)
+		case IntFlag:
+			c.intFlags[f.Name], err = getValueForIntFlag(f, baseContext.String(f.Name))
+		case DurationFlag:
+			c.durationFlags[f.Name], err = getValueForDurationFlag(f, baseContext.String(f.Name))
+		case StringSliceFlag:
+			c.stringSliceFlags[f.Name], err = getValueForStringSliceFlag(f, baseContext.StringSlice(f.Name))
+		case EnumFlag:
+			c.stringFlags[f.Name], err = getValueForEnumFlag(f, baseContext.String(f.Name))
 		}
-
-		if boolFlag, ok := flag.(BoolFlag); ok {
-			c.boolFlags[boolFlag.Name] = getValueForBoolFlag(boolFlag, baseContext)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -206,3 +372,69 @@ func getValueForBoolFlag(f BoolFlag, baseContext *cli.Context) bool {
 	}
 	return baseContext.Bool(f.Name)
 }
+
+func getValueForIntFlag(f IntFlag, receivedValue string) (int, error) {
+	if receivedValue != "" {
+		value, err := strconv.Atoi(receivedValue)
+		if err != nil {
+			return 0, errors.New("Flag '" + f.Name + "' expects an integer, but received '" + receivedValue + "'")
+		}
+		return value, nil
+	}
+	if f.DefaultValue == 0 && f.Mandatory {
+		return 0, errors.New("Mandatory flag '" + f.Name + "' is missing")
+	}
+	return f.DefaultValue, nil
+}
+
+func getValueForDurationFlag(f DurationFlag, receivedValue string) (time.Duration, error) {
+	if receivedValue != "" {
+		value, err := time.ParseDuration(receivedValue)
+		if err != nil {
+			return 0, errors.New("Flag '" + f.Name + "' expects a duration such as '90s' or '1h30m', but received '" + receivedValue + "'")
+		}
+		return value, nil
+	}
+	if f.DefaultValue == 0 && f.Mandatory {
+		return 0, errors.New("Mandatory flag '" + f.Name + "' is missing")
+	}
+	return f.DefaultValue, nil
+}
+
+// Each received value may hold several comma-separated values.
+func getValueForStringSliceFlag(f StringSliceFlag, receivedValues []string) ([]string, error) {
+	var values []string
+	for _, receivedValue := range receivedValues {
+		for _, value := range strings.Split(receivedValue, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	if len(values) > 0 {
+		return values, nil
+	}
+	if len(f.DefaultValue) == 0 && f.Mandatory {
+		return nil, errors.New("Mandatory flag '" + f.Name + "' is missing")
+	}
+	return f.DefaultValue, nil
+}
+
+func getValueForEnumFlag(f EnumFlag, receivedValue string) (string, error) {
+	if receivedValue == "" {
+		return getValueForStringFlag(StringFlag{Name: f.Name, DefaultValue: f.DefaultValue, Mandatory: f.Mandatory}, receivedValue)
+	}
+	if !isAllowedValue(f, receivedValue) {
+		return "", errors.New("Flag '" + f.Name + "' received '" + receivedValue + "', but expects one of: " + strings.Join(f.AllowedValues, ", "))
+	}
+	return receivedValue, nil
+}
+
+func isAllowedValue(f EnumFlag, value string) bool {
+	for _, allowedValue := range f.AllowedValues {
+		if value == allowedValue {
+			return true
+		}
+	}
+	return false
+}
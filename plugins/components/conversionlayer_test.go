@@ -1,9 +1,12 @@
 package components
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"os"
 	"testing"
+	"time"
 )
 
 func TestCreateCommandUsage(t *testing.T) {
@@ -213,3 +216,195 @@ func TestGetValueForStringFlag(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, finalValue, expected)
 }
+
+func TestConvertNestedCommands(t *testing.T) {
+	var executed []string
+	action := func(name string) ActionFunc {
+		return func(c *Context) error {
+			executed = append(executed, fmt.Sprintf("%s %v %s", name, c.Arguments, c.GetStringFlagValue("name")))
+			return nil
+		}
+	}
+	app := App{
+		Name:     "test-app",
+		Commands: []Command{{Name: "flat", Action: action("flat")}},
+		Namespaces: []Namespace{{
+			Name: "repo",
+			Commands: []Command{
+				{Name: "create", Aliases: []string{"c"}, Flags: []Flag{StringFlag{Name: "name"}}, Action: action("repo create")},
+				{Name: "audit", Action: action("repo audit"), Subcommands: []Command{{Name: "report", Action: action("repo audit report")}}},
+			},
+		}},
+	}
+	baseApp, err := ConvertApp(app)
+	assert.NoError(t, err)
+	for _, args := range [][]string{
+		{"flat", "arg"},
+		{"repo", "create", "--name=libs", "arg"},
+		{"repo", "c", "arg"},
+		{"repo", "audit"},
+		{"repo", "audit", "report", "arg"},
+	} {
+		assert.NoError(t, baseApp.Run(append([]string{"test-app"}, args...)))
+	}
+	assert.Equal(t, []string{
+		"flat [arg] ",
+		"repo create [arg] libs",
+		"repo create [arg] ",
+		"repo audit [] ",
+		"repo audit report [arg] ",
+	}, executed)
+
+	// Namespaces have no action of their own.
+	namespace := baseApp.Commands[1]
+	assert.Nil(t, namespace.Action)
+	assert.Len(t, namespace.Subcommands, 2)
+	assert.Equal(t, "jfrog test-app repo <command>", createCommandUsage(app.Namespaces[0].asCommand(), app.Name))
+	assert.Equal(t, "jfrog test-app repo create [command options]", createCommandUsage(app.Namespaces[0].Commands[0], "test-app repo"))
+	assert.Contains(t, namespace.Subcommands[0].HelpName, "jfrog test-app repo create [command options]")
+}
+
+func TestTypedFlags(t *testing.T) {
+	var received *Context
+	cmd := Command{
+		Name: "typed",
+		Flags: []Flag{
+			IntFlag{Name: "threads", DefaultValue: 3, EnvVar: "TEST_PLUGIN_THREADS"},
+			IntFlag{Name: "retries", Mandatory: true},
+			DurationFlag{Name: "timeout", DefaultValue: time.Minute},
+			StringSliceFlag{Name: "repos", DefaultValue: []string{"default-repo"}},
+			EnumFlag{Name: "format", AllowedValues: []string{"json", "table"}, DefaultValue: "table"},
+		},
+		Action: func(c *Context) error {
+			received = c
+			return nil
+		},
+	}
+	baseApp, err := ConvertApp(App{Name: "test-app", Commands: []Command{cmd}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, baseApp.Run([]string{"test-app", "typed", "--retries=2"}))
+	assert.Equal(t, 3, received.GetIntFlagValue("threads"))
+	assert.Equal(t, 2, received.GetIntFlagValue("retries"))
+	assert.Equal(t, time.Minute, received.GetDurationFlagValue("timeout"))
+	assert.Equal(t, []string{"default-repo"}, received.GetStringSliceFlagValue("repos"))
+	assert.Equal(t, "table", received.GetStringFlagValue("format"))
+
+	assert.NoError(t, os.Setenv("TEST_PLUGIN_THREADS", "8"))
+	defer os.Unsetenv("TEST_PLUGIN_THREADS")
+	assert.NoError(t, baseApp.Run([]string{"test-app", "typed", "--retries=0", "--timeout=1h30m", "--repos=a,b", "--repos=c", "--format=json"}))
+	assert.Equal(t, 8, received.GetIntFlagValue("threads"))
+	assert.Equal(t, 90*time.Minute, received.GetDurationFlagValue("timeout"))
+	assert.Equal(t, []string{"a", "b", "c"}, received.GetStringSliceFlagValue("repos"))
+	assert.Equal(t, "json", received.GetStringFlagValue("format"))
+
+	// Invalid values fail before the action runs.
+	received = nil
+	for _, args := range [][]string{
+		{"--retries=two"},
+		{},
+		{"--retries=1", "--timeout=soon"},
+		{"--retries=1", "--format=xml"},
+	} {
+		assert.Error(t, baseApp.Run(append([]string{"test-app", "typed"}, args...)), args)
+	}
+	assert.Nil(t, received)
+}
+
+func TestConvertTypedFlags(t *testing.T) {
+	converted, err := convertByType(IntFlag{Name: "threads", Description: "Number of threads.", DefaultValue: 3, EnvVar: "THREADS"})
+	assert.NoError(t, err)
+	assert.Equal(t, "--threads  \t[Default: 3] Number of threads. [$THREADS]", converted.String())
+
+	converted, err = convertByType(EnumFlag{Name: "format", Description: "Output format.", AllowedValues: []string{"json", "table"}, Mandatory: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "--format  \t[Mandatory] Output format. Allowed values: json, table.", converted.String())
+
+	_, err = convertByType(EnumFlag{Name: "format", AllowedValues: []string{"json"}, DefaultValue: "xml"})
+	assert.Error(t, err)
+}
+
+func TestArgumentsValidation(t *testing.T) {
+	var received *Context
+	cmd := Command{
+		Name: "copy",
+		Arguments: []Argument{
+			{Name: "source"},
+			{Name: "target"},
+			{Name: "patterns", Optional: true, Variadic: true},
+		},
+		ValidateArguments: true,
+		Action: func(c *Context) error {
+			received = c
+			return nil
+		},
+	}
+	assert.Equal(t, "jfrog test-app copy <source> <target> [patterns...]", createCommandUsage(cmd, "test-app"))
+	baseApp, err := ConvertApp(App{Name: "test-app", Commands: []Command{cmd}})
+	assert.NoError(t, err)
+	output := new(bytes.Buffer)
+	baseApp.Writer = output
+
+	assert.NoError(t, baseApp.Run([]string{"test-app", "copy", "a", "b"}))
+	assert.Equal(t, "a", received.GetArgumentValue("source"))
+	assert.Equal(t, "b", received.GetArgumentValue("target"))
+	assert.Empty(t, received.GetArgumentValues("patterns"))
+
+	assert.NoError(t, baseApp.Run([]string{"test-app", "copy", "a", "b", "*.zip", "*.jar"}))
+	assert.Equal(t, []string{"*.zip", "*.jar"}, received.GetArgumentValues("patterns"))
+	assert.Empty(t, output.String())
+
+	// Missing arguments fail before the action runs, and the command's usage is shown.
+	received = nil
+	err = baseApp.Run([]string{"test-app", "copy", "a"})
+	assert.EqualError(t, err, "Wrong number of arguments: expected at least 2, but received 1.")
+	assert.Nil(t, received)
+	assert.Contains(t, output.String(), "jfrog test-app copy <source> <target> [patterns...]")
+
+	// Declaring an optional or a variadic argument validates the arguments, even without setting ValidateArguments.
+	cmd.ValidateArguments = false
+	baseApp, err = ConvertApp(App{Name: "test-app", Commands: []Command{cmd}})
+	assert.NoError(t, err)
+	baseApp.Writer = output
+	received = nil
+	assert.Error(t, baseApp.Run([]string{"test-app", "copy", "a"}))
+	assert.Nil(t, received)
+
+	// Without validation, the action receives any number of arguments, and checks them itself.
+	cmd.Arguments = cmd.Arguments[:2]
+	baseApp, err = ConvertApp(App{Name: "test-app", Commands: []Command{cmd}})
+	assert.NoError(t, err)
+	assert.NoError(t, baseApp.Run([]string{"test-app", "copy", "a"}))
+	assert.Equal(t, "a", received.GetArgumentValue("source"))
+	assert.Empty(t, received.GetArgumentValue("target"))
+}
+
+func TestFillArgumentValues(t *testing.T) {
+	arguments := []Argument{{Name: "first"}, {Name: "second", Optional: true}}
+	for _, test := range []struct {
+		received      []string
+		expectedError string
+	}{
+		{[]string{"1"}, ""},
+		{[]string{"1", "2"}, ""},
+		{[]string{}, "Wrong number of arguments: expected 1 to 2, but received 0."},
+		{[]string{"1", "2", "3"}, "Wrong number of arguments: expected 1 to 2, but received 3."},
+	} {
+		err := fillArgumentValues(&Context{Arguments: test.received}, arguments, true)
+		if test.expectedError == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, test.expectedError)
+		}
+		// Without validation, any number of arguments is accepted.
+		assert.NoError(t, fillArgumentValues(&Context{Arguments: test.received}, arguments, false))
+	}
+	// Commands which declare no arguments accept any arguments.
+	assert.NoError(t, fillArgumentValues(&Context{Arguments: []string{"1", "2"}}, nil, true))
+}
+
+func TestValidateArgumentsDeclaration(t *testing.T) {
+	assert.NoError(t, validateArgumentsDeclaration(Command{Arguments: []Argument{{Name: "a"}, {Name: "b", Optional: true}, {Name: "c", Optional: true, Variadic: true}}}))
+	assert.Error(t, validateArgumentsDeclaration(Command{Arguments: []Argument{{Name: "a", Optional: true}, {Name: "b"}}}))
+	assert.Error(t, validateArgumentsDeclaration(Command{Arguments: []Argument{{Name: "a", Variadic: true}, {Name: "b"}}}))
+}
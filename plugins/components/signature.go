@@ -0,0 +1,92 @@
+package components
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Describe the commands of the app, including its namespaces, for the plugin's signature.
+func CreateCommandSignatures(app App) []CommandSignature {
+	commands := app.Commands
+	for _, namespace := range app.Namespaces {
+		commands = append(commands, namespace.asCommand())
+	}
+	return createCommandSignatures(commands)
+}
+
+func createCommandSignatures(commands []Command) []CommandSignature {
+	var signatures []CommandSignature
+	for _, cmd := range commands {
+		signature := CommandSignature{
+			Name:        cmd.Name,
+			Description: cmd.Description,
+			Aliases:     cmd.Aliases,
+			Subcommands: createCommandSignatures(cmd.Subcommands),
+		}
+		for _, argument := range cmd.Arguments {
+			signature.Arguments = append(signature.Arguments, ArgumentSignature(argument))
+		}
+		for _, flag := range cmd.Flags {
+			signature.Flags = append(signature.Flags, createFlagSignature(flag))
+		}
+		for _, envVar := range cmd.EnvVars {
+			signature.EnvVars = append(signature.EnvVars, EnvVarSignature(envVar))
+		}
+		signatures = append(signatures, signature)
+	}
+	return signatures
+}
+
+func createFlagSignature(flag Flag) FlagSignature {
+	signature := FlagSignature{Name: flag.GetName(), Description: flag.GetDescription()}
+	switch f := flag.(type) {
+	case StringFlag:
+		signature.Type, signature.Default, signature.Mandatory = "string", f.DefaultValue, f.Mandatory
+	case BoolFlag:
+		signature.Type, signature.Default = "bool", strconv.FormatBool(f.DefaultValue)
+	case IntFlag:
+		signature.Type, signature.Mandatory, signature.EnvVar = "int", f.Mandatory, f.EnvVar
+		if f.DefaultValue != 0 {
+			signature.Default = strconv.Itoa(f.DefaultValue)
+		}
+	case DurationFlag:
+		signature.Type, signature.Mandatory, signature.EnvVar = "duration", f.Mandatory, f.EnvVar
+		if f.DefaultValue != 0 {
+			signature.Default = f.DefaultValue.String()
+		}
+	case StringSliceFlag:
+		signature.Type, signature.Default, signature.Mandatory, signature.EnvVar = "string-slice", strings.Join(f.DefaultValue, ","), f.Mandatory, f.EnvVar
+	case EnumFlag:
+		signature.Type, signature.Default, signature.Mandatory, signature.EnvVar = "enum", f.DefaultValue, f.Mandatory, f.EnvVar
+		signature.AllowedValues = f.AllowedValues
+	}
+	return signature
+}
+
+// Create the usage line of a command, as shown in its help and in its generated docs.
+// For example: jfrog my-plugin copy [command options] <source> [patterns...]
+func CreateCommandUsage(commandLine string, cmd CommandSignature) string {
+	usage := commandLine
+	if len(cmd.Subcommands) > 0 {
+		usage += " <command>"
+	}
+	if len(cmd.Flags) > 0 {
+		usage += " [command options]"
+	}
+	for _, argument := range cmd.Arguments {
+		usage += " " + CreateArgumentUsage(argument)
+	}
+	return usage
+}
+
+// Create the usage of an argument: <name> for a required argument, [name] for an optional one, with '...' if it is variadic.
+func CreateArgumentUsage(argument ArgumentSignature) string {
+	name := argument.Name
+	if argument.Variadic {
+		name += "..."
+	}
+	if argument.Optional {
+		return "[" + name + "]"
+	}
+	return "<" + name + ">"
+}
@@ -4,6 +4,17 @@ type App struct {
 	Name        string
 	Description string
 	Version     string
+	// The minimum version of jfrog-cli-core the CLI running the plugin should have, if any.
+	MinimumCoreVersion string
+	Commands           []Command
+	Namespaces         []Namespace
+}
+
+// A group of commands, invoked as 'jfrog <plugin> <namespace> <command>'.
+// A namespace has no action of its own - running it without a command shows its help.
+type Namespace struct {
+	Name        string
+	Description string
 	Commands    []Command
 }
 
@@ -16,11 +27,74 @@ type Command struct {
 	EnvVars         []EnvVar
 	Action          ActionFunc
 	SkipFlagParsing bool
+	// Validate the number of received arguments against the declared arguments, before the command's action runs.
+	// Commands which declare optional or variadic arguments are always validated. Otherwise, validation is disabled by default,
+	// so that commands which check their arguments themselves keep receiving any number of them.
+	ValidateArguments bool
+	// Commands nested under this command, invoked as 'jfrog <plugin> <command> <subcommand>'.
+	// The command's own action, if set, runs when no subcommand is given.
+	Subcommands []Command
+}
+
+// Whether the number of received arguments is validated before the command's action runs.
+// Declaring an optional or a variadic argument opts the command in, since such a declaration is only meaningful if it is enforced.
+func (cmd Command) shouldValidateArguments() bool {
+	if cmd.ValidateArguments {
+		return true
+	}
+	for _, argument := range cmd.Arguments {
+		if argument.Optional || argument.Variadic {
+			return true
+		}
+	}
+	return false
 }
 
 type PluginSignature struct {
-	Name  string `json:"name,omitempty"`
-	Usage string `json:"usage,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Usage   string `json:"usage,omitempty"`
+	Version string `json:"version,omitempty"`
+	// The version of jfrog-cli-core the plugin was built with.
+	CoreVersion string `json:"coreVersion,omitempty"`
+	// The minimum version of jfrog-cli-core the CLI running the plugin should have.
+	MinimumCoreVersion string             `json:"minimumCoreVersion,omitempty"`
+	Commands           []CommandSignature `json:"commands,omitempty"`
+	// The SHA-256 checksum of the plugin's executable. Computed by the CLI from the executable, rather than reported by the plugin.
+	Checksum string `json:"checksum,omitempty"`
 	// Only used internally in the CLI.
 	ExecutablePath string `json:"executablePath,omitempty"`
 }
+
+type CommandSignature struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Aliases     []string            `json:"aliases,omitempty"`
+	Arguments   []ArgumentSignature `json:"arguments,omitempty"`
+	Flags       []FlagSignature     `json:"flags,omitempty"`
+	EnvVars     []EnvVarSignature   `json:"envVars,omitempty"`
+	Subcommands []CommandSignature  `json:"subcommands,omitempty"`
+}
+
+type ArgumentSignature struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Optional    bool   `json:"optional,omitempty"`
+	Variadic    bool   `json:"variadic,omitempty"`
+}
+
+type EnvVarSignature struct {
+	Name        string `json:"name"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type FlagSignature struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// One of: string, bool, int, duration, string-slice or enum.
+	Type          string   `json:"type"`
+	Default       string   `json:"default,omitempty"`
+	Mandatory     bool     `json:"mandatory,omitempty"`
+	EnvVar        string   `json:"envVar,omitempty"`
+	AllowedValues []string `json:"allowedValues,omitempty"`
+}
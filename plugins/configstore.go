@@ -0,0 +1,250 @@
+package plugins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/lock"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	pluginConfigDirName  = "config"
+	pluginConfigFileName = "config.yaml"
+	pluginLocksDirName   = "locks"
+	pluginConfigVersion  = "1"
+)
+
+// The persistent configuration of a plugin, stored in its own directory under the JFrog plugins directory:
+// <JFrog home>/plugins/<plugin name>/config/config.yaml
+//
+// Values are converted to and from JSON, so structs are stored according to their json tags.
+// Secrets are stored separately, and are encrypted when a master key is configured, like the secrets of the CLI's configuration.
+// Changes are made while holding a lock on the plugin's configuration, so that concurrent runs of the plugin don't override each other.
+type ConfigStore struct {
+	pluginName string
+	dir        string
+}
+
+type pluginConfig struct {
+	Version string                 `yaml:"version"`
+	Values  map[string]interface{} `yaml:"values,omitempty"`
+	Secrets map[string]string      `yaml:"secrets,omitempty"`
+	// True if the secrets are encrypted.
+	Enc bool `yaml:"enc,omitempty"`
+}
+
+func NewConfigStore(pluginName string) (*ConfigStore, error) {
+	if pluginName == "" || strings.ContainsAny(pluginName, `/\`) || pluginName == "." || pluginName == ".." {
+		return nil, errorutils.CheckError(fmt.Errorf("invalid plugin name '%s'", pluginName))
+	}
+	pluginsDir, err := coreutils.GetJfrogPluginsDir()
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigStore{pluginName: pluginName, dir: filepath.Join(pluginsDir, pluginName, pluginConfigDirName)}, nil
+}
+
+// The directory of the plugin's configuration. Plugins may keep additional files in it.
+func (cs *ConfigStore) Dir() string {
+	return cs.dir
+}
+
+// Read the value of a key into the value pointed by target.
+// Returns false if the key does not exist, in which case target is not changed.
+func (cs *ConfigStore) Get(key string, target interface{}) (bool, error) {
+	conf, err := cs.read()
+	if err != nil {
+		return false, err
+	}
+	value, exists := conf.Values[key]
+	if !exists {
+		return false, nil
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	return true, errorutils.CheckError(json.Unmarshal(content, target))
+}
+
+func (cs *ConfigStore) GetString(key string) (string, error) {
+	var value string
+	_, err := cs.Get(key, &value)
+	return value, err
+}
+
+func (cs *ConfigStore) GetInt(key string) (int, error) {
+	var value int
+	_, err := cs.Get(key, &value)
+	return value, err
+}
+
+func (cs *ConfigStore) GetBool(key string) (bool, error) {
+	var value bool
+	_, err := cs.Get(key, &value)
+	return value, err
+}
+
+func (cs *ConfigStore) Set(key string, value interface{}) error {
+	if key == "" {
+		return errorutils.CheckError(errors.New("the configuration key cannot be empty"))
+	}
+	// Convert the value to its JSON form, so that it is read the same way it was written.
+	content, err := json.Marshal(value)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	var jsonValue interface{}
+	if err = json.Unmarshal(content, &jsonValue); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return cs.update(func(conf *pluginConfig) {
+		if conf.Values == nil {
+			conf.Values = make(map[string]interface{})
+		}
+		conf.Values[key] = jsonValue
+	})
+}
+
+// Returns false if the secret does not exist.
+func (cs *ConfigStore) GetSecret(key string) (string, bool, error) {
+	conf, err := cs.read()
+	if err != nil {
+		return "", false, err
+	}
+	secret, exists := conf.Secrets[key]
+	return secret, exists, nil
+}
+
+func (cs *ConfigStore) SetSecret(key, secret string) error {
+	if key == "" {
+		return errorutils.CheckError(errors.New("the configuration key cannot be empty"))
+	}
+	return cs.update(func(conf *pluginConfig) {
+		if conf.Secrets == nil {
+			conf.Secrets = make(map[string]string)
+		}
+		conf.Secrets[key] = secret
+	})
+}
+
+// Delete a value or a secret. Deleting a key which does not exist does nothing.
+func (cs *ConfigStore) Delete(key string) error {
+	return cs.update(func(conf *pluginConfig) {
+		delete(conf.Values, key)
+		delete(conf.Secrets, key)
+	})
+}
+
+// The keys of the values and the secrets, sorted.
+func (cs *ConfigStore) Keys() ([]string, error) {
+	conf, err := cs.read()
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for key := range conf.Values {
+		keys = append(keys, key)
+	}
+	for key := range conf.Secrets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Read, change and save the configuration while holding the plugin's configuration lock.
+func (cs *ConfigStore) update(change func(conf *pluginConfig)) error {
+	lockFile, err := lock.CreateLockInDir(filepath.Join(cs.dir, pluginLocksDirName))
+	defer lockFile.Unlock()
+	if err != nil {
+		return err
+	}
+	conf, err := cs.read()
+	if err != nil {
+		return err
+	}
+	change(conf)
+	return cs.save(conf)
+}
+
+// Read the configuration, decrypting its secrets. If the configuration file does not exist, an empty configuration is returned.
+func (cs *ConfigStore) read() (*pluginConfig, error) {
+	conf := new(pluginConfig)
+	path := filepath.Join(cs.dir, pluginConfigFileName)
+	exists, err := fileutils.IsFileExists(path, false)
+	if err != nil || !exists {
+		return conf, err
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if err = yaml.Unmarshal(content, conf); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("failed to parse the configuration of the '%s' plugin: %s", cs.pluginName, err.Error()))
+	}
+	if !conf.Enc {
+		return conf, nil
+	}
+	key, err := config.GetMasterKey()
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return nil, errorutils.CheckError(fmt.Errorf("cannot decrypt the configuration of the '%s' plugin: no master key was found in the security configuration file", cs.pluginName))
+	}
+	for name, secret := range conf.Secrets {
+		if conf.Secrets[name], err = config.DecryptSecret(secret, key); err != nil {
+			return nil, err
+		}
+	}
+	conf.Enc = false
+	return conf, nil
+}
+
+// Save the configuration, encrypting its secrets if a master key is configured.
+// The file is replaced in a single rename, so that reading it while it is saved returns either the old or the new configuration.
+func (cs *ConfigStore) save(conf *pluginConfig) error {
+	key, err := config.GetMasterKey()
+	if err != nil {
+		return err
+	}
+	toSave := &pluginConfig{Version: pluginConfigVersion, Values: conf.Values, Secrets: conf.Secrets}
+	if key != "" && len(conf.Secrets) > 0 {
+		toSave.Secrets = make(map[string]string)
+		for name, secret := range conf.Secrets {
+			if toSave.Secrets[name], err = config.EncryptSecret(secret, key); err != nil {
+				return err
+			}
+		}
+		toSave.Enc = true
+	}
+	content, err := yaml.Marshal(toSave)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	tempFile, err := ioutil.TempFile(cs.dir, pluginConfigFileName+".*.tmp")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err = tempFile.Write(content); err != nil {
+		tempFile.Close()
+		return errorutils.CheckError(err)
+	}
+	if err = tempFile.Close(); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.Rename(tempFile.Name(), filepath.Join(cs.dir, pluginConfigFileName)))
+}
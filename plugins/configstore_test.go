@@ -0,0 +1,116 @@
+package plugins
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+type repoSettings struct {
+	Key     string   `json:"key"`
+	Threads int      `json:"threads,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+func TestConfigStoreValues(t *testing.T) {
+	oldHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer os.Setenv(coreutils.HomeDir, oldHome)
+	defer tests.CleanUnitTestsJfrogHome()
+	homeDir, err := coreutils.GetJfrogHomeDir()
+	assert.NoError(t, err)
+	store, err := NewConfigStore("test-plugin")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(homeDir, "plugins", "test-plugin", "config"), store.Dir())
+
+	found, err := store.Get("repo", new(repoSettings))
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, store.Set("url", "https://example.com"))
+	assert.NoError(t, store.Set("threads", 5))
+	assert.NoError(t, store.Set("verbose", true))
+	assert.NoError(t, store.Set("repo", repoSettings{Key: "libs", Threads: 2, Tags: []string{"a", "b"}}))
+
+	url, err := store.GetString("url")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", url)
+	threads, err := store.GetInt("threads")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, threads)
+	verbose, err := store.GetBool("verbose")
+	assert.NoError(t, err)
+	assert.True(t, verbose)
+	repo := new(repoSettings)
+	found, err = store.Get("repo", repo)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, &repoSettings{Key: "libs", Threads: 2, Tags: []string{"a", "b"}}, repo)
+
+	assert.NoError(t, store.Delete("threads"))
+	keys, err := store.Keys()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"repo", "url", "verbose"}, keys)
+
+	// Each plugin has its own configuration.
+	otherStore, err := NewConfigStore("other-plugin")
+	assert.NoError(t, err)
+	keys, err = otherStore.Keys()
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestConfigStoreSecrets(t *testing.T) {
+	oldHome, err := tests.SetJfrogHome()
+	assert.NoError(t, err)
+	defer os.Setenv(coreutils.HomeDir, oldHome)
+	defer tests.CleanUnitTestsJfrogHome()
+	homeDir, err := coreutils.GetJfrogHomeDir()
+	assert.NoError(t, err)
+	store, err := NewConfigStore("test-plugin")
+	assert.NoError(t, err)
+
+	// Without a master key, secrets are saved as is.
+	assert.NoError(t, store.SetSecret("token", "plain-token"))
+	assertConfigFileContains(t, store, "plain-token", true)
+
+	// Once a master key is configured, secrets are encrypted on the next change.
+	securityDir := filepath.Join(homeDir, "security")
+	assert.NoError(t, os.MkdirAll(securityDir, 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(securityDir, "security.yaml"), []byte("version: 1\nmasterKey: "+strings.Repeat("k", 32)+"\n"), 0600))
+	assert.NoError(t, store.SetSecret("password", "my-password"))
+	assertConfigFileContains(t, store, "plain-token", false)
+	assertConfigFileContains(t, store, "my-password", false)
+
+	token, found, err := store.GetSecret("token")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "plain-token", token)
+	password, _, err := store.GetSecret("password")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-password", password)
+
+	// Encrypted secrets cannot be read once the master key is removed.
+	assert.NoError(t, os.Remove(filepath.Join(securityDir, "security.yaml")))
+	_, _, err = store.GetSecret("password")
+	assert.Error(t, err)
+}
+
+func assertConfigFileContains(t *testing.T, store *ConfigStore, value string, expected bool) {
+	content, err := ioutil.ReadFile(filepath.Join(store.Dir(), "config.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, strings.Contains(string(content), value), string(content))
+}
+
+func TestNewConfigStoreInvalidName(t *testing.T) {
+	for _, name := range []string{"", "..", "a/b", `a\b`} {
+		_, err := NewConfigStore(name)
+		assert.Error(t, err, name)
+	}
+}
@@ -46,20 +46,32 @@ GLOBAL OPTIONS:
 
 `
 
+// Used for the help of namespaces and of commands with subcommands.
+const subcommandHelpTemplate = `NAME:
+   jfrog {{.HelpName}} - {{.Description}}
+
+USAGE:
+   jfrog {{.HelpName}} command{{if .VisibleFlags}} [command options]{{end}} [arguments...]
+
+COMMANDS:
+   {{range .VisibleCommands}}{{join .Names ", "}}{{ "\t" }}{{if .Description}}{{.Description}}{{else}}{{.Usage}}{{end}}
+   {{end}}{{if .VisibleFlags}}
+OPTIONS:
+   {{range .VisibleFlags}}{{.}}
+   {{end}}{{end}}
+
+`
+
 func PluginMain(jfrogApp components.App) {
 	log.SetDefaultLogger()
 
 	// Set the plugin's user-agent as the jfrog-cli-core's.
 	utils.SetUserAgent(jfrogclicore.GetUserAgent())
 
-	cli.CommandHelpTemplate = commandHelpTemplate
-	cli.AppHelpTemplate = appHelpTemplate
-
-	baseApp, err := components.ConvertApp(jfrogApp)
+	baseApp, err := CreatePluginApp(jfrogApp)
 	if err != nil {
 		coreutils.ExitOnErr(err)
 	}
-	addHiddenPluginSignatureCommand(baseApp)
 
 	args := os.Args
 	err = baseApp.Run(args)
@@ -70,3 +82,17 @@ func PluginMain(jfrogApp components.App) {
 
 	coreutils.ExitOnErr(err)
 }
+
+// Convert the plugin's app to a cli.App, with the plugins' help templates and the hidden signature command.
+func CreatePluginApp(jfrogApp components.App) (*cli.App, error) {
+	cli.CommandHelpTemplate = commandHelpTemplate
+	cli.AppHelpTemplate = appHelpTemplate
+	cli.SubcommandHelpTemplate = subcommandHelpTemplate
+
+	baseApp, err := components.ConvertApp(jfrogApp)
+	if err != nil {
+		return nil, err
+	}
+	addHiddenPluginSignatureCommand(baseApp, jfrogApp)
+	return baseApp, nil
+}
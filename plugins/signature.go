@@ -1,28 +1,36 @@
 package plugins
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
 	"github.com/codegangsta/cli"
+	jfrogclicore "github.com/jfrog/jfrog-cli-core/v2"
 	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/utils/version"
 )
 
 const SignatureCommandName = "hidden-plugin-signature"
 
 // Adds a hidden command to every built plugin.
-// The command will later be used by the CLI to retrieve the plugin's signature to show in the CLI's help command.
-func addHiddenPluginSignatureCommand(baseApp *cli.App) {
+// The command will later be used by the CLI to retrieve the plugin's signature to show in the CLI's help command,
+// and to verify that the plugin can run with the CLI.
+func addHiddenPluginSignatureCommand(baseApp *cli.App, jfrogApp components.App) {
 	cmd := cli.Command{
 		Name:     SignatureCommandName,
 		Hidden:   true,
 		HideHelp: true,
 		Action: func(c *cli.Context) error {
-			signature := components.PluginSignature{
-				Name:  baseApp.Name,
-				Usage: baseApp.Description,
-			}
-			content, err := json.Marshal(signature)
+			content, err := json.Marshal(createPluginSignature(jfrogApp))
 			if err == nil {
 				log.Output(clientutils.IndentJson(content))
 			}
@@ -31,3 +39,65 @@ func addHiddenPluginSignatureCommand(baseApp *cli.App) {
 	}
 	baseApp.Commands = append(baseApp.Commands, cmd)
 }
+
+func createPluginSignature(jfrogApp components.App) *components.PluginSignature {
+	return &components.PluginSignature{
+		Name:               jfrogApp.Name,
+		Usage:              jfrogApp.Description,
+		Version:            jfrogApp.Version,
+		CoreVersion:        jfrogclicore.GetVersion(),
+		MinimumCoreVersion: jfrogApp.MinimumCoreVersion,
+		Commands:           components.CreateCommandSignatures(jfrogApp),
+	}
+}
+
+// Get the signature of a plugin by executing the plugin's binary with its hidden signature command.
+// The binary is executed as is, so it should only be called for plugins which are trusted, such as installed plugins.
+// The signature's checksum is calculated from the binary, so that it can be compared with a known checksum of the plugin.
+func GetPluginSignature(executablePath string) (*components.PluginSignature, error) {
+	output, err := exec.Command(executablePath, SignatureCommandName).Output()
+	if err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("failed to get the signature of the plugin at %s: %s", executablePath, err.Error()))
+	}
+	signature := new(components.PluginSignature)
+	if err = json.Unmarshal(output, signature); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("failed to parse the signature of the plugin at %s: %s", executablePath, err.Error()))
+	}
+	if signature.Checksum, err = calcSha256(executablePath); err != nil {
+		return nil, err
+	}
+	signature.ExecutablePath = executablePath
+	return signature, nil
+}
+
+// The SHA-256 checksum is calculated here, since the checksum details of fileutils.GetFileDetails have no SHA-256 in this version of jfrog-client-go.
+func calcSha256(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Verify that a plugin can run with the given version of jfrog-cli-core, which is the version used by the CLI running the plugin.
+// The plugin must be built with the same major version of jfrog-cli-core, and the version must satisfy the plugin's minimum version.
+func VerifyPluginCompatibility(signature *components.PluginSignature, coreVersion string) error {
+	if signature.CoreVersion != "" && majorVersion(signature.CoreVersion) != majorVersion(coreVersion) {
+		return errorutils.CheckError(fmt.Errorf("the plugin '%s' was built with jfrog-cli-core %s, which is incompatible with jfrog-cli-core %s",
+			signature.Name, signature.CoreVersion, coreVersion))
+	}
+	if signature.MinimumCoreVersion != "" && !version.NewVersion(coreVersion).AtLeast(signature.MinimumCoreVersion) {
+		return errorutils.CheckError(fmt.Errorf("the plugin '%s' requires jfrog-cli-core %s or above, but the current version is %s",
+			signature.Name, signature.MinimumCoreVersion, coreVersion))
+	}
+	return nil
+}
+
+func majorVersion(fullVersion string) string {
+	return strings.SplitN(strings.TrimPrefix(fullVersion, "v"), ".", 2)[0]
+}
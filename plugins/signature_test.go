@@ -0,0 +1,93 @@
+package plugins
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	jfrogclicore "github.com/jfrog/jfrog-cli-core/v2"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePluginSignature(t *testing.T) {
+	app := components.App{
+		Name:               "test-plugin",
+		Description:        "A plugin for tests.",
+		Version:            "1.2.0",
+		MinimumCoreVersion: "1.0.0",
+		Commands: []components.Command{{
+			Name:      "hello",
+			Aliases:   []string{"hi"},
+			Arguments: []components.Argument{{Name: "names", Optional: true, Variadic: true}},
+			Flags: []components.Flag{
+				components.BoolFlag{Name: "shout", Description: "Shout."},
+				components.EnumFlag{Name: "lang", AllowedValues: []string{"en", "fr"}, DefaultValue: "en", EnvVar: "HELLO_LANG"},
+			},
+		}},
+		Namespaces: []components.Namespace{{Name: "repo", Commands: []components.Command{{Name: "create"}}}},
+	}
+	signature := createPluginSignature(app)
+	assert.Equal(t, "test-plugin", signature.Name)
+	assert.Equal(t, "1.2.0", signature.Version)
+	assert.Equal(t, jfrogclicore.GetVersion(), signature.CoreVersion)
+	assert.Equal(t, "1.0.0", signature.MinimumCoreVersion)
+	assert.Equal(t, []components.CommandSignature{
+		{
+			Name:      "hello",
+			Aliases:   []string{"hi"},
+			Arguments: []components.ArgumentSignature{{Name: "names", Optional: true, Variadic: true}},
+			Flags: []components.FlagSignature{
+				{Name: "shout", Description: "Shout.", Type: "bool", Default: "false"},
+				{Name: "lang", Type: "enum", Default: "en", EnvVar: "HELLO_LANG", AllowedValues: []string{"en", "fr"}},
+			},
+		},
+		{Name: "repo", Subcommands: []components.CommandSignature{{Name: "create"}}},
+	}, signature.Commands)
+}
+
+func TestGetPluginSignature(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test plugin is a shell script.")
+	}
+	tempDir, err := ioutil.TempDir("", "plugin")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	// The plugin reports a checksum of its own, which is replaced by the checksum of its executable.
+	executablePath := filepath.Join(tempDir, "test-plugin")
+	content := []byte("#!/bin/sh\necho '{\"name\":\"test-plugin\",\"version\":\"1.2.0\",\"checksum\":\"reported\"}'\n")
+	assert.NoError(t, ioutil.WriteFile(executablePath, content, 0755))
+
+	signature, err := GetPluginSignature(executablePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-plugin", signature.Name)
+	assert.Equal(t, "1.2.0", signature.Version)
+	assert.Equal(t, executablePath, signature.ExecutablePath)
+	checksum := sha256.Sum256(content)
+	assert.Equal(t, hex.EncodeToString(checksum[:]), signature.Checksum)
+}
+
+func TestVerifyPluginCompatibility(t *testing.T) {
+	tests := []struct {
+		coreVersion        string
+		minimumCoreVersion string
+		hostCoreVersion    string
+		compatible         bool
+	}{
+		{"", "", "2.0.0", true},
+		{"2.1.0", "", "2.0.0", true},
+		{"2.1.0", "2.0.5", "2.0.5", true},
+		{"2.1.0", "2.0.5", "2.0.4", false},
+		{"1.9.0", "", "2.0.0", false},
+		{"v2.0.0", "", "2.3.0", true},
+	}
+	for _, test := range tests {
+		signature := &components.PluginSignature{Name: "test-plugin", CoreVersion: test.coreVersion, MinimumCoreVersion: test.minimumCoreVersion}
+		err := VerifyPluginCompatibility(signature, test.hostCoreVersion)
+		assert.Equal(t, test.compatible, err == nil, test)
+	}
+}
@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/codegangsta/cli"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	corelog "github.com/jfrog/jfrog-cli-core/v2/utils/log"
+	coretests "github.com/jfrog/jfrog-cli-core/v2/utils/tests"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// Runs the commands of a plugin in-process, as they would run by 'jfrog <plugin> <command>'.
+//
+// The servers set on the runner are saved to the configuration of the tests JFrog home directory (see tests.SetJfrogHome),
+// so that plugins.GetServerDetails returns them instead of the servers configured on the machine.
+// Since a run replaces the process' stdout, stderr, environment and logger, runs must not be done in parallel.
+type PluginRunner struct {
+	app     components.App
+	servers []*config.ServerDetails
+	env     map[string]string
+}
+
+// The outcome of running a command.
+type RunResult struct {
+	// What the command wrote to stdout, including the output written by log.Output.
+	Stdout string
+	// What the command wrote to stderr directly.
+	Stderr string
+	// What the command logged using the Debug, Info, Warn and Error log functions.
+	Log string
+	// The error returned by the command, including argument and flag validation errors.
+	Err error
+}
+
+func NewPluginRunner(app components.App) *PluginRunner {
+	return &PluginRunner{app: app, env: make(map[string]string)}
+}
+
+// The servers available to the plugin. Unless one of them is marked as default, the first one is the default server.
+func (pr *PluginRunner) SetServers(servers ...*config.ServerDetails) *PluginRunner {
+	pr.servers = servers
+	return pr
+}
+
+// Set an environment variable for the runs. The variable is restored once each run is done.
+func (pr *PluginRunner) SetEnv(key, value string) *PluginRunner {
+	pr.env[key] = value
+	return pr
+}
+
+// Run a command, given its arguments without the plugin name. For example: Run("repo", "create", "--name=libs").
+func (pr *PluginRunner) Run(args ...string) *RunResult {
+	result := new(RunResult)
+	restoreEnv, err := pr.prepareEnv()
+	defer func() {
+		if restoreErr := restoreEnv(); result.Err == nil {
+			result.Err = restoreErr
+		}
+	}()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	// Commands which return cli.ExitCoder errors must not exit the tests.
+	previousExiter := cli.OsExiter
+	cli.OsExiter = func(int) {}
+	defer func() { cli.OsExiter = previousExiter }()
+
+	logs := new(bytes.Buffer)
+	result.Stdout, result.Stderr, err = captureOutput(func() error {
+		// The logger is created once stdout is replaced, since log.Output writes to the stdout of the time it was created.
+		previousLogger := log.Logger
+		log.SetLogger(log.NewLogger(corelog.GetCliLogLevel(), logs))
+		defer log.SetLogger(previousLogger)
+
+		baseApp, err := plugins.CreatePluginApp(pr.app)
+		if err != nil {
+			return err
+		}
+		return baseApp.Run(append([]string{pr.app.Name}, args...))
+	})
+	result.Log = logs.String()
+	result.Err = err
+	return result
+}
+
+// Set the JFrog home to the tests home with the runner's servers, and set the runner's environment variables.
+// Returns a function which restores the previous environment and removes the tests home.
+func (pr *PluginRunner) prepareEnv() (restore func() error, err error) {
+	previousEnv := make(map[string]*string)
+	oldHome := ""
+	restore = func() error {
+		for key, value := range previousEnv {
+			if value == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *value)
+			}
+		}
+		if oldHome == "" {
+			return nil
+		}
+		coretests.CleanUnitTestsJfrogHome()
+		return errorutils.CheckError(os.Setenv(coreutils.HomeDir, oldHome))
+	}
+	setEnv := func(key, value string) error {
+		if _, saved := previousEnv[key]; !saved {
+			if previous, exists := os.LookupEnv(key); exists {
+				previousEnv[key] = &previous
+			} else {
+				previousEnv[key] = nil
+			}
+		}
+		return errorutils.CheckError(os.Setenv(key, value))
+	}
+
+	if oldHome, err = coretests.SetJfrogHome(); err != nil {
+		return restore, errorutils.CheckError(err)
+	}
+	for key, value := range pr.env {
+		if err = setEnv(key, value); err != nil {
+			return restore, err
+		}
+	}
+	if len(pr.servers) > 0 {
+		if err = config.SaveServersConf(defaultServerFirst(pr.servers)); err != nil {
+			return restore, err
+		}
+	}
+	return restore, nil
+}
+
+// Copy the servers, marking the first one as default if none is.
+func defaultServerFirst(servers []*config.ServerDetails) []*config.ServerDetails {
+	var copied []*config.ServerDetails
+	hasDefault := false
+	for _, server := range servers {
+		serverCopy := *server
+		hasDefault = hasDefault || serverCopy.IsDefault
+		copied = append(copied, &serverCopy)
+	}
+	if !hasDefault {
+		copied[0].IsDefault = true
+	}
+	return copied
+}
+
+// Run a function while capturing what it writes to stdout and stderr.
+func captureOutput(run func() error) (stdout, stderr string, err error) {
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return "", "", errorutils.CheckError(err)
+	}
+	stderrReader, stderrWriter, err := os.Pipe()
+	if err != nil {
+		return "", "", errorutils.CheckError(err)
+	}
+	stdoutBuffer, stderrBuffer := new(bytes.Buffer), new(bytes.Buffer)
+	var wg sync.WaitGroup
+	for reader, buffer := range map[io.Reader]*bytes.Buffer{stdoutReader: stdoutBuffer, stderrReader: stderrBuffer} {
+		wg.Add(1)
+		go func(reader io.Reader, buffer *bytes.Buffer) {
+			defer wg.Done()
+			io.Copy(buffer, reader)
+		}(reader, buffer)
+	}
+
+	previousStdout, previousStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutWriter, stderrWriter
+	func() {
+		defer func() {
+			os.Stdout, os.Stderr = previousStdout, previousStderr
+			stdoutWriter.Close()
+			stderrWriter.Close()
+		}()
+		err = run()
+	}()
+	wg.Wait()
+	stdoutReader.Close()
+	stderrReader.Close()
+	return stdoutBuffer.String(), stderrBuffer.String(), err
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/plugins"
+	"github.com/jfrog/jfrog-cli-core/v2/plugins/components"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestApp() components.App {
+	return components.App{
+		Name: "test-plugin",
+		Namespaces: []components.Namespace{{
+			Name: "server",
+			Commands: []components.Command{{
+				Name:              "show",
+				Arguments:         []components.Argument{{Name: "field"}},
+				ValidateArguments: true,
+				Flags:             []components.Flag{plugins.GetServerIdFlag()},
+				Action: func(c *components.Context) error {
+					details, err := plugins.GetServerDetails(c)
+					if err != nil {
+						return err
+					}
+					log.Info("Showing the", c.GetArgumentValue("field"), "of", details.ServerId)
+					switch c.GetArgumentValue("field") {
+					case "url":
+						log.Output(details.Url)
+					case "user":
+						fmt.Fprintln(os.Stderr, os.Getenv("TEST_PLUGIN_PREFIX")+details.User)
+					default:
+						return errors.New("unknown field")
+					}
+					return nil
+				},
+			}},
+		}},
+	}
+}
+
+func TestPluginRunner(t *testing.T) {
+	runner := NewPluginRunner(createTestApp()).
+		SetServers(&config.ServerDetails{ServerId: "first", Url: "http://first/", User: "admin"},
+			&config.ServerDetails{ServerId: "second", Url: "http://second/", User: "deployer"}).
+		SetEnv("TEST_PLUGIN_PREFIX", "user: ")
+	tests := []struct {
+		name           string
+		args           []string
+		expectedStdout string
+		expectedStderr string
+		expectedLog    string
+		expectedErr    string
+	}{
+		{"default server", []string{"server", "show", "url"}, "http://first/\n", "", "[Info] Showing the url of first\n", ""},
+		{"server id", []string{"server", "show", "--server-id=second", "user"}, "", "user: deployer\n", "[Info] Showing the user of second\n", ""},
+		{"action error", []string{"server", "show", "email"}, "", "", "[Info] Showing the email of first\n", "unknown field"},
+		{"missing argument", []string{"server", "show"}, "", "", "", "Wrong number of arguments: expected 1, but received 0."},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := runner.Run(test.args...)
+			if test.expectedErr == "" {
+				assert.NoError(t, result.Err)
+				assert.Equal(t, test.expectedStdout, result.Stdout)
+			} else {
+				assert.EqualError(t, result.Err, test.expectedErr)
+			}
+			assert.Equal(t, test.expectedStderr, result.Stderr)
+			assert.Equal(t, test.expectedLog, result.Log)
+		})
+	}
+	// The environment is restored after each run.
+	_, exists := os.LookupEnv("TEST_PLUGIN_PREFIX")
+	assert.False(t, exists)
+}
+
+func TestPluginRunnerWithoutServers(t *testing.T) {
+	result := NewPluginRunner(createTestApp()).Run("server", "show", "url")
+	assert.Error(t, result.Err)
+}
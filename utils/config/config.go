@@ -294,57 +294,17 @@ func convertCertsDir() error {
 }
 
 // The configuration schema can change between versions, therefore we need to convert old versions to the new schema.
+// The converted configuration is saved, after a backup of the JFrog home directory is created.
 func convertIfNeeded(content []byte) ([]byte, error) {
-	version, err := getVersion(content)
+	config, result, err := migrateConfig(content, false)
 	if err != nil {
 		return nil, err
 	}
-
-	// Switch contains FALLTHROUGH to convert from a certain version to the latest.
-	switch version {
-	case strconv.Itoa(coreutils.GetConfigVersion()):
+	if len(result.Steps) == 0 {
 		return content, nil
-	case "0":
-		content, err = convertConfigV0toV1(content)
-		if err != nil {
-			return nil, err
-		}
-		fallthrough
-	case "1":
-		err = createHomeDirBackup()
-		if err != nil {
-			return nil, err
-		}
-		err = convertCertsDir()
-		if err != nil {
-			return nil, err
-		}
-		fallthrough
-	case "2":
-		content, err = convertConfigV2toV3(content)
-		if err != nil {
-			return nil, err
-		}
-		fallthrough
-	case "3", "4":
-		content, err = convertConfigV4toV5(content)
 	}
-
-	// Save config after all conversions (also updates version).
-	result := new(ConfigV5)
-	err = json.Unmarshal(content, &result)
-	if errorutils.CheckError(err) != nil {
-		return nil, err
-	}
-	err = saveConfig(result)
-	if err != nil {
-		return nil, err
-	}
-	content, err = json.Marshal(&result)
-	if err != nil {
-		return nil, errorutils.CheckError(err)
-	}
-	return content, err
+	content, err = json.Marshal(&config)
+	return content, errorutils.CheckError(err)
 }
 
 // Creating a homedir backup prior to converting.
@@ -359,8 +319,16 @@ func createHomeDirBackup() error {
 	}
 
 	// Copy homedir contents to backup dir, excluding redundant dirs and the backup dir itself.
-	backupName := ".jfrog-" + strconv.FormatInt(time.Now().Unix(), 10)
+	// The backup's name includes the time in nanoseconds, so that backups created in the same second are not merged.
+	backupName := homeDirBackupPrefix + strconv.FormatInt(time.Now().UnixNano(), 10)
 	curBackupPath := filepath.Join(backupDir, backupName)
+	exists, err := fileutils.IsDirExists(curBackupPath, false)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errorutils.CheckError(errors.New("the homedir backup already exists at: " + curBackupPath))
+	}
 	log.Debug("Creating a homedir backup at: " + curBackupPath)
 	exclude := []string{coreutils.JfrogBackupDirName, coreutils.JfrogDependenciesDirName, coreutils.JfrogLockDirName, coreutils.JfrogLogsDirName}
 	return fileutils.CopyDir(homeDir, curBackupPath, true, exclude)
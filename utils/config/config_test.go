@@ -130,6 +130,10 @@ func TestConvertConfigV4ToV5(t *testing.T) {
 		}
 	`
 
+	tempDirPath, oldHomeDir := createTempEnv(t)
+	defer os.RemoveAll(tempDirPath)
+	defer os.Setenv(coreutils.HomeDir, oldHomeDir)
+
 	content, err := convertIfNeeded([]byte(configV4))
 	assert.NoError(t, err)
 	configV5 := new(ConfigV5)
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	bundleVersion    = 1
+	bundleSaltLength = 16
+	// The scrypt parameters recommended for interactive logins.
+	bundleScryptN = 32768
+	bundleScryptR = 8
+	bundleScryptP = 1
+)
+
+// The policy for importing a server whose server ID already exists in the configuration.
+type BundleImportPolicy string
+
+const (
+	// Replace the existing server with the imported one.
+	OverwriteServers BundleImportPolicy = "overwrite"
+	// Keep the existing server, ignoring the imported one.
+	SkipExistingServers BundleImportPolicy = "skip"
+	// Update the existing server with the non-empty fields of the imported one.
+	MergeServers BundleImportPolicy = "merge"
+)
+
+var BundleImportPolicies = []BundleImportPolicy{OverwriteServers, SkipExistingServers, MergeServers}
+
+// The file of a bundle of servers, encrypted with a passphrase.
+type configBundleFile struct {
+	Version int `json:"version"`
+	// The salt of the key derived from the passphrase, base64 encoded.
+	Salt []byte `json:"salt"`
+	// The encrypted configBundle.
+	Data string `json:"data"`
+}
+
+type configBundle struct {
+	Servers         []*configToken `json:"servers"`
+	DefaultServerId string         `json:"defaultServerId,omitempty"`
+}
+
+// Export servers as a bundle encrypted with the passphrase, which can be imported by ImportBundle.
+// Unlike Export, the master key is not requested, so that bundles can be exported non-interactively.
+// File paths, such as the SSH key path and the client certificate path, are exported as is.
+func ExportBundle(servers []*ServerDetails, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errorutils.CheckError(errors.New("a passphrase is required to export servers"))
+	}
+	bundle := configBundle{}
+	for _, server := range servers {
+		bundle.Servers = append(bundle.Servers, fromServerDetails(server))
+		if server.IsDefault {
+			bundle.DefaultServerId = server.ServerId
+		}
+	}
+	content, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	bundleFile := configBundleFile{Version: bundleVersion, Salt: make([]byte, bundleSaltLength)}
+	if _, err = io.ReadFull(rand.Reader, bundleFile.Salt); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	key, err := deriveBundleKey(passphrase, bundleFile.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if bundleFile.Data, err = encrypt(string(content), key); err != nil {
+		return nil, err
+	}
+	content, err = json.MarshalIndent(bundleFile, "", "  ")
+	return content, errorutils.CheckError(err)
+}
+
+// Decrypt a bundle created by ExportBundle. Returns the servers of the bundle, of which the default server of the exporting
+// configuration is marked as default.
+func ImportBundle(content []byte, passphrase string) ([]*ServerDetails, error) {
+	bundleFile := new(configBundleFile)
+	if err := json.Unmarshal(content, bundleFile); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("failed to parse the servers bundle: %s", err.Error()))
+	}
+	if bundleFile.Version != bundleVersion {
+		return nil, errorutils.CheckError(fmt.Errorf("unsupported servers bundle version %d", bundleFile.Version))
+	}
+	key, err := deriveBundleKey(passphrase, bundleFile.Salt)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := decrypt(bundleFile.Data, key)
+	if err != nil {
+		return nil, errorutils.CheckError(errors.New("failed to decrypt the servers bundle, the passphrase may be wrong"))
+	}
+	bundle := new(configBundle)
+	if err = json.Unmarshal([]byte(decrypted), bundle); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var servers []*ServerDetails
+	for _, token := range bundle.Servers {
+		server := toServerDetails(token)
+		server.IsDefault = server.ServerId == bundle.DefaultServerId
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+func deriveBundleKey(passphrase string, salt []byte) (string, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, bundleScryptN, bundleScryptR, bundleScryptP, masterKeyLength)
+	return string(key), errorutils.CheckError(err)
+}
+
+// Add the imported servers to the existing ones, according to the import policy.
+// The default server is defaultServerId if not empty. Otherwise, the existing default server is kept,
+// or if there is none, the default server of the imported servers is used.
+// Returns the servers to save, and the IDs of the servers which were imported.
+func MergeBundleServers(existing, imported []*ServerDetails, policy BundleImportPolicy, defaultServerId string) ([]*ServerDetails, []string, error) {
+	if !isValidBundleImportPolicy(policy) {
+		return nil, nil, errorutils.CheckError(fmt.Errorf("unsupported import policy '%s'", policy))
+	}
+	merged := append([]*ServerDetails{}, existing...)
+	existingDefault := ""
+	for _, server := range existing {
+		if server.IsDefault {
+			existingDefault = server.ServerId
+		}
+	}
+	importedDefault := ""
+	var importedIds []string
+	for _, server := range imported {
+		index := -1
+		for i, current := range merged {
+			if current.ServerId == server.ServerId {
+				index = i
+				break
+			}
+		}
+		switch {
+		case index == -1:
+			merged = append(merged, server)
+		case policy == SkipExistingServers:
+			continue
+		case policy == OverwriteServers:
+			merged[index] = server
+		case policy == MergeServers:
+			merged[index] = mergeServerDetails(merged[index], server)
+		}
+		importedIds = append(importedIds, server.ServerId)
+		if server.IsDefault {
+			importedDefault = server.ServerId
+		}
+	}
+
+	switch {
+	case defaultServerId != "":
+	case existingDefault != "":
+		defaultServerId = existingDefault
+	case importedDefault != "":
+		defaultServerId = importedDefault
+	case len(merged) > 0:
+		defaultServerId = merged[0].ServerId
+	}
+	foundDefault := false
+	for _, server := range merged {
+		server.IsDefault = server.ServerId == defaultServerId
+		foundDefault = foundDefault || server.IsDefault
+	}
+	if !foundDefault && defaultServerId != "" {
+		return nil, nil, errorutils.CheckError(fmt.Errorf("the default server ID '%s' does not exist", defaultServerId))
+	}
+	return merged, importedIds, nil
+}
+
+func isValidBundleImportPolicy(policy BundleImportPolicy) bool {
+	for _, validPolicy := range BundleImportPolicies {
+		if policy == validPolicy {
+			return true
+		}
+	}
+	return false
+}
+
+// Return a copy of the existing server, updated with the non-empty fields of the imported server.
+func mergeServerDetails(existing, imported *ServerDetails) *ServerDetails {
+	mergedToken := fromServerDetails(existing)
+	importedToken := fromServerDetails(imported)
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&mergedToken.Url, importedToken.Url},
+		{&mergedToken.ArtifactoryUrl, importedToken.ArtifactoryUrl},
+		{&mergedToken.DistributionUrl, importedToken.DistributionUrl},
+		{&mergedToken.XrayUrl, importedToken.XrayUrl},
+		{&mergedToken.MissionControlUrl, importedToken.MissionControlUrl},
+		{&mergedToken.PipelinesUrl, importedToken.PipelinesUrl},
+		{&mergedToken.User, importedToken.User},
+		{&mergedToken.Password, importedToken.Password},
+		{&mergedToken.SshKeyPath, importedToken.SshKeyPath},
+		{&mergedToken.SshPassphrase, importedToken.SshPassphrase},
+		{&mergedToken.AccessToken, importedToken.AccessToken},
+		{&mergedToken.RefreshToken, importedToken.RefreshToken},
+		{&mergedToken.ClientCertPath, importedToken.ClientCertPath},
+		{&mergedToken.ClientCertKeyPath, importedToken.ClientCertKeyPath},
+	} {
+		if field.value != "" {
+			*field.target = field.value
+		}
+	}
+	if importedToken.TokenRefreshInterval != 0 {
+		mergedToken.TokenRefreshInterval = importedToken.TokenRefreshInterval
+	}
+	return toServerDetails(mergedToken)
+}
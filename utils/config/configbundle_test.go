@@ -0,0 +1,103 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportImportBundle(t *testing.T) {
+	servers := []*ServerDetails{
+		{ServerId: "prod", ArtifactoryUrl: "https://prod.example.com/artifactory/", User: "admin", Password: "prod-password"},
+		{ServerId: "dev", Url: "https://dev.example.com/", AccessToken: "dev-token", TokenRefreshInterval: 60, IsDefault: true},
+	}
+	content, err := ExportBundle(servers, "passphrase")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "prod-password")
+	assert.NotContains(t, string(content), "dev-token")
+
+	imported, err := ImportBundle(content, "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, servers, imported)
+
+	_, err = ImportBundle(content, "wrong")
+	assert.EqualError(t, err, "failed to decrypt the servers bundle, the passphrase may be wrong")
+	_, err = ExportBundle(servers, "")
+	assert.Error(t, err)
+	_, err = ImportBundle([]byte(`{"version": 2}`), "passphrase")
+	assert.EqualError(t, err, "unsupported servers bundle version 2")
+}
+
+func createBundleServers(defaultId string, servers ...ServerDetails) []*ServerDetails {
+	var details []*ServerDetails
+	for i := range servers {
+		server := servers[i]
+		server.IsDefault = server.ServerId == defaultId
+		details = append(details, &server)
+	}
+	return details
+}
+
+func TestMergeBundleServers(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          BundleImportPolicy
+		defaultServerId string
+		existingDefault string
+		importedDefault string
+		expected        []*ServerDetails
+		expectedIds     []string
+	}{
+		{
+			name: "overwrite", policy: OverwriteServers, existingDefault: "a", importedDefault: "b",
+			expected: createBundleServers("a",
+				ServerDetails{ServerId: "a", ArtifactoryUrl: "new-a"},
+				ServerDetails{ServerId: "b", User: "user-b"},
+				ServerDetails{ServerId: "c", ArtifactoryUrl: "new-c"}),
+			expectedIds: []string{"a", "c"},
+		},
+		{
+			name: "skip", policy: SkipExistingServers, defaultServerId: "c",
+			expected: createBundleServers("c",
+				ServerDetails{ServerId: "a", ArtifactoryUrl: "old-a", User: "user-a"},
+				ServerDetails{ServerId: "b", User: "user-b"},
+				ServerDetails{ServerId: "c", ArtifactoryUrl: "new-c"}),
+			expectedIds: []string{"c"},
+		},
+		{
+			name: "merge", policy: MergeServers, importedDefault: "c",
+			expected: createBundleServers("c",
+				ServerDetails{ServerId: "a", ArtifactoryUrl: "new-a", User: "user-a"},
+				ServerDetails{ServerId: "b", User: "user-b"},
+				ServerDetails{ServerId: "c", ArtifactoryUrl: "new-c"}),
+			expectedIds: []string{"a", "c"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := createBundleServers(test.existingDefault,
+				ServerDetails{ServerId: "a", ArtifactoryUrl: "old-a", User: "user-a"},
+				ServerDetails{ServerId: "b", User: "user-b"})
+			imported := createBundleServers(test.importedDefault,
+				ServerDetails{ServerId: "a", ArtifactoryUrl: "new-a"},
+				ServerDetails{ServerId: "c", ArtifactoryUrl: "new-c"})
+			merged, importedIds, err := MergeBundleServers(existing, imported, test.policy, test.defaultServerId)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, merged)
+			assert.Equal(t, test.expectedIds, importedIds)
+		})
+	}
+}
+
+func TestMergeBundleServersErrors(t *testing.T) {
+	imported := createBundleServers("", ServerDetails{ServerId: "a"})
+	_, _, err := MergeBundleServers(nil, imported, "replace", "")
+	assert.EqualError(t, err, "unsupported import policy 'replace'")
+	_, _, err = MergeBundleServers(nil, imported, OverwriteServers, "b")
+	assert.EqualError(t, err, "the default server ID 'b' does not exist")
+
+	// Without any default, the first server becomes the default.
+	merged, _, err := MergeBundleServers(nil, imported, OverwriteServers, "")
+	assert.NoError(t, err)
+	assert.True(t, merged[0].IsDefault)
+}
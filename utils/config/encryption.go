@@ -135,6 +135,21 @@ func getMasterKeyFromSecurityConfFile() (key string, secFileExists bool, err err
 	return key, true, nil
 }
 
+// Return the master key of the security configuration file, or an empty string if there is none.
+// Used together with EncryptSecret and DecryptSecret by configurations other than the CLI's configuration file.
+func GetMasterKey() (string, error) {
+	key, _, err := getMasterKeyFromSecurityConfFile()
+	return key, err
+}
+
+func EncryptSecret(secret, key string) (string, error) {
+	return encrypt(secret, key)
+}
+
+func DecryptSecret(encryptedSecret, key string) (string, error) {
+	return decrypt(encryptedSecret, key)
+}
+
 func readMasterKeyFromConsole() (string, error) {
 	print("Please enter the master key: ")
 	bytePassword, err := terminal.ReadPassword(int(syscall.Stdin))
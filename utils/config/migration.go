@@ -0,0 +1,276 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/lock"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const homeDirBackupPrefix = ".jfrog-"
+
+// A step of the configuration migration chain, which migrates the configuration from a version to the next one.
+type migrationStep struct {
+	fromVersion int
+	description string
+	// Converts the content of the configuration file. Nil if the step doesn't change the content.
+	convert func(content []byte) ([]byte, error)
+	// Changes other files of the JFrog home directory. Nil if the step has no such changes. Skipped on dry-run.
+	apply func() error
+}
+
+// The migration chain. Each step migrates from its version to the next one, up to the current version.
+// When the configuration schema changes, a step is added here and the version returned by coreutils.GetConfigVersion is incremented.
+var migrationSteps = []migrationStep{
+	{fromVersion: 0, description: "Add a version and convert the Artifactory server to a list of servers", convert: convertConfigV0toV1},
+	{fromVersion: 1, description: "Move the SSL certificates from the security directory to the certs directory", apply: convertCertsDir},
+	{fromVersion: 2, description: "Lowercase the Artifactory usernames", convert: convertConfigV2toV3},
+	// Version 4 changed the name of the configuration file only.
+	{fromVersion: 3, description: "Rename the configuration file to include its version"},
+	{fromVersion: 4, description: "Convert the Artifactory servers to JFrog platform servers", convert: convertConfigV4toV5},
+}
+
+// The outcome of migrating the configuration to the current version.
+type MigrationResult struct {
+	FromVersion int
+	ToVersion   int
+	// The descriptions of the steps, in the order they were applied.
+	Steps []string
+	// The lines of the configuration which were removed and added by the migration, prefixed by '-' and '+'.
+	// Unchanged lines are prefixed by a space. Secrets are masked.
+	Diff []string
+}
+
+// Return the steps which migrate the configuration from the given version to the current one.
+func getMigrationSteps(fromVersion int) ([]migrationStep, error) {
+	currentVersion := coreutils.GetConfigVersion()
+	if fromVersion > currentVersion {
+		return nil, errorutils.CheckError(fmt.Errorf("the configuration version %d is newer than the version supported by this JFrog CLI (%d), please upgrade JFrog CLI", fromVersion, currentVersion))
+	}
+	var steps []migrationStep
+	for _, step := range migrationSteps {
+		if step.fromVersion >= fromVersion {
+			steps = append(steps, step)
+		}
+	}
+	if len(steps) != currentVersion-fromVersion {
+		return nil, errorutils.CheckError(fmt.Errorf("the configuration migration from version %d to version %d is incomplete", fromVersion, currentVersion))
+	}
+	return steps, nil
+}
+
+// Migrate the content of a configuration file to the current version.
+// Unless on dry-run, a backup of the JFrog home directory is created before migrating, and the migrated configuration is saved.
+func migrateConfig(content []byte, dryRun bool) (*ConfigV5, *MigrationResult, error) {
+	versionString, err := getVersion(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	version, err := strconv.Atoi(versionString)
+	if err != nil {
+		return nil, nil, errorutils.CheckError(fmt.Errorf("unexpected configuration version '%s'", versionString))
+	}
+	steps, err := getMigrationSteps(version)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &MigrationResult{FromVersion: version, ToVersion: coreutils.GetConfigVersion()}
+	if len(steps) > 0 && !dryRun {
+		if err = createHomeDirBackup(); err != nil {
+			return nil, nil, err
+		}
+	}
+	migrated := content
+	for _, step := range steps {
+		log.Debug(fmt.Sprintf("Migrating the configuration from version %d: %s.", step.fromVersion, step.description))
+		result.Steps = append(result.Steps, step.description)
+		if step.convert != nil {
+			if migrated, err = step.convert(migrated); err != nil {
+				return nil, nil, err
+			}
+		}
+		if step.apply != nil && !dryRun {
+			if err = step.apply(); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	config := new(ConfigV5)
+	if err = json.Unmarshal(migrated, &config); err != nil {
+		return nil, nil, errorutils.CheckError(err)
+	}
+	if len(steps) == 0 {
+		return config, result, nil
+	}
+	config.Version = strconv.Itoa(result.ToVersion)
+	if dryRun {
+		result.Diff, err = diffConfigs(content, config)
+		return config, result, err
+	}
+	// Saving also encrypts the secrets, if a master key is configured.
+	return config, result, saveConfig(config)
+}
+
+// Show how the configuration file would be migrated to the current version, without changing it or any other file.
+// If the configuration is already in the current version, the result has no steps.
+func DryRunConfigMigration() (*MigrationResult, error) {
+	content, err := getConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	result := &MigrationResult{FromVersion: coreutils.GetConfigVersion(), ToVersion: coreutils.GetConfigVersion()}
+	if len(content) == 0 {
+		return result, nil
+	}
+	_, result, err = migrateConfig(content, true)
+	return result, err
+}
+
+// Restore the JFrog home directory from the latest backup, which is created before the configuration is migrated.
+// Returns the path of the restored backup.
+func RollbackConfig() (string, error) {
+	lockFile, err := lock.CreateLock()
+	defer lockFile.Unlock()
+	if err != nil {
+		return "", err
+	}
+	backupPath, err := getLatestHomeDirBackup()
+	if err != nil {
+		return "", err
+	}
+	homeDir, err := coreutils.GetJfrogHomeDir()
+	if err != nil {
+		return "", err
+	}
+	// The configuration file of the current version is read before legacy ones, so it is removed unless the backup includes it.
+	// It is moved aside while the backup is restored, and moved back if the restore fails.
+	// After a successful restore, it is kept next to the restored configuration, so that the rollback can be undone.
+	confFilePath, err := getConfFilePath()
+	if err != nil {
+		return "", err
+	}
+	exists, err := fileutils.IsFileExists(confFilePath, false)
+	if err != nil {
+		return "", err
+	}
+	movedConfFilePath := ""
+	if exists {
+		movedConfFilePath = confFilePath + ".rollback"
+		if err = os.Rename(confFilePath, movedConfFilePath); err != nil {
+			return "", errorutils.CheckError(err)
+		}
+	}
+	log.Debug("Restoring the homedir backup from: " + backupPath)
+	if err = fileutils.CopyDir(backupPath, homeDir, true, nil); err != nil {
+		if movedConfFilePath != "" {
+			if restoreErr := os.Rename(movedConfFilePath, confFilePath); restoreErr != nil {
+				log.Error(fmt.Sprintf("Failed to restore the configuration file from %s: %s", movedConfFilePath, restoreErr.Error()))
+			}
+		}
+		return "", errorutils.CheckError(err)
+	}
+	if movedConfFilePath != "" {
+		log.Info("The configuration file which was replaced by the backup was moved to: " + movedConfFilePath)
+	}
+	return backupPath, nil
+}
+
+func getLatestHomeDirBackup() (string, error) {
+	backupDir, err := coreutils.GetJfrogBackupDir()
+	if err != nil {
+		return "", err
+	}
+	exists, err := fileutils.IsDirExists(backupDir, false)
+	if err != nil {
+		return "", err
+	}
+	var latestName string
+	var latestTime int64 = -1
+	if exists {
+		files, err := ioutil.ReadDir(backupDir)
+		if err != nil {
+			return "", errorutils.CheckError(err)
+		}
+		for _, file := range files {
+			if !file.IsDir() || !strings.HasPrefix(file.Name(), homeDirBackupPrefix) {
+				continue
+			}
+			backupTime, err := strconv.ParseInt(strings.TrimPrefix(file.Name(), homeDirBackupPrefix), 10, 64)
+			if err == nil && backupTime > latestTime {
+				latestName, latestTime = file.Name(), backupTime
+			}
+		}
+	}
+	if latestName == "" {
+		return "", errorutils.CheckError(errors.New("no configuration backup was found in " + backupDir))
+	}
+	return filepath.Join(backupDir, latestName), nil
+}
+
+var secretFieldPattern = regexp.MustCompile(`(?i)("(?:password|accessToken|refreshToken|sshPassphrase|apiKey)"\s*:\s*)"[^"]*"`)
+
+// Diff the original configuration content with the migrated configuration, both indented.
+func diffConfigs(original []byte, migrated *ConfigV5) ([]string, error) {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, original, "", "  "); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	migratedContent, err := migrated.getContent()
+	if err != nil {
+		return nil, err
+	}
+	maskSecrets := func(content []byte) []string {
+		return strings.Split(secretFieldPattern.ReplaceAllString(string(content), `$1"***"`), "\n")
+	}
+	return diffLines(maskSecrets(indented.Bytes()), maskSecrets(migratedContent)), nil
+}
+
+// A line diff, based on the longest common subsequence of the lines.
+func diffLines(before, after []string) []string {
+	// common[i][j] is the length of the longest common subsequence of before[i:] and after[j:].
+	common := make([][]int, len(before)+1)
+	for i := range common {
+		common[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+	var diff []string
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			diff = append(diff, " "+before[i])
+			i++
+			j++
+		case j == len(after) || i < len(before) && common[i+1][j] >= common[i][j+1]:
+			diff = append(diff, "-"+before[i])
+			i++
+		default:
+			diff = append(diff, "+"+after[j])
+			j++
+		}
+	}
+	return diff
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const configV4WithPassword = `{
+  "artifactory": [
+    {
+      "url": "http://localhost:8080/artifactory/",
+      "user": "user",
+      "password": "my-password",
+      "serverId": "` + DefaultServerId + `",
+      "isDefault": true
+    }
+  ],
+  "version": "4"
+}`
+
+func TestGetMigrationSteps(t *testing.T) {
+	currentVersion := coreutils.GetConfigVersion()
+	for version := 0; version <= currentVersion; version++ {
+		steps, err := getMigrationSteps(version)
+		assert.NoError(t, err)
+		// The steps must migrate to the current version one version at a time.
+		if assert.Len(t, steps, currentVersion-version) {
+			for i, step := range steps {
+				assert.Equal(t, version+i, step.fromVersion)
+				assert.NotEmpty(t, step.description)
+			}
+		}
+	}
+	_, err := getMigrationSteps(currentVersion + 1)
+	assert.Error(t, err)
+}
+
+func TestConvertConfigV2ToV3(t *testing.T) {
+	content, err := convertConfigV2toV3([]byte(`{"artifactory": [{"user": "ADMIN"}, {"user": "user"}], "version": "2"}`))
+	assert.NoError(t, err)
+	config := new(ConfigV4)
+	assert.NoError(t, json.Unmarshal(content, config))
+	if assert.Len(t, config.Artifactory, 2) {
+		assert.Equal(t, "admin", config.Artifactory[0].User)
+		assert.Equal(t, "user", config.Artifactory[1].User)
+	}
+}
+
+// Write a configuration file of version 4, which is named after its version.
+func writeConfigV4(t *testing.T) string {
+	path, err := getLegacyConfigFilePath(4)
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	assert.NoError(t, ioutil.WriteFile(path, []byte(configV4WithPassword), 0600))
+	return path
+}
+
+func TestDryRunConfigMigration(t *testing.T) {
+	tempDirPath, oldHomeDir := createTempEnv(t)
+	defer os.RemoveAll(tempDirPath)
+	defer os.Setenv(coreutils.HomeDir, oldHomeDir)
+	writeConfigV4(t)
+
+	result, err := DryRunConfigMigration()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.FromVersion)
+	assert.Equal(t, 5, result.ToVersion)
+	assert.Equal(t, []string{"Convert the Artifactory servers to JFrog platform servers"}, result.Steps)
+	assert.Contains(t, result.Diff, `-  "artifactory": [`)
+	assert.Contains(t, result.Diff, `+  "servers": [`)
+	assert.Contains(t, result.Diff, `+      "artifactoryUrl": "http://localhost:8080/artifactory/",`)
+	assert.Contains(t, result.Diff, `-  "version": "4"`)
+	assert.Contains(t, result.Diff, `+  "version": "5"`)
+	for _, line := range result.Diff {
+		assert.NotContains(t, line, "my-password")
+	}
+
+	// Nothing is changed on dry-run.
+	confFilePath, err := getConfFilePath()
+	assert.NoError(t, err)
+	assert.NoFileExists(t, confFilePath)
+	backupDir, err := coreutils.GetJfrogBackupDir()
+	assert.NoError(t, err)
+	assert.NoDirExists(t, backupDir)
+}
+
+func TestDryRunConfigMigrationCurrentVersion(t *testing.T) {
+	tempDirPath, oldHomeDir := createTempEnv(t)
+	defer os.RemoveAll(tempDirPath)
+	defer os.Setenv(coreutils.HomeDir, oldHomeDir)
+	assert.NoError(t, SaveServersConf([]*ServerDetails{{ServerId: DefaultServerId, IsDefault: true}}))
+
+	result, err := DryRunConfigMigration()
+	assert.NoError(t, err)
+	assert.Empty(t, result.Steps)
+	assert.Empty(t, result.Diff)
+}
+
+func TestRollbackConfig(t *testing.T) {
+	tempDirPath, oldHomeDir := createTempEnv(t)
+	defer os.RemoveAll(tempDirPath)
+	defer os.Setenv(coreutils.HomeDir, oldHomeDir)
+
+	_, err := RollbackConfig()
+	assert.EqualError(t, err, "no configuration backup was found in "+filepath.Join(tempDirPath, coreutils.JfrogBackupDirName))
+
+	legacyPath := writeConfigV4(t)
+	// Reading the configuration migrates it, after backing up the home directory.
+	servers, err := GetAllServersConfigs()
+	assert.NoError(t, err)
+	if assert.Len(t, servers, 1) {
+		assert.Equal(t, "http://localhost:8080/artifactory/", servers[0].ArtifactoryUrl)
+	}
+	confFilePath, err := getConfFilePath()
+	assert.NoError(t, err)
+	migratedContent, err := ioutil.ReadFile(confFilePath)
+	assert.NoError(t, err)
+
+	backupPath, err := RollbackConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tempDirPath, coreutils.JfrogBackupDirName), filepath.Dir(backupPath))
+	assert.NoFileExists(t, confFilePath)
+	content, err := ioutil.ReadFile(legacyPath)
+	assert.NoError(t, err)
+	assert.Equal(t, configV4WithPassword, string(content))
+	// The replaced configuration is kept, so that the rollback can be undone.
+	content, err = ioutil.ReadFile(confFilePath + ".rollback")
+	assert.NoError(t, err)
+	assert.Equal(t, string(migratedContent), string(content))
+}
+
+func TestRollbackConfigFailure(t *testing.T) {
+	tempDirPath, oldHomeDir := createTempEnv(t)
+	defer os.RemoveAll(tempDirPath)
+	defer os.Setenv(coreutils.HomeDir, oldHomeDir)
+	writeConfigV4(t)
+	_, err := GetAllServersConfigs()
+	assert.NoError(t, err)
+	confFilePath, err := getConfFilePath()
+	assert.NoError(t, err)
+	migratedContent, err := ioutil.ReadFile(confFilePath)
+	assert.NoError(t, err)
+
+	// A file of the backup can't be restored over a directory of the home directory.
+	backupPath, err := getLatestHomeDirBackup()
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(backupPath, "conflict"), []byte("content"), 0600))
+	assert.NoError(t, os.MkdirAll(filepath.Join(tempDirPath, "conflict", "dir"), 0700))
+
+	_, err = RollbackConfig()
+	assert.Error(t, err)
+	content, err := ioutil.ReadFile(confFilePath)
+	assert.NoError(t, err)
+	assert.Equal(t, string(migratedContent), string(content))
+	assert.NoFileExists(t, confFilePath+".rollback")
+}
+
+func TestDiffLines(t *testing.T) {
+	diff := diffLines([]string{"a", "b", "c", "d"}, []string{"a", "c", "e", "d", "f"})
+	assert.Equal(t, []string{" a", "-b", " c", "+e", " d", "+f"}, diff)
+	assert.Equal(t, []string{"+a"}, diffLines(nil, []string{"a"}))
+	assert.Equal(t, []string{"-a"}, diffLines([]string{"a"}, nil))
+}
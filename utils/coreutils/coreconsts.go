@@ -19,6 +19,7 @@ const (
 	JfrogLogsDirName         = "logs"
 	JfrogLockDirName         = "lock"
 	JfrogPluginsDirName      = "plugins"
+	JfrogSyncDirName         = "sync"
 
 	// Env
 	ErrorHandling      = "JFROG_CLI_ERROR_HANDLING"
@@ -230,3 +230,21 @@ func CreateLock() (Lock, error) {
 	}
 	return *lockFile, nil
 }
+
+// Create and acquire a lock in the provided directory, rather than in the lock directory of the JFrog home.
+// Such a lock only waits for other locks created in the same directory, which must not contain other files.
+func CreateLockInDir(dir string) (Lock, error) {
+	lockFile := new(Lock)
+	if err := fileutils.CreateDirIfNotExist(dir); err != nil {
+		return *lockFile, err
+	}
+	lockFile.currentTime = time.Now().UnixNano()
+	lockFile.pid = os.Getpid()
+	if err := lockFile.CreateFile(dir, lockFile.pid); err != nil {
+		return *lockFile, err
+	}
+	if err := lockFile.Lock(); err != nil {
+		return *lockFile, errorutils.CheckError(err)
+	}
+	return *lockFile, nil
+}